@@ -14,10 +14,15 @@ var aliasesCmd = &cobra.Command{
 	Use:   "aliases",
 	Short: "List available playbook aliases",
 	Long: `List all available playbook aliases that can be used with --playbooks.
-These are the built-in playbooks located in the playbooks/ directory.
+These are the playbooks in the playbooks/ directory, or in the directory given
+with --playbook-dir or the playbooks_dir config key.
 
 Examples:
   autowsl aliases
+
+  # Use a central playbook library, for this command or permanently
+  autowsl aliases --playbook-dir ~/my-playbooks
+  autowsl config set playbooks_dir ~/my-playbooks
   autowsl install "Ubuntu 22.04 LTS" --playbooks curl
   autowsl provision ubuntu-2204 --playbooks curl,default`,
 	RunE: runAliases,
@@ -28,12 +33,7 @@ func init() {
 }
 
 func runAliases(cmd *cobra.Command, args []string) error {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-
-	playbooksDir := filepath.Join(cwd, "playbooks")
+	playbooksDir := playbooksDirPath()
 
 	// Check if playbooks directory exists
 	if _, err := os.Stat(playbooksDir); os.IsNotExist(err) {
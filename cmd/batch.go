@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/batch"
+	"github.com/yuanjua/autowsl/internal/metadata"
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var batchStatusOutput string
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Install and provision several distributions from a spec file",
+	Long: `Install and provision several distributions from a YAML spec file. The spec is
+a list of distributions:
+
+  - version: "Ubuntu 22.04 LTS"
+    name: ubuntu-dev
+    path: D:\WSL\ubuntu-dev     # optional
+    wsl_version: 2              # optional, default 2
+    playbooks: [git, ./dev.yml] # optional
+
+Examples:
+  autowsl batch apply team.yml
+  autowsl batch status team.yml`,
+}
+
+var batchApplyCmd = &cobra.Command{
+	Use:   "apply <spec.yml>",
+	Short: "Install and provision the distributions of a spec",
+	Long: `Install and provision every distribution of a spec, one after another.
+Distributions that already exist are skipped, so apply can be re-run after
+adding entries or fixing a failure.
+
+Examples:
+  autowsl batch apply team.yml
+
+  # Show what would be done
+  autowsl batch apply team.yml --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBatchApply,
+}
+
+var batchStatusCmd = &cobra.Command{
+	Use:   "status <spec.yml>",
+	Short: "Compare a spec against the installed distributions",
+	Long: `Show which distributions of a spec are installed. An installed distribution
+whose recorded catalog version differs from the spec is reported as a version
+mismatch.
+
+Examples:
+  autowsl batch status team.yml
+  autowsl batch status team.yml -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBatchStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.AddCommand(batchApplyCmd)
+	batchCmd.AddCommand(batchStatusCmd)
+	batchStatusCmd.Flags().StringVarP(&batchStatusOutput, "output", "o", "table", "Output format: table, json, or yaml")
+}
+
+func runBatchApply(cmd *cobra.Command, args []string) error {
+	entries, err := batch.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for i, e := range entries {
+		fmt.Printf("\n[%d/%d] %s (%s)\n", i+1, len(entries), e.Name, e.Version)
+		fmt.Println(strings.Repeat("=", 60))
+
+		exists, err := wsl.IsDistroInstalled(e.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check existing distributions: %w", err)
+		}
+		if exists {
+			fmt.Printf("⚠ Distribution '%s' already exists, skipping\n", e.Name)
+			continue
+		}
+
+		if err := applyBatchEntry(e); err != nil {
+			fmt.Printf("\n⚠ %s: %v\n", e.Name, err)
+			failed = append(failed, e.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("batch completed with failures: %s", strings.Join(failed, ", "))
+	}
+	fmt.Printf("\n✓ Applied %s\n", args[0])
+	return nil
+}
+
+// applyBatchEntry installs one distribution of a spec with the install
+// command's logic, then provisions it
+func applyBatchEntry(e batch.Entry) error {
+	installName = e.Name
+	installPath = e.Path
+	installWSLVersion = e.WSLVersion
+	installPlaybooks = nil
+	installFromTar = ""
+	installURL = ""
+
+	if err := runInstall(installCmd, []string{e.Version}); err != nil {
+		return err
+	}
+	if len(e.Playbooks) == 0 {
+		return nil
+	}
+	return runProvisioningPipeline(ProvisioningPipelineOptions{
+		DistroName:     e.Name,
+		PlaybookInputs: e.Playbooks,
+		MaxRetries:     installMaxRetries,
+
+		ValidatePlaybooks: true,
+	})
+}
+
+func runBatchStatus(cmd *cobra.Command, args []string) error {
+	if batchStatusOutput != "table" && batchStatusOutput != "json" && batchStatusOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", batchStatusOutput)
+	}
+
+	entries, err := batch.Load(args[0])
+	if err != nil {
+		return err
+	}
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+
+	paths, _ := wsl.InstallPaths()
+	installed := make(map[string]batch.Installed, len(distros))
+	for _, d := range distros {
+		info := batch.Installed{State: d.State}
+		if path, ok := paths[d.Name]; ok {
+			if m, err := metadata.Read(path); err == nil {
+				info.DistroVersion = m.DistroVersion
+			}
+		}
+		installed[d.Name] = info
+	}
+	statuses := batch.Compare(entries, installed)
+
+	switch batchStatusOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(statuses); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSTATE\tSTATUS")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, s.Version, orDash(s.State), s.Status)
+	}
+	return w.Flush()
+}
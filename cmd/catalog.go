@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/config"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/winget"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	catalogOutput        string
+	catalogSearchGroup   string
+	catalogVersionsGroup string
+	catalogUpdateURL     string
+)
+
+// remoteCatalogMaxAge is how long the cached remote catalog is used before
+// it is downloaded again
+const remoteCatalogMaxAge = 24 * time.Hour
+
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Browse the distribution catalog",
+	Long: `Browse the distributions autowsl can install without starting the install flow.
+Entries from --catalog are included.
+
+Set catalog_url in the config file to merge a remote JSON catalog (same schema
+as --catalog) into the built-in one. It is cached in ~/.autowsl and downloaded
+again once a day, or on 'autowsl catalog update'. It is only loaded by commands
+that read the catalog, and an expired copy is used when the download fails.
+
+Examples:
+  autowsl catalog list
+  autowsl catalog search debian
+  autowsl catalog search 24.04 --group ubuntu
+  autowsl catalog show "Ubuntu 22.04 LTS" --output json
+
+  # Iterate over the groups in a script
+  autowsl catalog groups --output json | jq -r '.[]'
+  autowsl catalog versions --group debian
+
+  # Use a remote catalog and download it now
+  autowsl config set catalog_url https://example.com/distros.json
+  autowsl catalog update
+
+  # Show which installed distributions came from winget / the Microsoft Store
+  autowsl catalog installed`,
+}
+
+var catalogListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all distributions in the catalog",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useRemoteCatalog()
+		return printCatalog(distro.GetAllDistros())
+	},
+}
+
+var catalogSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the catalog by group, version, or package ID",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useRemoteCatalog()
+		results := distro.Search(args[0], catalogSearchGroup)
+		if len(results) == 0 && catalogOutput == "table" {
+			fmt.Printf("No distributions match '%s'\n", args[0])
+			if matches := distro.ClosestMatches(args[0], 3); len(matches) > 0 {
+				fmt.Println("\nClosest matches:")
+				for _, m := range matches {
+					fmt.Printf("  %s (%s)\n", m.Distro.Version, m.Distro.PackageID)
+				}
+			}
+			return nil
+		}
+		return printCatalog(results)
+	},
+}
+
+var catalogGroupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "List the distribution groups in the catalog",
+	Args:  cobra.NoArgs,
+	RunE:  runCatalogGroups,
+}
+
+var catalogVersionsCmd = &cobra.Command{
+	Use:   "versions --group <group>",
+	Short: "List the versions of a distribution group",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useRemoteCatalog()
+		group, err := distro.FindGroup(catalogVersionsGroup)
+		if err != nil {
+			return err
+		}
+		return printCatalog(distro.GetDistrosByGroup(group))
+	},
+}
+
+var catalogUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download the remote catalog again",
+	Long: `Download the remote catalog from catalog_url in the config file, or from
+--url, and refresh the cached copy in ~/.autowsl.`,
+	Args: cobra.NoArgs,
+	RunE: runCatalogUpdate,
+}
+
+var catalogInstalledCmd = &cobra.Command{
+	Use:   "installed",
+	Short: "Show which installed distributions were installed with winget",
+	Long: `List the installed WSL distributions and whether each one matches a catalog
+package that 'winget list' reports as installed (e.g. from the Microsoft Store)
+or was imported manually. Distributions are matched to packages by name.`,
+	Args: cobra.NoArgs,
+	RunE: runCatalogInstalled,
+}
+
+var catalogShowCmd = &cobra.Command{
+	Use:   "show <version>",
+	Short: "Show all details of a catalog entry",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCatalogShow,
+}
+
+func init() {
+	rootCmd.AddCommand(catalogCmd)
+	catalogCmd.AddCommand(catalogListCmd)
+	catalogCmd.AddCommand(catalogSearchCmd)
+	catalogCmd.AddCommand(catalogShowCmd)
+	catalogCmd.AddCommand(catalogGroupsCmd)
+	catalogCmd.AddCommand(catalogVersionsCmd)
+	catalogCmd.AddCommand(catalogUpdateCmd)
+	catalogCmd.AddCommand(catalogInstalledCmd)
+	catalogCmd.PersistentFlags().StringVarP(&catalogOutput, "output", "o", "table", "Output format: table or json")
+	catalogSearchCmd.Flags().StringVar(&catalogSearchGroup, "group", "", "Only search this group (e.g. ubuntu)")
+	catalogVersionsCmd.Flags().StringVar(&catalogVersionsGroup, "group", "", "Group to list (e.g. ubuntu)")
+	catalogUpdateCmd.Flags().StringVar(&catalogUpdateURL, "url", "", "Catalog URL to download (default: catalog_url from the config file)")
+	_ = catalogVersionsCmd.MarkFlagRequired("group")
+	_ = catalogVersionsCmd.RegisterFlagCompletionFunc("group", completeCatalogGroups)
+	_ = catalogSearchCmd.RegisterFlagCompletionFunc("group", completeCatalogGroups)
+	catalogShowCmd.ValidArgsFunction = completeCatalogVersions
+}
+
+// checkCatalogOutput validates --output for the catalog subcommands
+func checkCatalogOutput() error {
+	if catalogOutput != "table" && catalogOutput != "json" {
+		return fmt.Errorf("invalid --output %q (must be table or json)", catalogOutput)
+	}
+	return nil
+}
+
+// printCatalog prints catalog entries as a table or JSON
+func printCatalog(distros []distro.Distro) error {
+	if err := checkCatalogOutput(); err != nil {
+		return err
+	}
+	if distros == nil {
+		distros = []distro.Distro{}
+	}
+
+	if catalogOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(distros)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tVERSION\tPACKAGE ID\tARCH")
+	fmt.Fprintln(w, "-----\t-------\t----------\t----")
+	for _, d := range distros {
+		packageID := d.PackageID
+		if packageID == "" {
+			packageID = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Group, d.Version, packageID, d.Architecture)
+	}
+	return w.Flush()
+}
+
+// runCatalogGroups prints the catalog groups with their number of versions.
+// The JSON form is a plain array of group names.
+func runCatalogGroups(cmd *cobra.Command, args []string) error {
+	if err := checkCatalogOutput(); err != nil {
+		return err
+	}
+	useRemoteCatalog()
+	groups := distro.GetGroups()
+	if groups == nil {
+		groups = []string{}
+	}
+
+	if catalogOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(groups)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tVERSIONS")
+	fmt.Fprintln(w, "-----\t--------")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s\t%d\n", g, len(distro.GetDistrosByGroup(g)))
+	}
+	return w.Flush()
+}
+
+// completeCatalogGroups completes the --group flag with catalog group names
+func completeCatalogGroups(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return distro.GetGroups(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func runCatalogShow(cmd *cobra.Command, args []string) error {
+	if err := checkCatalogOutput(); err != nil {
+		return err
+	}
+
+	d, err := selectDistroByVersion(args[0])
+	if err != nil {
+		return err
+	}
+
+	if catalogOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+
+	fmt.Printf("Group:        %s\n", d.Group)
+	fmt.Printf("Version:      %s\n", d.Version)
+	fmt.Printf("Architecture: %s\n", d.Architecture)
+	if d.PackageID != "" {
+		fmt.Printf("Package ID:   %s\n", d.PackageID)
+	}
+	if d.URL != "" {
+		fmt.Printf("URL:          %s\n", d.URL)
+	}
+	if d.SHA256 != "" {
+		fmt.Printf("SHA256:       %s\n", d.SHA256)
+	}
+	return nil
+}
+
+// remoteCatalogLoaded is set once useRemoteCatalog has run for this command
+var remoteCatalogLoaded bool
+
+// useRemoteCatalog merges the catalog_url catalog into the built-in one the
+// first time a command reads the catalog, so commands that never look at it
+// (completion, version, remove, ...) don't wait for the download
+func useRemoteCatalog() {
+	if remoteCatalogLoaded {
+		return
+	}
+	remoteCatalogLoaded = true
+	if url := config.Get().CatalogURL; url != "" && !catalogReplace {
+		loadRemoteCatalog(url)
+	}
+}
+
+// loadRemoteCatalog merges the remote catalog into the built-in one. A
+// download failure only warns so commands keep working offline, using an
+// expired cached copy when there is one.
+func loadRemoteCatalog(url string) {
+	distros, err := distro.FetchRemoteCatalog(url, distro.DefaultCacheDir(), remoteCatalogMaxAge)
+	if err != nil {
+		cached, cachedAt, ok := distro.CachedRemoteCatalog(url, distro.DefaultCacheDir())
+		if !ok {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: %v; using the built-in catalog\n", err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "⚠ Warning: %v; using the catalog cached on %s\n", err, cachedAt.Format("2006-01-02 15:04"))
+		distros = cached
+	}
+	distro.SetRemoteCatalog(distros)
+}
+
+func runCatalogUpdate(cmd *cobra.Command, args []string) error {
+	url := catalogUpdateURL
+	if url == "" {
+		url = config.Get().CatalogURL
+	}
+	if url == "" {
+		return fmt.Errorf("no catalog URL; pass --url or set one with 'autowsl config set catalog_url <url>'")
+	}
+
+	fmt.Printf("→ Downloading catalog from %s...\n", url)
+	distros, err := distro.FetchRemoteCatalog(url, distro.DefaultCacheDir(), 0)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %d distribution(s) cached in %s\n", len(distros), filepath.Join(distro.DefaultCacheDir(), distro.RemoteCatalogFile))
+	if url != config.Get().CatalogURL {
+		fmt.Printf("  Set catalog_url to use this catalog: autowsl config set catalog_url %s\n", url)
+	}
+	return nil
+}
+
+// installedCatalogDistro is an installed distribution with where it came from
+type installedCatalogDistro struct {
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	WSLVersion string `json:"wslVersion"`
+	Source     string `json:"source"` // winget, imported, or unknown
+	PackageID  string `json:"packageId,omitempty"`
+	Catalog    string `json:"catalogVersion,omitempty"`
+}
+
+func runCatalogInstalled(cmd *cobra.Command, args []string) error {
+	if err := checkCatalogOutput(); err != nil {
+		return err
+	}
+
+	installed, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list installed distributions: %w", err)
+	}
+
+	useRemoteCatalog()
+	var packages []winget.WingetDistro
+	mgr := winget.NewManager(tempDirPath())
+	wingetOK := mgr.IsWingetAvailable()
+	if wingetOK {
+		packages, err = mgr.ListInstalledWingetDistros()
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, "⚠ Warning: winget is not available; the source of each distribution is unknown")
+	}
+
+	result := make([]installedCatalogDistro, 0, len(installed))
+	for _, d := range installed {
+		entry := installedCatalogDistro{Name: d.Name, State: d.State, WSLVersion: d.Version, Source: "imported"}
+		if !wingetOK {
+			entry.Source = "unknown"
+		} else if pkg := matchWingetDistro(d.Name, packages); pkg != nil {
+			entry.Source = "winget"
+			entry.PackageID = pkg.PackageID
+			entry.Catalog = pkg.Version
+		}
+		result = append(result, entry)
+	}
+
+	if catalogOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(result) == 0 {
+		fmt.Println("No WSL distributions installed")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATE\tWSL\tSOURCE\tPACKAGE ID")
+	fmt.Fprintln(w, "----\t-----\t---\t------\t----------")
+	for _, e := range result {
+		packageID := e.PackageID
+		if packageID == "" {
+			packageID = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Name, e.State, e.WSLVersion, e.Source, packageID)
+	}
+	return w.Flush()
+}
+
+// matchWingetDistro finds the winget package a distribution was installed
+// from by comparing names without punctuation: an exact match wins over a
+// package whose name starts with the distribution name ("Ubuntu-22.04" and
+// "Ubuntu 22.04.5 LTS").
+func matchWingetDistro(name string, packages []winget.WingetDistro) *winget.WingetDistro {
+	key := nameKey(name)
+	if key == "" {
+		return nil
+	}
+	var prefix *winget.WingetDistro
+	for i, p := range packages {
+		for _, candidate := range []string{nameKey(p.Name), nameKey(p.Version)} {
+			if candidate == key {
+				return &packages[i]
+			}
+			if prefix == nil && strings.HasPrefix(candidate, key) {
+				prefix = &packages[i]
+			}
+		}
+	}
+	return prefix
+}
+
+// nameKey lowercases name and drops everything but letters and digits
+func nameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Manage trusted CA certificates inside a distribution",
+	Long: `Manage the CA certificates trusted inside a distribution, e.g. to trust a
+corporate root CA. The trust store location and update command are chosen
+from the distribution's package manager.
+
+Examples:
+  # Trust a corporate root CA (PEM or DER)
+  autowsl cert import ubuntu-2204 C:\certs\corp-root-ca.crt
+
+  # Show the custom CAs installed in the distribution
+  autowsl cert list ubuntu-2204`,
+}
+
+var certImportCmd = &cobra.Command{
+	Use:   "import <distro-name> <cert-file>",
+	Short: "Add a CA certificate to a distribution's trust store",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runCertImport,
+}
+
+var certListCmd = &cobra.Command{
+	Use:   "list <distro-name>",
+	Short: "List the custom CA certificates in a distribution",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCertList,
+}
+
+func init() {
+	rootCmd.AddCommand(certCmd)
+	certCmd.AddCommand(certImportCmd)
+	certCmd.AddCommand(certListCmd)
+	certImportCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveDefault // certificate file
+		}
+		return completeInstalledDistros(cmd, args, toComplete)
+	}
+	certListCmd.ValidArgsFunction = completeInstalledDistros
+}
+
+func runCertImport(cmd *cobra.Command, args []string) error {
+	distroName, certPath := args[0], args[1]
+	if err := checkDistroExists(distroName); err != nil {
+		return err
+	}
+
+	installed, err := ansible.ImportCertificate(distroName, certPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Installed %s in '%s' as %s\n", certPath, distroName, installed)
+	return nil
+}
+
+func runCertList(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+	if err := checkDistroExists(distroName); err != nil {
+		return err
+	}
+
+	certs, err := ansible.ListCertificates(distroName)
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		fmt.Printf("No custom CA certificates in '%s'\n", distroName)
+		return nil
+	}
+	for _, c := range certs {
+		fmt.Println(c)
+	}
+	return nil
+}
+
+// checkDistroExists returns an error listing the installed distributions when
+// distroName is not one of them. Dry runs skip the check.
+func checkDistroExists(distroName string) error {
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/config"
+	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/ui"
+)
+
+var (
+	cleanRecursive bool
+	cleanForce     bool
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean [directory]",
+	Short: "Remove leftover .autowsl_tmp directories",
+	Long: `Remove .autowsl_tmp directories left behind by failed installs or --keep-tar.
+Scans the given directory (default: current directory) and, with --recursive,
+all of its subdirectories. Directories locked by a running import are skipped.
+
+Examples:
+  # Clean the current directory
+  autowsl clean
+
+  # Scan a whole tree and delete without asking
+  autowsl clean D:\WSL --recursive --force`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+	cleanCmd.Flags().BoolVarP(&cleanRecursive, "recursive", "r", false, "Also scan subdirectories")
+	cleanCmd.Flags().BoolVarP(&cleanForce, "force", "f", false, "Delete without asking for confirmation")
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	root, _ := os.Getwd()
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	dirs, err := extractor.FindTempDirs(root, cleanRecursive)
+	if err != nil {
+		return err
+	}
+
+	// Include the temp_dir from the config file when it lives outside the scanned tree
+	if configured := config.Get().TempDir; configured != "" && !containsPath(dirs, configured) {
+		if info, err := os.Stat(configured); err == nil && info.IsDir() {
+			dirs = append(dirs, configured)
+		}
+	}
+
+	if len(dirs) == 0 {
+		fmt.Println("No .autowsl_tmp directories found.")
+		return nil
+	}
+
+	var safe []string
+	var safeSize int64
+	sizes := make(map[string]int64)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "PATH\tSIZE\tSTATUS")
+	fmt.Fprintln(w, "----\t----\t------")
+	for _, dir := range dirs {
+		size, err := extractor.DirSize(dir)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t?\tunreadable (%v)\n", dir, err)
+			continue
+		}
+		sizes[dir] = size
+
+		status := "safe to delete"
+		if extractor.IsLocked(dir) {
+			status = "in use by a running import"
+		} else {
+			safe = append(safe, dir)
+			safeSize += size
+		}
+		fmt.Fprintf(w, "%s\t%.2f MB\t%s\n", dir, float64(size)/1024/1024, status)
+	}
+	w.Flush()
+	fmt.Println()
+
+	if len(safe) == 0 {
+		fmt.Println("Nothing to clean.")
+		return nil
+	}
+
+	if !cleanForce {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Delete %d director(ies), %.2f MB", len(safe), float64(safeSize)/1024/1024),
+			IsConfirm: true,
+		}
+		if _, err := ui.Prompt(prompt); err != nil {
+			fmt.Println("Clean cancelled")
+			return nil
+		}
+	}
+
+	var reclaimed int64
+	for _, dir := range safe {
+		if err := extractor.CleanupTempDir(dir); err != nil {
+			fmt.Printf("  ⚠ Warning: Failed to remove %s: %v\n", dir, err)
+			continue
+		}
+		reclaimed += sizes[dir]
+		fmt.Printf("  ✓ Removed %s\n", dir)
+	}
+
+	fmt.Printf("\nReclaimed %.2f MB\n", float64(reclaimed)/1024/1024)
+	return nil
+}
+
+// containsPath reports whether paths includes target, comparing absolute paths
+func containsPath(paths []string, target string) bool {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	for _, p := range paths {
+		if absP, err := filepath.Abs(p); err == nil && absP == absTarget {
+			return true
+		}
+	}
+	return false
+}
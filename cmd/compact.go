@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var compactCmd = &cobra.Command{
+	Use:   "compact [distro-name]",
+	Short: "Reclaim unused disk space from a WSL 2 distribution",
+	Long: `Shrink the virtual disk (ext4.vhdx) of a WSL 2 distribution.
+The distribution is stopped first. On Windows build 22557 and later the disk is
+switched to sparse mode so freed space is returned automatically; on older
+builds it is compacted with diskpart, which must be run from an elevated prompt.
+
+Examples:
+  # Interactive mode - select from installed distros
+  autowsl compact
+
+  # Compact a specific distribution
+  autowsl compact ubuntu-2204-lts`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCompact,
+}
+
+func init() {
+	rootCmd.AddCommand(compactCmd)
+}
+
+func runCompact(cmd *cobra.Command, args []string) error {
+	var distroName string
+	if len(args) > 0 {
+		distroName = args[0]
+	} else {
+		var err error
+		distroName, err = selectInstalledDistroInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	if err := system.CheckFeature(system.FeatureSparseVHD); err != nil {
+		fmt.Printf("⚠ %v.\n  The disk will be compacted with diskpart instead, which needs an elevated prompt.\n\n", err)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("'%s' will be stopped before compacting. Continue", distroName),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Compact cancelled")
+		return nil
+	}
+
+	vhdPath, vhdErr := wsl.VHDPath(distroName)
+	var sizeBefore int64
+	if vhdErr == nil {
+		sizeBefore, _ = wsl.GetVHDSize(vhdPath)
+	}
+
+	fmt.Printf("\nCompacting '%s'...\n", distroName)
+	if err := wsl.Compact(distroName); err != nil {
+		return fmt.Errorf("failed to compact distribution: %w", err)
+	}
+
+	fmt.Printf("\nSuccessfully compacted '%s'\n", distroName)
+	if vhdErr != nil {
+		fmt.Printf("VHD size: unknown (%v)\n", vhdErr)
+		return nil
+	}
+
+	sizeAfter, err := wsl.GetVHDSize(vhdPath)
+	if err != nil {
+		return nil
+	}
+	fmt.Printf("VHD:    %s\n", vhdPath)
+	fmt.Printf("Before: %.2f MB\n", float64(sizeBefore)/1024/1024)
+	fmt.Printf("After:  %.2f MB\n", float64(sizeAfter)/1024/1024)
+	if sizeBefore > sizeAfter {
+		fmt.Printf("Saved:  %.2f MB\n", float64(sizeBefore-sizeAfter)/1024/1024)
+	}
+
+	return nil
+}
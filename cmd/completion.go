@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a shell completion script for autowsl.
+
+Examples:
+  # Bash (current session)
+  source <(autowsl completion bash)
+
+  # Zsh
+  autowsl completion zsh > "${fpath[1]}/_autowsl"
+
+  # Fish
+  autowsl completion fish > ~/.config/fish/completions/autowsl.fish
+
+  # PowerShell (add to your $PROFILE to load on startup)
+  autowsl completion powershell | Out-String | Invoke-Expression`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	for _, c := range []*cobra.Command{shellCmd, provisionCmd, copyCmd, renameCmd, runCmd, setDefaultCmd, removeCmd, backupCmd, convertCmd, compactCmd, moveCmd, inspectCmd, updateCmd, statusCmd, packagesListCmd, startCmd, stopCmd, execCmd} {
+		c.ValidArgsFunction = completeInstalledDistros
+	}
+	installCmd.ValidArgsFunction = completeCatalogVersions
+	downloadCmd.ValidArgsFunction = completeCatalogVersions
+}
+
+// completeInstalledDistros completes the first argument with installed distro names
+func completeInstalledDistros(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return []string{}, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(distros))
+	for _, d := range distros {
+		names = append(names, d.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCatalogVersions completes the first argument with catalog version names
+func completeCatalogVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	distros := distro.GetAllDistros()
+	versions := make([]string, 0, len(distros))
+	for _, d := range distros {
+		versions = append(versions, d.Version+"\t"+d.Group)
+	}
+	return versions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePlaybookAliases completes --playbooks with aliases from the playbooks directory
+func completePlaybookAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries, err := os.ReadDir(playbooksDirPath())
+	if err != nil {
+		return []string{}, cobra.ShellCompDirectiveDefault
+	}
+
+	var aliases []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
+			continue
+		}
+		aliases = append(aliases, strings.TrimSuffix(strings.TrimSuffix(name, ".yml"), ".yaml"))
+	}
+	// Keep file completion available since playbooks can also be local paths
+	return aliases, cobra.ShellCompDirectiveDefault
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write autowsl defaults",
+	Long: `Read and write default settings stored in the autowsl config file
+(~/.autowsl.yml, or %APPDATA%\autowsl\config.yml on Windows).
+Values from the config file are used whenever the matching flag is not given.
+Each key can also be set with an AUTOWSL_<KEY> environment variable, which
+takes precedence over the file: flags > environment > config file > defaults.
+
+Keys:
+  default_wsl_version    WSL version for install/copy/import (1 or 2)
+  default_install_path   Directory new distributions are installed under
+  playbooks_dir          Directory searched for playbook aliases
+  keep_tar               Keep the extracted tar file after install/import
+  max_retries            Retries for transient network failures
+  temp_dir               Scratch directory for downloads and exports
+  history_limit          Playbook runs kept by 'autowsl history' (default 1000)
+  no_cleanup             Keep the temp directory after a run (for debugging)
+  catalog_url            Remote JSON catalog merged into the built-in one
+                         (cached by 'autowsl catalog update')
+
+Environment variables:
+  AUTOWSL_DEFAULT_WSL_VERSION, AUTOWSL_WSL_VERSION
+  AUTOWSL_DEFAULT_INSTALL_PATH, AUTOWSL_INSTALL_PATH
+  AUTOWSL_PLAYBOOKS_DIR, AUTOWSL_KEEP_TAR, AUTOWSL_MAX_RETRIES,
+  AUTOWSL_TEMP_DIR, AUTOWSL_HISTORY_LIMIT, AUTOWSL_NO_CLEANUP,
+  AUTOWSL_CATALOG_URL
+
+Examples:
+  autowsl config set default_install_path D:\WSL
+  autowsl config get default_install_path`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a value from the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Write a value to the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, err := config.GetValue(strings.ToLower(args[0]))
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key := strings.ToLower(args[0])
+	if err := config.SetValue(key, args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("Set %s = %s (%s)\n", key, args[1], config.Path())
+	return nil
+}
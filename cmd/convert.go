@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var convertVersion int
+
+var convertCmd = &cobra.Command{
+	Use:   "convert <distro-name>",
+	Short: "Convert a distribution between WSL 1 and WSL 2",
+	Long: `Convert an installed distribution to another WSL version with 'wsl --set-version'.
+Conversion rewrites the whole filesystem and can take several minutes.
+
+Examples:
+  # Convert a WSL 1 distribution to WSL 2
+  autowsl convert ubuntu-2204-lts --version 2
+
+  # Convert back to WSL 1
+  autowsl convert ubuntu-2204-lts --version 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvert,
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	convertCmd.Flags().IntVar(&convertVersion, "version", 2, "Target WSL version (1 or 2)")
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+
+	if convertVersion != 1 && convertVersion != 2 {
+		return fmt.Errorf("invalid --version %d (must be 1 or 2)", convertVersion)
+	}
+
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+
+	currentVersion := ""
+	for _, d := range distros {
+		if d.Name == distroName {
+			currentVersion = d.Version
+		}
+	}
+	if currentVersion == "" && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+	if currentVersion == fmt.Sprint(convertVersion) {
+		fmt.Printf("'%s' is already using WSL %d, nothing to do\n", distroName, convertVersion)
+		return nil
+	}
+
+	if convertVersion == 1 {
+		fmt.Println("⚠ Warning: WSL 1 has much slower filesystem performance for Linux files")
+		fmt.Println("  and lacks features such as systemd and full syscall compatibility.")
+	}
+
+	fmt.Printf("\nConverting '%s' to WSL %d...\n", distroName, convertVersion)
+	fmt.Println("This may take several minutes depending on the size of your distribution...")
+
+	if err := wsl.ConvertVersion(distroName, convertVersion); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSuccessfully converted '%s' to WSL %d\n", distroName, convertVersion)
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/ui"
 	"github.com/yuanjua/autowsl/internal/wsl"
 )
 
@@ -67,7 +69,7 @@ func runCopy(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to check distribution: %w", err)
 		}
-		if !exists {
+		if !exists && !dryRun {
 			return fmt.Errorf("source distribution '%s' does not exist", sourceDistro)
 		}
 	}
@@ -81,7 +83,7 @@ func runCopy(cmd *cobra.Command, args []string) error {
 				Label:   "New distribution name",
 				Default: defaultName,
 			}
-			if customName, err := namePrompt.Run(); err != nil {
+			if customName, err := ui.Prompt(namePrompt); err != nil {
 				return fmt.Errorf("failed to get distribution name: %w", err)
 			} else {
 				newName = customName
@@ -91,6 +93,10 @@ func runCopy(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err := wsl.ValidateDistroName(newName); err != nil {
+		return err
+	}
+
 	// Check if new distro name already exists
 	exists, err := wsl.IsDistroInstalled(newName)
 	if err != nil {
@@ -103,14 +109,13 @@ func runCopy(cmd *cobra.Command, args []string) error {
 	// Determine installation path
 	newPath := copyPath
 	if newPath == "" {
-		cwd, _ := os.Getwd()
-		newPath = filepath.Join(cwd, "wsl-distros", newName)
+		newPath = filepath.Join(defaultInstallRoot(), newName)
 		if isInteractive {
 			pathPrompt := promptui.Prompt{
 				Label:   "Installation path",
 				Default: newPath,
 			}
-			if customPath, err := pathPrompt.Run(); err != nil {
+			if customPath, err := ui.Prompt(pathPrompt); err != nil {
 				return fmt.Errorf("failed to get installation path: %w", err)
 			} else {
 				newPath = customPath
@@ -134,11 +139,17 @@ func runCopy(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s\n\n", strings.Repeat("=", 60))
 
 	// Create temporary directory for export
-	cwd, _ := os.Getwd()
-	tempDir := filepath.Join(cwd, ".autowsl_tmp")
-	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		return fmt.Errorf("failed to create temp directory '%s': %w", tempDir, err)
+	tempDir := tempDirPath()
+	if !dryRun {
+		if err := os.MkdirAll(tempDir, 0755); err != nil {
+			return fmt.Errorf("failed to create temp directory '%s': %w", tempDir, err)
+		}
+	}
+	releaseLock, err := extractor.AcquireLock(tempDir)
+	if err != nil {
+		return err
 	}
+	defer releaseLock()
 
 	// Temporary tar file path
 	tempTarPath := filepath.Join(tempDir, fmt.Sprintf("%s-export.tar", sourceDistro))
@@ -148,14 +159,18 @@ func runCopy(cmd *cobra.Command, args []string) error {
 	fmt.Println("  This may take a while depending on the size of your distribution...")
 
 	if err := wsl.Export(sourceDistro, tempTarPath); err != nil {
-		_ = os.RemoveAll(tempDir)
+		_ = cleanupTempDir(tempDir)
 		return fmt.Errorf("failed to export distribution: %w", err)
 	}
 
-	// Get file size
-	fileInfo, _ := os.Stat(tempTarPath)
-	sizeInMB := float64(fileInfo.Size()) / 1024 / 1024
-	fmt.Printf("  ✓ Export completed (%.2f MB)\n\n", sizeInMB)
+	if dryRun {
+		fmt.Println()
+	} else {
+		// Get file size
+		fileInfo, _ := os.Stat(tempTarPath)
+		sizeInMB := float64(fileInfo.Size()) / 1024 / 1024
+		fmt.Printf("  ✓ Export completed (%.2f MB)\n\n", sizeInMB)
+	}
 
 	// Import to new name
 	fmt.Printf("→ Importing to WSL as '%s'...\n", newName)
@@ -167,19 +182,14 @@ func runCopy(cmd *cobra.Command, args []string) error {
 	}
 
 	if err := wsl.Import(importOpts); err != nil {
-		_ = os.RemoveAll(tempDir)
+		_ = cleanupTempDir(tempDir)
 		return fmt.Errorf("failed to import distribution '%s' to '%s': %w", newName, newPath, err)
 	}
 
 	fmt.Println("  ✓ Import completed successfully")
 
 	// Cleanup temporary files
-	fmt.Println("\n→ Cleaning up temporary files...")
-	if err := os.RemoveAll(tempDir); err != nil {
-		fmt.Printf("  ⚠ Warning: Failed to cleanup temp directory: %v\n", err)
-	} else {
-		fmt.Println("  ✓ Cleanup completed")
-	}
+	finishTempDir(tempDir)
 
 	// Print success message
 	fmt.Println("\n" + strings.Repeat("=", 60))
@@ -189,6 +199,9 @@ func runCopy(cmd *cobra.Command, args []string) error {
 	fmt.Printf("New Name: %s\n", newName)
 	fmt.Printf("Location: %s\n", newPath)
 	fmt.Printf("Version:  WSL %d\n", copyVersion)
+	if noCleanup {
+		fmt.Printf("Temp dir: %s\n", tempDir)
+	}
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Printf("\nLaunch with:  wsl -d %s\n", newName)
 	fmt.Printf("List all:     autowsl list\n\n")
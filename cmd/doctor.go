@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/runner"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/windowsterminal"
+	"github.com/yuanjua/autowsl/internal/winget"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+// minFreeSpace is the free space below which installs are likely to fail
+const minFreeSpace = 2 * 1024 * 1024 * 1024
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that WSL and its prerequisites are set up correctly",
+	Long: `Run a checklist of the prerequisites autowsl relies on and print a hint for
+everything that needs attention. Exits non-zero if a required check fails;
+optional checks (winget, Windows Terminal) only produce warnings.
+
+Examples:
+  autowsl doctor`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// checkResult is the outcome of a single doctor check
+type checkResult struct {
+	status checkStatus
+	detail string
+	hint   string
+}
+
+// doctorCheck is a named diagnostic. Failures of optional checks are reported as warnings.
+type doctorCheck struct {
+	name     string
+	required bool
+	run      func() checkResult
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	r := runner.NewExecRunner(15 * time.Second)
+	r.WSLPath = wslExecutable()
+	client := wsl.NewClient(r)
+
+	checks := []doctorCheck{
+		{name: "wsl.exe on PATH", required: true, run: checkWSLOnPath},
+		{name: "WSL installed", required: true, run: func() checkResult { return checkWSLInstalled(client) }},
+		{name: "WSL version", required: false, run: func() checkResult { return checkWSLVersion(client) }},
+		{name: "Windows version", required: true, run: func() checkResult { return checkWindowsBuild(r) }},
+		{name: "WSL features", required: false, run: func() checkResult { return checkWindowsFeatures(r) }},
+		{name: "Disk space", required: true, run: checkDiskSpace},
+		{name: "winget", required: false, run: checkWinget},
+		{name: "Windows Terminal", required: false, run: checkWindowsTerminal},
+	}
+
+	fmt.Println("\nautowsl doctor")
+	fmt.Println(strings.Repeat("=", 60))
+
+	failed := 0
+	for _, c := range checks {
+		res := c.run()
+		if res.status == checkFail && !c.required {
+			res.status = checkWarn
+		}
+
+		symbol := "✓"
+		switch res.status {
+		case checkWarn:
+			symbol = "⚠"
+		case checkFail:
+			symbol = "✗"
+			failed++
+		}
+
+		fmt.Printf("%s %-18s %s\n", symbol, c.name, res.detail)
+		if res.status != checkOK && res.hint != "" {
+			fmt.Printf("  → %s\n", res.hint)
+		}
+	}
+	fmt.Println(strings.Repeat("=", 60))
+
+	if failed > 0 {
+		return fmt.Errorf("%d required check(s) failed", failed)
+	}
+	fmt.Println("All required checks passed.")
+	return nil
+}
+
+func checkWSLOnPath() checkResult {
+	path, err := exec.LookPath(wslExecutable())
+	if err != nil {
+		return checkResult{
+			status: checkFail,
+			detail: "not found",
+			hint:   "Install WSL with 'wsl --install' from an elevated PowerShell, or add %SystemRoot%\\System32 to PATH",
+		}
+	}
+	return checkResult{status: checkOK, detail: path}
+}
+
+func checkWSLInstalled(client *wsl.Client) checkResult {
+	if err := client.CheckWSLInstalled(); err != nil {
+		return checkResult{
+			status: checkFail,
+			detail: "wsl --status failed",
+			hint:   "Run 'wsl --install' and reboot: https://docs.microsoft.com/en-us/windows/wsl/install",
+		}
+	}
+	return checkResult{status: checkOK, detail: "available"}
+}
+
+func checkWSLVersion(client *wsl.Client) checkResult {
+	info, err := client.GetWSLVersion()
+	if err != nil || info.WSL == "" {
+		detail := "unknown (inbox WSL)"
+		if info.Kernel != "" {
+			detail += ", kernel " + info.Kernel
+		}
+		return checkResult{
+			status: checkFail,
+			detail: detail,
+			hint:   "Update to the Store version of WSL with 'wsl --update'",
+		}
+	}
+	return checkResult{status: checkOK, detail: formatWSLVersion(info)}
+}
+
+// formatWSLVersion describes WSL and the component versions that are known,
+// e.g. "2.0.14.0 (kernel 5.15.133.1-1, WSLg 1.0.59)"
+func formatWSLVersion(info wsl.WSLVersionInfo) string {
+	var parts []string
+	if info.Kernel != "" {
+		parts = append(parts, "kernel "+info.Kernel)
+	}
+	if info.WSLg != "" {
+		parts = append(parts, "WSLg "+info.WSLg)
+	}
+	if info.SystemDistro != "" {
+		parts = append(parts, "system distro "+info.SystemDistro)
+	}
+	version := info.WSL
+	if version == "" {
+		version = "inbox"
+	}
+	if len(parts) == 0 {
+		return version
+	}
+	return fmt.Sprintf("%s (%s)", version, strings.Join(parts, ", "))
+}
+
+// readWindowsBuild reads the Windows build number with reg.exe
+func readWindowsBuild(r runner.Runner) (int, error) {
+	output, _, err := r.Run("reg.exe", "query", `HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion`, "/v", "CurrentBuild")
+	if err != nil {
+		return 0, fmt.Errorf("could not read Windows build from the registry")
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "CurrentBuild" {
+			if build, err := strconv.Atoi(fields[2]); err == nil {
+				return build, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("could not parse Windows build")
+}
+
+func checkWindowsBuild(r runner.Runner) checkResult {
+	build, err := readWindowsBuild(r)
+	if err != nil {
+		return checkResult{status: checkWarn, detail: err.Error()}
+	}
+	if system.FeatureSupported(system.FeatureWSL2, build) != nil {
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("build %d does not support WSL 2", build),
+			hint:   fmt.Sprintf("Update Windows to build %d or later", system.MinimumBuild(system.FeatureWSL2)),
+		}
+	}
+	return checkResult{status: checkOK, detail: fmt.Sprintf("build %d", build)}
+}
+
+// checkWindowsFeatures reports the build-dependent WSL features this
+// Windows version lacks
+func checkWindowsFeatures(r runner.Runner) checkResult {
+	build, err := readWindowsBuild(r)
+	if err != nil {
+		return checkResult{status: checkWarn, detail: err.Error()}
+	}
+
+	var missing []string
+	newest := 0
+	for _, feature := range system.Features() {
+		if system.FeatureSupported(feature, build) != nil {
+			missing = append(missing, feature)
+			newest = max(newest, system.MinimumBuild(feature))
+		}
+	}
+	if len(missing) == 0 {
+		return checkResult{status: checkOK, detail: "all available (" + strings.Join(system.Features(), ", ") + ")"}
+	}
+	return checkResult{
+		status: checkWarn,
+		detail: "not available: " + strings.Join(missing, ", "),
+		hint:   fmt.Sprintf("Update Windows to build %d or later to use all features", newest),
+	}
+}
+
+func checkDiskSpace() checkResult {
+	root := defaultInstallRoot()
+	// The install root may not exist yet; measure the closest existing parent
+	free, err := system.FreeDiskSpace(existingParent(root))
+	if err != nil {
+		return checkResult{status: checkWarn, detail: "could not determine free space"}
+	}
+
+	detail := fmt.Sprintf("%.1f GB free for %s", float64(free)/1024/1024/1024, root)
+	if free < minFreeSpace {
+		return checkResult{
+			status: checkFail,
+			detail: detail,
+			hint:   "Free up space or set default_install_path in ~/.autowsl.yml to another drive",
+		}
+	}
+	return checkResult{status: checkOK, detail: detail}
+}
+
+func checkWinget() checkResult {
+	mgr := winget.NewManager(tempDirPath())
+	if !mgr.IsWingetAvailable() {
+		return checkResult{
+			status: checkWarn,
+			detail: "not found",
+			hint:   "Install 'App Installer' from the Microsoft Store to download distributions",
+		}
+	}
+	version, err := mgr.GetWingetVersion()
+	if err != nil {
+		return checkResult{status: checkOK, detail: "available"}
+	}
+	return checkResult{status: checkOK, detail: strings.TrimSpace(version)}
+}
+
+func checkWindowsTerminal() checkResult {
+	if _, err := windowsterminal.FindSettingsFile(); err != nil {
+		return checkResult{
+			status: checkWarn,
+			detail: "not found",
+			hint:   "Install Windows Terminal to get profiles for new distributions",
+		}
+	}
+	return checkResult{status: checkOK, detail: "installed"}
+}
@@ -7,12 +7,14 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/distro"
 	"github.com/yuanjua/autowsl/internal/winget"
 )
 
 var (
-	downloadOutputDir string
-	downloadPackageID string
+	downloadOutputDir   string
+	downloadPackageID   string
+	downloadForceDirect bool
 )
 
 var downloadCmd = &cobra.Command{
@@ -28,7 +30,11 @@ Examples:
   autowsl download                                 # Interactive mode
   autowsl download "Ubuntu 22.04 LTS"              # Direct download by version
   autowsl download --package-id Canonical.Ubuntu.2204  # Download by package ID
-  autowsl download --output ./packages             # Download to specific directory`,
+  autowsl download --output ./packages             # Download to specific directory
+  autowsl download "Ubuntu 22.04 LTS" --force-direct-download  # Skip winget, use the catalog URL
+
+Without winget, distributions that have a direct URL in the catalog are
+downloaded from it automatically.`,
 	RunE: runDownload,
 }
 
@@ -36,11 +42,15 @@ func init() {
 	rootCmd.AddCommand(downloadCmd)
 	downloadCmd.Flags().StringVarP(&downloadOutputDir, "output", "o", "", "Output directory (default: current directory)")
 	downloadCmd.Flags().StringVar(&downloadPackageID, "package-id", "", "Winget package ID (alternative to version name)")
+	downloadCmd.Flags().BoolVar(&downloadForceDirect, "force-direct-download", false, "Download from the catalog's direct URL even when winget is available")
+	downloadCmd.MarkFlagsMutuallyExclusive("package-id", "force-direct-download")
 }
 
 func runDownload(cmd *cobra.Command, args []string) error {
 	var packageID string
 	var distroName string
+	var selectedDistro distro.Distro
+	forceDirect := downloadForceDirect
 
 	// If package ID is provided directly, use it
 	if downloadPackageID != "" {
@@ -48,15 +58,16 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		distroName = downloadPackageID
 	} else {
 		// Use shared helper for distro selection
-		selectedDistro, err := selectDistro(args)
+		var err error
+		selectedDistro, err = selectDistro(args, "")
 		if err != nil {
 			return err
 		}
 
-		// Check if distro has packageId
-		if selectedDistro.PackageID == "" {
-			return fmt.Errorf("distribution '%s' does not have a winget package ID", selectedDistro.Version)
+		if selectedDistro.PackageID == "" && selectedDistro.URL == "" {
+			return fmt.Errorf("distribution '%s' has neither a winget package ID nor a direct download URL", selectedDistro.Version)
 		}
+		forceDirect = forceDirect || selectedDistro.PackageID == ""
 
 		packageID = selectedDistro.PackageID
 		distroName = selectedDistro.Version
@@ -65,7 +76,11 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Download Configuration\n")
 		fmt.Printf("%s\n", strings.Repeat("=", 60))
 		fmt.Printf("Distribution: %s - %s (%s)\n", selectedDistro.Group, selectedDistro.Version, selectedDistro.Architecture)
-		fmt.Printf("Package ID:   %s\n", packageID)
+		if forceDirect {
+			fmt.Printf("URL:          %s\n", selectedDistro.URL)
+		} else {
+			fmt.Printf("Package ID:   %s\n", packageID)
+		}
 	}
 
 	// Determine output directory
@@ -81,17 +96,13 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Output:       %s\n", outputDir)
 	fmt.Printf("%s\n\n", strings.Repeat("=", 60))
 
-	// Download using winget
+	// Download using winget, or directly when it is unavailable
 	fmt.Println("→ Downloading package...")
 	mgr := winget.NewManager(outputDir)
-
-	// Check if winget is available
-	if !mgr.IsWingetAvailable() {
-		return fmt.Errorf("winget is not available. Please install 'App Installer' from Microsoft Store")
-	}
-
 	downloadedFile, err := mgr.Download(winget.DownloadOptions{
-		PackageID: packageID,
+		PackageID:   packageID,
+		Distro:      selectedDistro,
+		ForceDirect: forceDirect,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to download '%s': %w", distroName, err)
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var execUser string
+
+var execCmd = &cobra.Command{
+	Use:   "exec <distro-name> -- <command> [args...]",
+	Short: "Run a command in a distribution and return its exit code",
+	Long: `Run a command inside a WSL distribution and exit with the command's own exit
+code, so autowsl can be used in scripts and conditionals. Use -- to separate
+autowsl flags from the command.
+
+Examples:
+  # Check for a file from a Windows script
+  autowsl exec ubuntu-2204-lts -- test -f /etc/passwd && echo found
+
+  # Run as another user
+  autowsl exec ubuntu-2204-lts --user root -- systemctl is-active docker`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runExec,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().StringVarP(&execUser, "user", "u", "", "Run the command as this user (e.g. root)")
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash != 1 {
+		return fmt.Errorf("usage: autowsl exec <distro-name> -- <command> [args...]")
+	}
+	distroName := args[0]
+
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	recordDistroStart(distroName)
+	exitCode, err := wsl.Exec(distroName, execUser, args[1:])
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
@@ -1,39 +1,129 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/manifoldco/promptui"
 	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/config"
 	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/metadata"
 	"github.com/yuanjua/autowsl/internal/playbooks"
+	"github.com/yuanjua/autowsl/internal/ui"
 	"github.com/yuanjua/autowsl/internal/wsl"
 )
 
-// selectDistroInteractive handles interactive distribution selection with promptui
-func selectDistroInteractive() (distro.Distro, error) {
-	distros := distro.GetAllDistros()
+// defaultInstallRoot returns the directory new distributions are installed under
+func defaultInstallRoot() string {
+	if root := config.Get().DefaultInstallPath; root != "" {
+		return root
+	}
+	cwd, _ := os.Getwd()
+	return filepath.Join(cwd, "wsl-distros")
+}
 
-	// Create selection prompt with colored templates
-	templates := &promptui.SelectTemplates{
-		Label:    "{{ . }}",
-		Active:   "> {{ .Group | cyan }} - {{ .Version | yellow }} ({{ .Architecture | faint }})",
-		Inactive: "  {{ .Group | white }} - {{ .Version | faint }} ({{ .Architecture | faint }})",
-		Selected: "* {{ .Group | green }} - {{ .Version | green }}",
+// tempDirPath returns the scratch directory used for downloads and exports
+func tempDirPath() string {
+	if dir := config.Get().TempDir; dir != "" {
+		return dir
+	}
+	cwd, _ := os.Getwd()
+	return filepath.Join(cwd, extractor.TempDirName)
+}
+
+// cleanupTempDir removes the scratch directory unless --no-cleanup is set
+func cleanupTempDir(dir string) error {
+	if noCleanup {
+		return nil
+	}
+	return extractor.CleanupTempDir(dir)
+}
+
+// finishTempDir removes the scratch directory after a successful run, or
+// reports where it was kept with --no-cleanup
+func finishTempDir(dir string) {
+	if noCleanup {
+		fmt.Printf("\n→ Keeping temporary files (--no-cleanup): %s\n", dir)
+		return
+	}
+	fmt.Println("\n→ Cleaning up temporary files...")
+	if err := extractor.CleanupTempDir(dir); err != nil {
+		fmt.Printf("  ⚠ Warning: Failed to cleanup temp directory: %v\n", err)
+	} else {
+		fmt.Println("  ✓ Cleanup completed")
+	}
+}
+
+// playbooksDirPath returns the directory searched for playbook aliases:
+// --playbook-dir, then playbooks_dir from the config file, then ./playbooks
+func playbooksDirPath() string {
+	if playbookDir != "" {
+		return expandHome(playbookDir)
+	}
+	if dir := config.Get().PlaybooksDir; dir != "" {
+		return expandHome(dir)
+	}
+	cwd, _ := os.Getwd()
+	return filepath.Join(cwd, "playbooks")
+}
+
+// expandHome replaces a leading ~ with the user's home directory, which
+// Windows shells don't do themselves
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// distroSelectTemplates colors catalog entries in selection prompts
+var distroSelectTemplates = &promptui.SelectTemplates{
+	Label:    "{{ . }}",
+	Active:   "> {{ .Group | cyan }} - {{ .Version | yellow }} ({{ .Architecture | faint }})",
+	Inactive: "  {{ .Group | white }} - {{ .Version | faint }} ({{ .Architecture | faint }})",
+	Selected: "* {{ .Group | green }} - {{ .Version | green }}",
+}
+
+// selectDistroInteractive handles interactive distribution selection with
+// promptui: first a group, then a version within it. A non-empty group skips
+// the group selection.
+func selectDistroInteractive(group string) (distro.Distro, error) {
+	useRemoteCatalog()
+	var err error
+	if group == "" {
+		group, err = selectGroupInteractive()
+	} else {
+		group, err = distro.FindGroup(group)
+	}
+	if err != nil {
+		return distro.Distro{}, err
+	}
+
+	distros := distro.GetDistrosByGroup(group)
+	if len(distros) == 1 {
+		fmt.Printf("Using %s\n", distros[0].Version)
+		return distros[0], nil
 	}
 
 	prompt := promptui.Select{
-		Label:     "Select a WSL distribution",
+		Label:     fmt.Sprintf("Select a %s version", group),
 		Items:     distros,
-		Templates: templates,
+		Templates: distroSelectTemplates,
 		Size:      12,
 	}
 
-	idx, _, err := prompt.Run()
+	idx, _, err := ui.Select(prompt)
 	if err != nil {
 		return distro.Distro{}, fmt.Errorf("selection cancelled: %w", err)
 	}
@@ -41,8 +131,31 @@ func selectDistroInteractive() (distro.Distro, error) {
 	return distros[idx], nil
 }
 
+// selectGroupInteractive asks for a distribution group of the catalog
+func selectGroupInteractive() (string, error) {
+	groups := distro.GetGroups()
+	if len(groups) == 0 {
+		return "", fmt.Errorf("the distribution catalog is empty")
+	}
+	if len(groups) == 1 {
+		return groups[0], nil
+	}
+
+	prompt := promptui.Select{
+		Label: "Select a distribution family",
+		Items: groups,
+		Size:  12,
+	}
+	idx, _, err := ui.Select(prompt)
+	if err != nil {
+		return "", fmt.Errorf("selection cancelled: %w", err)
+	}
+	return groups[idx], nil
+}
+
 // selectDistroByVersion finds a distribution by its version name or package ID
 func selectDistroByVersion(versionName string) (distro.Distro, error) {
+	useRemoteCatalog()
 	distros := distro.GetAllDistros()
 
 	// Try exact match on version name
@@ -53,12 +166,31 @@ func selectDistroByVersion(versionName string) (distro.Distro, error) {
 	}
 
 	// Try match on package ID
-	for _, d := range distros {
-		if strings.EqualFold(d.PackageID, versionName) {
+	if d, err := distro.FindDistroByPackageID(versionName); err == nil {
+		return *d, nil
+	}
+
+	// A partial version name, e.g. "22.04", picks among the entries containing it
+	if partial, err := distro.FindDistroByVersionFuzzy(versionName); err == nil {
+		if d, err := selectPartialMatch(versionName, partial); err == nil {
 			return d, nil
 		}
 	}
 
+	// Offer to correct a typo when a single entry is clearly the closest. A
+	// non-interactive run would accept any guess, so it only gets the suggestions.
+	matches := distro.ClosestMatches(versionName, 3)
+	if ui.IsInteractive() && len(matches) > 0 && matches[0].Score >= distro.AutoCorrectScore &&
+		(len(matches) == 1 || matches[1].Score < distro.AutoCorrectScore) {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Did you mean '%s'", matches[0].Distro.Version),
+			IsConfirm: true,
+		}
+		if _, err := ui.Prompt(prompt); err == nil {
+			return matches[0].Distro, nil
+		}
+	}
+
 	// If not found, show available options
 	fmt.Fprintf(os.Stderr, "\nError: distribution '%s' not found\n\n", versionName)
 	fmt.Fprintln(os.Stderr, "Available distributions:")
@@ -78,13 +210,48 @@ func selectDistroByVersion(versionName string) (distro.Distro, error) {
 	fmt.Fprintln(os.Stderr, strings.Repeat("-", 80))
 	fmt.Fprintln(os.Stderr, "\nTip: Use either the version name (e.g., 'Ubuntu 22.04 LTS') or package ID")
 
-	return distro.Distro{}, fmt.Errorf("distribution '%s' not found", versionName)
+	return distro.Distro{}, fmt.Errorf("distribution '%s' not found%s", versionName, suggestionSuffix(matches))
 }
 
-// selectDistro selects a distribution either interactively or by version name
-func selectDistro(args []string) (distro.Distro, error) {
+// selectPartialMatch returns the only distribution matching a partial version
+// name or asks which of several was meant
+func selectPartialMatch(versionName string, distros []distro.Distro) (distro.Distro, error) {
+	if len(distros) == 1 {
+		// stderr keeps machine-readable output such as catalog show -o json clean
+		fmt.Fprintf(os.Stderr, "Using %s\n", distros[0].Version)
+		return distros[0], nil
+	}
+
+	prompt := promptui.Select{
+		Label:     fmt.Sprintf("Several distributions match '%s'", versionName),
+		Items:     distros,
+		Templates: distroSelectTemplates,
+		Size:      12,
+	}
+	idx, _, err := ui.Select(prompt)
+	if err != nil {
+		return distro.Distro{}, fmt.Errorf("selection cancelled: %w", err)
+	}
+	return distros[idx], nil
+}
+
+// suggestionSuffix formats fuzzy matches as a "did you mean" hint for an error
+func suggestionSuffix(matches []distro.Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = "'" + m.Distro.Version + "'"
+	}
+	return "; did you mean " + strings.Join(names, ", ") + "?"
+}
+
+// selectDistro selects a distribution either interactively or by version
+// name. group limits the interactive selection to one distribution group.
+func selectDistro(args []string, group string) (distro.Distro, error) {
 	if len(args) == 0 {
-		return selectDistroInteractive()
+		return selectDistroInteractive(group)
 	}
 	return selectDistroByVersion(args[0])
 }
@@ -114,7 +281,7 @@ func selectInstalledDistroInteractive() (string, error) {
 		Templates: templates,
 	}
 
-	idx, _, err := prompt.Run()
+	idx, _, err := ui.Select(prompt)
 	if err != nil {
 		return "", fmt.Errorf("selection cancelled: %w", err)
 	}
@@ -122,6 +289,30 @@ func selectInstalledDistroInteractive() (string, error) {
 	return distros[idx].Name, nil
 }
 
+// distroNotFoundError prints the installed distributions to help the user pick
+// a valid name and returns the error to report for a missing distro
+func distroNotFoundError(distroName string) error {
+	distros, listErr := wsl.ListInstalledDistros()
+	if listErr == nil && len(distros) > 0 {
+		fmt.Fprintf(os.Stderr, "\nError: distribution '%s' does not exist\n\n", distroName)
+		fmt.Fprintln(os.Stderr, "Available installed distributions:")
+		fmt.Fprintln(os.Stderr, strings.Repeat("-", 60))
+		for _, d := range distros {
+			marker := " "
+			if d.Default {
+				marker = "*"
+			}
+			status := d.State
+			fmt.Fprintf(os.Stderr, "%s %-30s (%s)\n", marker, d.Name, status)
+		}
+		fmt.Fprintln(os.Stderr, strings.Repeat("-", 60))
+		fmt.Fprintln(os.Stderr, "\nTip: Use 'autowsl list' to see all installed distributions")
+		fmt.Fprintln(os.Stderr, "     Use 'autowsl install' to install a new distribution")
+		return fmt.Errorf("distribution not found")
+	}
+	return fmt.Errorf("distribution '%s' does not exist", distroName)
+}
+
 // selectInstalledDistro selects an installed distro either from args or interactively
 // (selectInstalledDistro removed – previously unused; interactive selection handled where needed)
 
@@ -132,7 +323,7 @@ func promptForPlaybooks() ([]string, error) {
 		Default: "",
 	}
 
-	result, err := prompt.Run()
+	result, err := ui.Prompt(prompt)
 	if err != nil {
 		return nil, fmt.Errorf("input cancelled: %w", err)
 	}
@@ -170,18 +361,108 @@ type ProvisioningPipelineOptions struct {
 	DistroName     string
 	PlaybookInputs []string
 	Tags           []string
+	SkipTags       []string
+	TagsFile       string // YAML file with more tags to run, merged into Tags
+	SkipTagsFile   string // YAML file with more tags to skip, merged into SkipTags
+	Limit          string
+	Forks          int // ansible-playbook --forks; 0 keeps the Ansible default
+	OutputLog      string
 	ExtraVars      []string
+	ExtraVarsFile  string
+	AnsibleCfg     string
+	PipPackages    []string // Python packages installed with pip3 before the playbooks run
+	SummaryJSON    string   // Where the execution summary is written as JSON, if set
 	Verbose        bool
 	TempDir        string
+	MaxRetries     int
+
+	Connection    string // Ansible connection type; empty means local
+	InventoryPath string // Inventory of remote hosts for a non-local Connection
+
+	PrePlaybookCmds  []string // Shell commands run in the distribution before each playbook
+	PostPlaybookCmds []string // Shell commands run in the distribution after each playbook
+	StrictPostCmds   bool     // Fail a playbook when one of its post commands fails
+
+	ForceRefreshPlaybooks bool // Download playbook URLs again instead of using cached copies
+
+	VaultPasswordFile string
+	AskVaultPass      bool
+	VaultIDs          []ansible.VaultID
+	SkipGalaxyInstall bool
+
+	GalaxyCollectionsFile string // requirements file for ansible-galaxy collection install
+	GalaxyRolesFile       string // requirements file for ansible-galaxy role install
+
+	BecomePasswordFile string
+
+	RequiredAnsibleVersion string // Fail before running playbooks if Ansible is older than this
+	ForceReinstallAnsible  bool   // Install Ansible again before the playbooks even if it is present
+
+	ValidatePlaybooks bool // Check the YAML structure of every playbook before anything is installed
+
+	SyntaxCheck bool // Validate every playbook before running any of them
+	CheckMode   bool // Run Ansible with --check
+	DiffMode    bool // Run Ansible with --diff
+
+	ContinueOnError bool // Run the remaining playbooks after a failure instead of stopping
+
+	Parallel    bool // Run playbooks concurrently instead of one after another
+	MaxParallel int  // Concurrency limit for Parallel; <= 0 uses defaultMaxParallel
+}
+
+// vaultIDsValue is a repeatable --vault-id flag holding label@source values
+type vaultIDsValue struct {
+	ids *[]ansible.VaultID
 }
 
+func (v vaultIDsValue) String() string {
+	values := make([]string, 0, len(*v.ids))
+	for _, id := range *v.ids {
+		values = append(values, id.String())
+	}
+	return strings.Join(values, ",")
+}
+
+func (v vaultIDsValue) Set(value string) error {
+	id, err := ansible.ParseVaultID(value)
+	if err != nil {
+		return err
+	}
+	*v.ids = append(*v.ids, id)
+	return nil
+}
+
+func (v vaultIDsValue) Type() string {
+	return "label@source"
+}
+
+// defaultMaxParallel is the number of playbooks run at once with --parallel
+const defaultMaxParallel = 4
+
 // runProvisioningPipeline executes the complete provisioning pipeline
 func runProvisioningPipeline(opts ProvisioningPipelineOptions) error {
 	fmt.Printf("\nProvisioning: %s\n", opts.DistroName)
 	fmt.Println(strings.Repeat("=", 60))
 
+	// The JSON summary is written however the run ends so CI can collect it
+	summary := &ansible.ExecutionSummary{}
+	if opts.SummaryJSON != "" && !dryRun {
+		defer func() {
+			if err := summary.WriteJSON(opts.SummaryJSON); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ Warning: %v\n", err)
+			}
+		}()
+	}
+
+	if opts.Forks < 0 {
+		return fmt.Errorf("invalid --forks %d (must be positive)", opts.Forks)
+	}
+	if err := ansible.ValidateConnection(opts.Connection, opts.InventoryPath); err != nil {
+		return err
+	}
+
 	// Parse extra vars
-	extraVarsMap := make(map[string]string)
+	extraVarsMap := make(map[string]any)
 	if len(opts.ExtraVars) > 0 {
 		var err error
 		extraVarsMap, err = playbooks.ParseExtraVars(opts.ExtraVars)
@@ -189,19 +470,50 @@ func runProvisioningPipeline(opts ProvisioningPipelineOptions) error {
 			return fmt.Errorf("invalid extra-vars: %w", err)
 		}
 	}
+	if opts.ExtraVarsFile != "" {
+		if err := ansible.ValidateExtraVarsFile(opts.ExtraVarsFile); err != nil {
+			return err
+		}
+	}
+	if opts.AnsibleCfg != "" {
+		if _, err := os.Stat(opts.AnsibleCfg); err != nil {
+			return fmt.Errorf("ansible.cfg '%s' not found: %w", opts.AnsibleCfg, err)
+		}
+	}
+	if opts.TagsFile != "" {
+		tags, err := ansible.LoadTagsFile(opts.TagsFile)
+		if err != nil {
+			return err
+		}
+		opts.Tags = ansible.MergeTags(opts.Tags, tags)
+	}
+	if opts.SkipTagsFile != "" {
+		tags, err := ansible.LoadTagsFile(opts.SkipTagsFile)
+		if err != nil {
+			return err
+		}
+		opts.SkipTags = ansible.MergeTags(opts.SkipTags, tags)
+	}
+	if err := ansible.ValidatePipPackages(opts.PipPackages); err != nil {
+		return err
+	}
 
 	// Ensure temp directory exists
 	if opts.TempDir == "" {
-		cwd, _ := os.Getwd()
-		opts.TempDir = filepath.Join(cwd, ".autowsl_tmp")
+		opts.TempDir = tempDirPath()
 	}
-	if err := os.MkdirAll(opts.TempDir, 0755); err != nil {
-		return fmt.Errorf("failed to create temp dir '%s': %w", opts.TempDir, err)
+	if !dryRun {
+		if err := os.MkdirAll(opts.TempDir, 0755); err != nil {
+			return fmt.Errorf("failed to create temp dir '%s': %w", opts.TempDir, err)
+		}
 	}
 
 	// Resolve playbooks
 	cwd, _ := os.Getwd()
 	resolver := playbooks.NewResolver(opts.TempDir, cwd)
+	resolver.AliasDir = playbooksDirPath()
+	resolver.MaxAttempts = opts.MaxRetries + 1
+	resolver.ForceRefresh = opts.ForceRefreshPlaybooks
 	playbookPaths, err := resolver.ResolveMultiple(opts.PlaybookInputs)
 	if err != nil {
 		return fmt.Errorf("failed to resolve playbooks: %w", err)
@@ -211,50 +523,66 @@ func runProvisioningPipeline(opts ProvisioningPipelineOptions) error {
 		return fmt.Errorf("no playbooks resolved")
 	}
 
-	// Execute playbooks with summary tracking
-	summary := &ansible.ExecutionSummary{}
+	if opts.ValidatePlaybooks {
+		for _, path := range playbookPaths {
+			if err := playbooks.ValidatePlaybook(path); err != nil {
+				return err
+			}
+		}
+	}
 
-	for _, playbookPath := range playbookPaths {
-		start := time.Now()
+	if opts.Parallel && opts.CheckMode {
+		return fmt.Errorf("--check cannot be combined with --parallel")
+	}
+	if opts.Parallel && opts.DiffMode {
+		return fmt.Errorf("--diff cannot be combined with --parallel")
+	}
 
-		fmt.Printf("\nRunning playbook: %s\n", filepath.Base(playbookPath))
-		fmt.Println(strings.Repeat("-", 60))
+	// Reinstall once here; the playbooks then find Ansible present
+	if opts.ForceReinstallAnsible {
+		if err := ansible.ReinstallAnsible(opts.DistroName); err != nil {
+			return err
+		}
+	}
 
-		execOpts := ansible.PlaybookOptions{
-			DistroName:   opts.DistroName,
-			PlaybookPath: playbookPath,
-			Tags:         opts.Tags,
-			Verbose:      opts.Verbose,
-			ExtraVars:    extraVarsMap,
+	if opts.SyntaxCheck {
+		if err := validatePlaybooks(opts, playbookPaths, extraVarsMap); err != nil {
+			return err
 		}
+	}
 
-		err := ansible.ExecutePlaybook(execOpts)
-		duration := time.Since(start)
+	// Python libraries the playbooks depend on go in after Ansible itself
+	if len(opts.PipPackages) > 0 {
+		if err := ansible.EnsureAnsible(opts.DistroName); err != nil {
+			return err
+		}
+		if err := ansible.PipInstallPackages(opts.DistroName, opts.PipPackages); err != nil {
+			return err
+		}
+	}
 
+	// Execute playbooks with summary tracking
+	if opts.Parallel && len(playbookPaths) > 1 {
+		parallelSummary, err := runPlaybooksParallel(opts, playbookPaths, extraVarsMap)
 		if err != nil {
-			summary.Add(ansible.ExecutionResult{
-				PlaybookName: filepath.Base(playbookPath),
-				Status:       "failed",
-				Duration:     duration,
-				Error:        err,
-			})
-			fmt.Printf("\nPlaybook '%s' failed: %v\n", filepath.Base(playbookPath), err)
-			break // Stop on first failure
-		} else {
-			summary.Add(ansible.ExecutionResult{
-				PlaybookName: filepath.Base(playbookPath),
-				Status:       "success",
-				Duration:     duration,
-			})
+			return err
 		}
+		summary = parallelSummary
+	} else {
+		summary = runPlaybooksSequential(opts, playbookPaths, extraVarsMap)
 	}
 
+	recordProvisionedPlaybooks(opts, summary)
+
 	// Print summary if multiple playbooks
 	if len(playbookPaths) > 1 {
 		summary.Print()
 	}
 
 	if summary.HasFailures() {
+		if opts.ContinueOnError {
+			return fmt.Errorf("provisioning completed with failures: %s", strings.Join(summary.FailedPlaybooks(), ", "))
+		}
 		return fmt.Errorf("provisioning completed with failures")
 	}
 
@@ -267,10 +595,200 @@ func runProvisioningPipeline(opts ProvisioningPipelineOptions) error {
 	if len(opts.Tags) > 0 {
 		fmt.Printf("Tags:         %s\n", strings.Join(opts.Tags, ", "))
 	}
+	if len(opts.SkipTags) > 0 {
+		fmt.Printf("Skip tags:    %s\n", strings.Join(opts.SkipTags, ", "))
+	}
+	if opts.Limit != "" {
+		fmt.Printf("Limit:        %s\n", opts.Limit)
+	}
 	if len(extraVarsMap) > 0 {
 		fmt.Printf("Extra vars:   %d variables\n", len(extraVarsMap))
 	}
+	if noCleanup {
+		fmt.Printf("Temp dir:     %s\n", opts.TempDir)
+	}
 	fmt.Println(strings.Repeat("=", 60))
 
 	return nil
 }
+
+// playbookExecOptions builds the executor options for one playbook of the pipeline
+func playbookExecOptions(opts ProvisioningPipelineOptions, playbookPath string, extraVars map[string]any) ansible.PlaybookOptions {
+	return ansible.PlaybookOptions{
+		DistroName:    opts.DistroName,
+		PlaybookPath:  playbookPath,
+		Tags:          opts.Tags,
+		SkipTags:      opts.SkipTags,
+		Limit:         opts.Limit,
+		Forks:         opts.Forks,
+		OutputLog:     opts.OutputLog,
+		Connection:    opts.Connection,
+		InventoryPath: opts.InventoryPath,
+		Verbose:       opts.Verbose,
+		ExtraVars:     extraVars,
+		ExtraVarsFile: opts.ExtraVarsFile,
+		AnsibleCfg:    opts.AnsibleCfg,
+
+		VaultPasswordFile: opts.VaultPasswordFile,
+		AskVaultPass:      opts.AskVaultPass,
+		VaultIDs:          opts.VaultIDs,
+		SkipGalaxyInstall: opts.SkipGalaxyInstall,
+
+		GalaxyCollectionsFile: opts.GalaxyCollectionsFile,
+		GalaxyRolesFile:       opts.GalaxyRolesFile,
+
+		BecomePasswordFile: opts.BecomePasswordFile,
+
+		RequiredAnsibleVersion: opts.RequiredAnsibleVersion,
+
+		CheckMode: opts.CheckMode,
+		DiffMode:  opts.DiffMode,
+
+		PrePlaybookCmds:  opts.PrePlaybookCmds,
+		PostPlaybookCmds: opts.PostPlaybookCmds,
+		StrictPostCmds:   opts.StrictPostCmds,
+	}
+}
+
+// playbookResult converts the outcome of a playbook run into a summary entry
+func playbookResult(playbookPath string, duration time.Duration, err error) ansible.ExecutionResult {
+	result := ansible.ExecutionResult{
+		PlaybookName: filepath.Base(playbookPath),
+		Status:       "success",
+		Duration:     duration,
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err
+	}
+	return result
+}
+
+// validatePlaybooks syntax-checks all playbooks so a typo in a later one is
+// caught before earlier ones have changed the distribution
+func validatePlaybooks(opts ProvisioningPipelineOptions, playbookPaths []string, extraVars map[string]any) error {
+	fmt.Printf("\nChecking syntax of %d playbook(s)\n", len(playbookPaths))
+	fmt.Println(strings.Repeat("-", 60))
+
+	for _, playbookPath := range playbookPaths {
+		if err := ansible.Validate(playbookExecOptions(opts, playbookPath, extraVars)); err != nil {
+			return fmt.Errorf("syntax check failed, no playbooks were run: %w", err)
+		}
+	}
+	return nil
+}
+
+// runPlaybooksSequential runs playbooks one by one, stopping at the first
+// failure unless opts.ContinueOnError is set
+func runPlaybooksSequential(opts ProvisioningPipelineOptions, playbookPaths []string, extraVars map[string]any) *ansible.ExecutionSummary {
+	summary := &ansible.ExecutionSummary{}
+
+	for _, playbookPath := range playbookPaths {
+		start := time.Now()
+
+		fmt.Printf("\nRunning playbook: %s\n", filepath.Base(playbookPath))
+		fmt.Println(strings.Repeat("-", 60))
+
+		err := ansible.ExecutePlaybook(playbookExecOptions(opts, playbookPath, extraVars))
+		result := playbookResult(playbookPath, time.Since(start), err)
+		recordHistory(opts, result)
+		summary.Add(result)
+		if err != nil {
+			fmt.Printf("\nPlaybook '%s' failed: %v\n", filepath.Base(playbookPath), err)
+			if !opts.ContinueOnError {
+				break // Stop on first failure
+			}
+		}
+	}
+
+	return summary
+}
+
+// runPlaybooksParallel runs playbooks concurrently. Each playbook's output is
+// buffered and printed as one block when it finishes so logs don't interleave.
+// All playbooks run to completion even if one of them fails.
+func runPlaybooksParallel(opts ProvisioningPipelineOptions, playbookPaths []string, extraVars map[string]any) (*ansible.ExecutionSummary, error) {
+	if opts.AskVaultPass {
+		return nil, fmt.Errorf("--ask-vault-pass cannot be combined with --parallel; use --vault-password-file")
+	}
+	if ansible.HasVaultPrompt(opts.VaultIDs) {
+		return nil, fmt.Errorf("--vault-id with a prompt cannot be combined with --parallel; use a password file")
+	}
+
+	maxParallel := opts.MaxParallel
+	if maxParallel <= 0 {
+		maxParallel = defaultMaxParallel
+	}
+
+	// Install Ansible once up front instead of racing the package manager
+	if err := ansible.EnsureAnsible(opts.DistroName); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("\nRunning %d playbooks in parallel (max %d at a time)\n", len(playbookPaths), maxParallel)
+
+	results := make([]ansible.ExecutionResult, len(playbookPaths))
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+	var printMu sync.Mutex
+
+	for i, playbookPath := range playbookPaths {
+		wg.Add(1)
+		go func(i int, playbookPath string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var buf bytes.Buffer
+			execOpts := playbookExecOptions(opts, playbookPath, extraVars)
+			execOpts.Output = &buf
+			execOpts.AnsibleReady = true
+
+			start := time.Now()
+			err := ansible.ExecutePlaybook(execOpts)
+			results[i] = playbookResult(playbookPath, time.Since(start), err)
+			recordHistory(opts, results[i])
+
+			printMu.Lock()
+			defer printMu.Unlock()
+			fmt.Printf("\nPlaybook: %s\n", filepath.Base(playbookPath))
+			fmt.Println(strings.Repeat("-", 60))
+			os.Stdout.Write(buf.Bytes())
+			if err != nil {
+				fmt.Printf("\nPlaybook '%s' failed: %v\n", filepath.Base(playbookPath), err)
+			}
+		}(i, playbookPath)
+	}
+	wg.Wait()
+
+	summary := &ansible.ExecutionSummary{}
+	for _, result := range results {
+		summary.Add(result)
+	}
+	return summary, nil
+}
+
+// recordProvisionedPlaybooks adds the playbooks that succeeded to the
+// distribution's .autowsl.json metadata. Failures only print a warning.
+func recordProvisionedPlaybooks(opts ProvisioningPipelineOptions, summary *ansible.ExecutionSummary) {
+	if dryRun || opts.CheckMode {
+		return
+	}
+	var succeeded []string
+	for _, r := range summary.Results {
+		if r.Status == "success" {
+			succeeded = append(succeeded, r.PlaybookName)
+		}
+	}
+	if len(succeeded) == 0 {
+		return
+	}
+
+	installPath, err := wsl.GetInstallPath(opts.DistroName)
+	if err == nil {
+		err = metadata.AddPlaybooks(installPath, succeeded, opts.Tags)
+	}
+	if err != nil {
+		fmt.Printf("  ⚠ Warning: Failed to update distribution metadata: %v\n", err)
+	}
+}
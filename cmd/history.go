@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/history"
+)
+
+var (
+	historyDistro string
+	historyLast   int
+	historyFailed bool
+	historyJSON   bool
+
+	historyPruneKeep   int
+	historyPruneBefore string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show past playbook runs",
+	Long: `Show the playbooks autowsl has run, against which distributions, and whether they succeeded.
+The log is kept in ~/.autowsl/history.json and capped at history_limit entries
+(default 1000, or --keep-history); 'history prune' trims it further.
+
+Examples:
+  # Show all recorded runs
+  autowsl history
+
+  # Last 10 runs against one distribution
+  autowsl history --distro ubuntu-2204 --last 10
+
+  # Only failures, as JSON for scripting
+  autowsl history --failed --json
+
+  # Drop runs older than 30 days
+  autowsl history prune --before 30d`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+var historyPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old entries from the history log",
+	Long: `Remove entries from the history log that are older than --before (e.g. 30d or
+12h), then keep only the newest --keep entries.
+
+Examples:
+  autowsl history prune --before 30d
+  autowsl history prune --keep 100`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historyDistro, "distro", "", "Only show runs against this distribution")
+	historyCmd.Flags().IntVar(&historyLast, "last", 0, "Only show the last n runs")
+	historyCmd.Flags().BoolVar(&historyFailed, "failed", false, "Only show failed runs")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output raw JSON")
+
+	historyCmd.AddCommand(historyPruneCmd)
+	historyPruneCmd.Flags().IntVar(&historyPruneKeep, "keep", 0, "Keep only the newest n entries")
+	historyPruneCmd.Flags().StringVar(&historyPruneBefore, "before", "", "Remove entries older than this age, e.g. 30d or 12h")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	entries, err := history.Load()
+	if err != nil {
+		return err
+	}
+	entries = history.Filter(entries, historyDistro, historyFailed, historyLast)
+
+	if historyJSON {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No playbook runs recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TIME\tDISTRO\tPLAYBOOK\tTAGS\tDURATION\tSTATUS")
+	fmt.Fprintln(w, "----\t------\t--------\t----\t--------\t------")
+	for _, e := range entries {
+		status := "success"
+		if !e.Success {
+			status = "failed"
+		}
+		tags := strings.Join(e.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Distro, e.Playbook, tags, e.Duration.Round(time.Second), status)
+	}
+	return w.Flush()
+}
+
+func runHistoryPrune(cmd *cobra.Command, args []string) error {
+	if historyPruneKeep < 0 {
+		return fmt.Errorf("invalid --keep %d (must be positive)", historyPruneKeep)
+	}
+	if historyPruneKeep == 0 && historyPruneBefore == "" {
+		return fmt.Errorf("nothing to prune; pass --keep and/or --before")
+	}
+
+	var before time.Time
+	if historyPruneBefore != "" {
+		age, err := history.ParseAge(historyPruneBefore)
+		if err != nil {
+			return fmt.Errorf("invalid --before: %w", err)
+		}
+		before = time.Now().Add(-age)
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] would prune %s\n", history.Path())
+		return nil
+	}
+	removed, err := history.Prune(historyPruneKeep, before)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed %d entries from the history log\n", removed)
+	return nil
+}
+
+// recordHistory appends a finished playbook run to the history log. Failing
+// to write the log only prints a warning.
+func recordHistory(opts ProvisioningPipelineOptions, result ansible.ExecutionResult) {
+	if dryRun {
+		return
+	}
+	entry := history.Entry{
+		Timestamp: time.Now().Add(-result.Duration).UTC(),
+		Distro:    opts.DistroName,
+		Playbook:  result.PlaybookName,
+		Tags:      opts.Tags,
+		Duration:  result.Duration,
+		Success:   result.Error == nil,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	if err := history.Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record history: %v\n", err)
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	importURL      string
+	importFromFile string
+	importName     string
+	importPath     string
+	importVersion  int
+	importKeepTar  bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --url <url> | --from-file <tar>",
+	Short: "Import a rootfs tarball from a URL or a local file",
+	Long: `Import a rootfs tarball as a new WSL distribution without going through
+the catalog. With --url the tarball is downloaded first (a partial download is
+resumed) and removed after the import unless --keep-tar is set.
+
+Use 'autowsl install --url' instead for appx packages or to run playbooks
+after the import.
+
+Examples:
+  # Import a tarball published on a web server
+  autowsl import --url https://files.example.com/wsl/dev-rootfs.tar.gz --name dev
+
+  # Import a local tarball into a specific directory
+  autowsl import --from-file ./backup.tar --name dev --path D:\WSL\dev`,
+	Args: cobra.NoArgs,
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importURL, "url", "", "URL of the rootfs tarball to download and import")
+	importCmd.Flags().StringVar(&importFromFile, "from-file", "", "Local rootfs tarball to import")
+	importCmd.Flags().StringVar(&importName, "name", "", "Name for the distribution (defaults to the tarball name)")
+	importCmd.Flags().StringVar(&importPath, "path", "", "Installation path (defaults to <install root>/<name>)")
+	importCmd.Flags().IntVar(&importVersion, "version", 2, "WSL version to use (1 or 2)")
+	importCmd.Flags().BoolVar(&importKeepTar, "keep-tar", false, "Keep the downloaded tarball after a --url import")
+	importCmd.MarkFlagsMutuallyExclusive("url", "from-file")
+	importCmd.MarkFlagsOneRequired("url", "from-file")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	name := importName
+	if name == "" {
+		if importURL != "" {
+			name = distroNameFromURL(importURL)
+		} else {
+			base := strings.ToLower(filepath.Base(importFromFile))
+			name = sanitizeDistroName(strings.TrimSuffix(strings.TrimSuffix(base, ".gz"), ".tar"))
+		}
+	}
+	if err := wsl.ValidateDistroName(name); err != nil {
+		return fmt.Errorf("%w (use --name)", err)
+	}
+	if importVersion != 1 && importVersion != 2 {
+		return fmt.Errorf("invalid --version %d (must be 1 or 2)", importVersion)
+	}
+
+	path := importPath
+	if path == "" {
+		path = filepath.Join(defaultInstallRoot(), name)
+	}
+	opts := wsl.ImportOptions{
+		Name:        name,
+		InstallPath: path,
+		Version:     importVersion,
+		KeepTar:     importKeepTar,
+	}
+
+	if importURL != "" {
+		fmt.Printf("→ Downloading and importing %s as '%s'...\n", importURL, name)
+		if err := wsl.ImportFromURL(importURL, opts); err != nil {
+			return fmt.Errorf("failed to import '%s': %w", importURL, err)
+		}
+		if importKeepTar && !dryRun {
+			fmt.Printf("→ Keeping tar file in %s\n", wsl.ImportDownloadDir(name))
+		}
+	} else {
+		absTarPath, err := filepath.Abs(importFromFile)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path for tar file: %w", err)
+		}
+		opts.TarFilePath = absTarPath
+		fmt.Printf("→ Importing %s as '%s'...\n", filepath.Base(absTarPath), name)
+		if err := wsl.Import(opts); err != nil {
+			return fmt.Errorf("failed to import distribution '%s' to '%s': %w", name, path, err)
+		}
+	}
+
+	fmt.Printf("✓ Imported '%s' at %s\n", name, path)
+	return nil
+}
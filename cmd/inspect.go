@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/metadata"
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var inspectOutput string
+
+// distroDetails is everything inspect reports about a distribution
+type distroDetails struct {
+	wsl.InstalledDistro `yaml:",inline"`
+
+	InstallPath string             `json:"install_path,omitempty" yaml:"install_path,omitempty"`
+	VHDPath     string             `json:"vhd_path,omitempty" yaml:"vhd_path,omitempty"`
+	VHDSize     int64              `json:"vhd_size,omitempty" yaml:"vhd_size,omitempty"`
+	Metadata    *metadata.Metadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	WSLConf     string             `json:"wsl_conf,omitempty" yaml:"wsl_conf,omitempty"`
+	Memory      *wsl.MemoryInfo    `json:"memory,omitempty" yaml:"memory,omitempty"`
+	Processes   []wsl.Process      `json:"processes,omitempty" yaml:"processes,omitempty"`
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <distro-name>",
+	Short: "Show detailed information about a distribution",
+	Long: `Show detailed information about an installed distribution: state, WSL version,
+install path, virtual disk size, and /etc/wsl.conf. Running distributions also
+report their processes and memory usage; stopped ones are not started.
+
+Examples:
+  autowsl inspect ubuntu-2204
+  autowsl inspect ubuntu-2204 --output json
+  autowsl inspect ubuntu-2204 -o yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.Flags().StringVarP(&inspectOutput, "output", "o", "table", "Output format: table, json, or yaml")
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	if inspectOutput != "table" && inspectOutput != "json" && inspectOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", inspectOutput)
+	}
+	distroName := args[0]
+
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+	var details *distroDetails
+	for _, d := range distros {
+		if d.Name == distroName {
+			details = &distroDetails{InstalledDistro: d}
+			break
+		}
+	}
+	if details == nil {
+		return distroNotFoundError(distroName)
+	}
+
+	// Everything below is best effort: report what can be read and keep going
+	var warnings []string
+	if path, err := wsl.GetInstallPath(distroName); err == nil {
+		details.InstallPath = path
+		if m, err := metadata.Read(path); err == nil {
+			details.Metadata = &m
+		}
+	} else {
+		warnings = append(warnings, err.Error())
+	}
+	if vhd, err := wsl.VHDPath(distroName); err == nil {
+		details.VHDPath = vhd
+		if size, err := wsl.GetVHDSize(vhd); err == nil {
+			details.VHDSize = size
+		}
+	} else if details.Version == "2" {
+		warnings = append(warnings, err.Error())
+	}
+
+	// Reading files inside a stopped distro would boot it, so only look when it is running
+	if strings.EqualFold(details.State, "Running") {
+		if conf, err := wsl.ReadFile(distroName, "/etc/wsl.conf"); err == nil {
+			details.WSLConf = strings.TrimSpace(conf)
+		}
+		if mem, err := wsl.Memory(distroName); err == nil {
+			details.Memory = mem
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+		if procs, err := wsl.Processes(distroName); err == nil {
+			details.Processes = procs
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	switch inspectOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(details)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(details); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	printDistroDetails(details)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", strings.SplitN(w, "\n", 2)[0])
+	}
+	return nil
+}
+
+// printDistroDetails prints the table view of inspect
+func printDistroDetails(d *distroDetails) {
+	fmt.Printf("\nDistribution: %s\n", d.Name)
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("State:        %s\n", d.State)
+	fmt.Printf("WSL version:  %s\n", d.Version)
+	fmt.Printf("Default:      %t\n", d.Default)
+	if d.InstallPath != "" {
+		fmt.Printf("Install path: %s\n", d.InstallPath)
+	}
+	if d.VHDPath != "" {
+		fmt.Printf("Virtual disk: %s (%.2f MB)\n", d.VHDPath, float64(d.VHDSize)/1024/1024)
+	}
+	if m := d.Metadata; m != nil {
+		fmt.Printf("Installed:    %s\n", m.InstalledAt.Local().Format("2006-01-02 15:04"))
+		if m.DistroVersion != "" {
+			fmt.Printf("Catalog:      %s - %s (%s)\n", m.DistroGroup, m.DistroVersion, m.PackageID)
+		}
+		if m.Source != "" {
+			fmt.Printf("Source:       %s\n", m.Source)
+		}
+		if m.TargetArch != "" {
+			fmt.Printf("Arch:         %s (forced at install)\n", m.TargetArch)
+		}
+		if len(m.PlaybooksRun) > 0 {
+			fmt.Printf("Playbooks:    %s\n", strings.Join(m.PlaybooksRun, ", "))
+		}
+		if len(m.Tags) > 0 {
+			fmt.Printf("Tags:         %s\n", strings.Join(m.Tags, ", "))
+		}
+	}
+
+	if !strings.EqualFold(d.State, "Running") {
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("Start the distribution to see wsl.conf, memory, and processes.")
+		return
+	}
+
+	if d.Memory != nil {
+		fmt.Printf("Memory:       %.0f MB used / %.0f MB total\n",
+			float64(d.Memory.UsedKB())/1024, float64(d.Memory.TotalKB)/1024)
+	}
+	fmt.Printf("Processes:    %d\n", len(d.Processes))
+	fmt.Println(strings.Repeat("=", 60))
+
+	fmt.Println("\n/etc/wsl.conf:")
+	if d.WSLConf == "" {
+		fmt.Println("  (not present)")
+	} else {
+		for _, line := range strings.Split(d.WSLConf, "\n") {
+			fmt.Printf("  %s\n", strings.TrimRight(line, "\r"))
+		}
+	}
+
+	if len(d.Processes) > 0 {
+		fmt.Println("\nProcesses:")
+		for _, p := range d.Processes {
+			fmt.Printf("  %6d  %s\n", p.PID, p.Name)
+		}
+	}
+}
@@ -5,11 +5,19 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/checksum"
 	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/downloader"
 	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/metadata"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/windowsterminal"
 	"github.com/yuanjua/autowsl/internal/winget"
 	"github.com/yuanjua/autowsl/internal/wsl"
 )
@@ -20,10 +28,39 @@ var (
 	installKeepTar    bool
 	installPlaybooks  []string
 	installExtraVars  []string
+	installVarsFile   string
+	installAnsibleCfg string
 	installTags       []string
+	installTagsFile   string
+	installSkipFile   string
+	installSkipTags   []string
+	installLimit      string
+	installForks      int
+	installOutputLog  string
+	installAnsibleVer string
 	installVerbose    bool
 	installWSLVersion int
 	installFromTar    string
+	installURL        string
+	installMaxRetries int
+
+	installForceDirect    bool
+	installGroup          string
+	installArch           string
+	installVerifyChecksum bool
+
+	installVaultPasswordFile string
+	installAskVaultPass      bool
+	installVaultIDs          []ansible.VaultID
+	installSkipGalaxy        bool
+	installGalaxyColls       string
+	installGalaxyRoles       string
+	installBecomePassFile    string
+	installSyntaxCheck       bool
+	installSkipValidate      bool
+	installCheck             bool
+	installDiff              bool
+	installContinueOnErr     bool
 )
 
 var installCmd = &cobra.Command{
@@ -39,11 +76,20 @@ Examples:
 	# Direct installation
 	autowsl install "Ubuntu 22.04 LTS"
 
+	# Choose only among the Ubuntu versions
+	autowsl install --group ubuntu
+
 	# Install from a tar file
 	autowsl install --from welcome-to-docker.tar --name docker-demo
 
+	# Install a custom rootfs from an internal server
+	autowsl install --url https://files.example.com/wsl/dev-rootfs.tar.gz --name dev
+
 	# Specify WSL 1 instead of default WSL 2
 	autowsl install "Ubuntu 22.04 LTS" --version 1
+
+	# Pick the x64 package on an ARM64 host (a non-native rootfs may not boot)
+	autowsl install "Ubuntu 22.04 LTS" --arch x64
 	
 	# Install then run a single playbook (file)
 	autowsl install "Ubuntu 22.04 LTS" --playbooks ./setup.yml
@@ -63,10 +109,39 @@ func init() {
 	installCmd.Flags().BoolVar(&installKeepTar, "keep-tar", false, "Keep the extracted tar file for future use")
 	installCmd.Flags().StringSliceVar(&installPlaybooks, "playbooks", []string{}, "Playbook files, URLs, or aliases (comma-separated or repeat flag)")
 	installCmd.Flags().StringArrayVar(&installExtraVars, "extra-vars", nil, "Extra variables in key=val format (repeatable)")
+	installCmd.Flags().StringVar(&installVarsFile, "extra-vars-file", "", "JSON or YAML file of extra variables; --extra-vars values override it")
+	installCmd.Flags().StringVar(&installAnsibleCfg, "ansible-cfg", "", "Custom ansible.cfg to run the playbooks with (sets ANSIBLE_CONFIG)")
 	installCmd.Flags().StringSliceVar(&installTags, "tags", []string{}, "Ansible tags to run (comma-separated)")
+	installCmd.Flags().StringSliceVar(&installSkipTags, "skip-tags", []string{}, "Ansible tags to skip (comma-separated)")
+	installCmd.Flags().StringVar(&installTagsFile, "tags-file", "", "YAML file listing more tags to run (tags: [a, b]), merged with --tags")
+	installCmd.Flags().StringVar(&installSkipFile, "skip-tags-file", "", "YAML file listing more tags to skip (tags: [a, b]), merged with --skip-tags")
+	installCmd.Flags().IntVar(&installForks, "forks", 0, "Parallel processes for ansible-playbook --forks (0 = Ansible default; little effect in local mode)")
+	installCmd.Flags().StringVar(&installLimit, "limit", "", "Ansible host pattern for --limit (localhost or all are the only hosts in local mode)")
+	installCmd.Flags().StringVar(&installOutputLog, "output-log", "", "Also append Ansible output to this file")
+	installCmd.Flags().StringVar(&installAnsibleVer, "ansible-version", "", "Minimum Ansible version the playbooks need (e.g. 2.14); fail early if older")
 	installCmd.Flags().BoolVarP(&installVerbose, "verbose", "v", false, "Verbose Ansible output")
 	installCmd.Flags().IntVar(&installWSLVersion, "version", 2, "WSL version to use (1 or 2)")
 	installCmd.Flags().StringVar(&installFromTar, "from", "", "Install from an existing tar file instead of downloading")
+	installCmd.Flags().StringVar(&installGroup, "group", "", "Distribution group to choose a version from (e.g. Ubuntu); skips the group prompt")
+	installCmd.Flags().StringVar(&installURL, "url", "", "Install from a rootfs tarball or appx package at this URL instead of the catalog")
+	installCmd.Flags().StringVar(&installArch, "arch", "", "Package architecture to install, x64 or arm64 (default: the host's); other architectures may not boot")
+	installCmd.Flags().BoolVar(&installForceDirect, "force-direct-download", false, "Download from the catalog's direct URL even when winget is available")
+	installCmd.Flags().BoolVar(&installVerifyChecksum, "verify-checksum", false, "Fail if the download does not match the catalog's SHA256 checksum")
+	installCmd.Flags().IntVar(&installMaxRetries, "max-retries", 3, "Retries for transient network failures when downloading the distribution or playbooks")
+	installCmd.Flags().StringVar(&installVaultPasswordFile, "vault-password-file", "", "Ansible Vault password file")
+	installCmd.Flags().BoolVar(&installAskVaultPass, "ask-vault-pass", false, "Prompt for the Ansible Vault password")
+	installCmd.MarkFlagsMutuallyExclusive("vault-password-file", "ask-vault-pass")
+	installCmd.Flags().Var(vaultIDsValue{&installVaultIDs}, "vault-id", "Ansible vault ID as label@file or label@prompt (repeatable)")
+	installCmd.Flags().StringVar(&installBecomePassFile, "become-password-file", "", "File with the sudo password for Ansible privilege escalation")
+	installCmd.Flags().BoolVar(&installSkipGalaxy, "skip-galaxy-install", false, "Don't install collections from a requirements.yml next to the playbook")
+	installCmd.Flags().StringVar(&installGalaxyColls, "galaxy-collections-file", "", "Requirements file of Galaxy collections to install before the playbooks")
+	installCmd.Flags().StringVar(&installGalaxyRoles, "galaxy-roles-file", "", "Requirements file of Galaxy roles to install before the playbooks")
+	installCmd.Flags().BoolVar(&installCheck, "check", false, "Run Ansible in check mode: report what would change without changing it")
+	installCmd.Flags().BoolVar(&installDiff, "diff", false, "Show the changes Ansible makes (or would make with --check) to files")
+	installCmd.Flags().BoolVar(&installSkipValidate, "skip-validation", false, "Don't check the YAML structure of the playbooks before provisioning")
+	installCmd.Flags().BoolVar(&installSyntaxCheck, "syntax-check", false, "Check the syntax of all playbooks before running any of them")
+	installCmd.Flags().BoolVar(&installContinueOnErr, "continue-on-error", false, "Run the remaining playbooks when one fails instead of stopping")
+	_ = installCmd.RegisterFlagCompletionFunc("playbooks", completePlaybookAliases)
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
@@ -75,34 +150,74 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("WSL is not available: %w\nPlease install WSL first: https://docs.microsoft.com/en-us/windows/wsl/install", err)
 	}
 
+	// Catch a broken vars file before spending time on the download
+	if installVarsFile != "" && len(installPlaybooks) > 0 {
+		if err := ansible.ValidateExtraVarsFile(installVarsFile); err != nil {
+			return err
+		}
+	}
+	for _, tagsFile := range []string{installTagsFile, installSkipFile} {
+		if tagsFile != "" && len(installPlaybooks) > 0 {
+			if _, err := ansible.LoadTagsFile(tagsFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	if installFromTar != "" && installURL != "" {
+		return fmt.Errorf("--from and --url cannot be used together")
+	}
+
+	if installArch != "" {
+		arch, err := system.ParseArchitecture(installArch)
+		if err != nil {
+			return err
+		}
+		installArch = string(arch)
+		if host := system.GetHostArchitecture(); arch != host {
+			fmt.Printf("⚠ Installing the %s package on a %s host; it may not boot under WSL\n", arch, host)
+		}
+	}
+
 	// Check if installing from tar file
 	if installFromTar != "" {
 		return runInstallFromTar(args)
 	}
 
+	if installURL != "" {
+		return runInstallFromURL(args)
+	}
+
+	if len(args) == 0 && activeProfile != nil && activeProfile.DefaultDistroVersion != "" {
+		args = []string{activeProfile.DefaultDistroVersion}
+	}
+
 	// Use shared helper for distro selection
-	selectedDistro, err := selectDistro(args)
+	selectedDistro, err := selectDistro(args, installGroup)
 	if err != nil {
 		return err
 	}
 
-	// Check if distro has packageId
-	if selectedDistro.PackageID == "" {
-		return fmt.Errorf("distribution '%s' does not have a winget package ID", selectedDistro.Version)
+	if selectedDistro.PackageID == "" && selectedDistro.URL == "" {
+		return fmt.Errorf("distribution '%s' has neither a winget package ID nor a direct download URL", selectedDistro.Version)
 	}
+	forceDirect := installForceDirect || selectedDistro.PackageID == ""
 
 	isInteractive := len(args) == 0
 
 	// Determine installation name
 	distroName := installName
 	if distroName == "" {
-		distroName = generateDistroName(selectedDistro)
+		distroName, err = generateDistroName(selectedDistro)
+		if err != nil {
+			return err
+		}
 		if isInteractive {
 			namePrompt := promptui.Prompt{
 				Label:   "Distribution name",
 				Default: distroName,
 			}
-			if customName, err := namePrompt.Run(); err != nil {
+			if customName, err := ui.Prompt(namePrompt); err != nil {
 				return fmt.Errorf("failed to get distribution name: %w", err)
 			} else {
 				distroName = customName
@@ -110,6 +225,10 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err := wsl.ValidateDistroName(distroName); err != nil {
+		return err
+	}
+
 	// Check if distro already exists
 	exists, err := wsl.IsDistroInstalled(distroName)
 	if err != nil {
@@ -122,14 +241,13 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	// Determine installation path
 	distroPath := installPath
 	if distroPath == "" {
-		cwd, _ := os.Getwd()
-		distroPath = filepath.Join(cwd, "wsl-distros", distroName)
+		distroPath = filepath.Join(defaultInstallRoot(), distroName)
 		if isInteractive {
 			pathPrompt := promptui.Prompt{
 				Label:   "Installation path",
 				Default: distroPath,
 			}
-			if customPath, err := pathPrompt.Run(); err != nil {
+			if customPath, err := ui.Prompt(pathPrompt); err != nil {
 				return fmt.Errorf("failed to get installation path: %w", err)
 			} else {
 				distroPath = customPath
@@ -146,7 +264,11 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Installation Configuration\n")
 	fmt.Printf("%s\n", strings.Repeat("=", 60))
 	fmt.Printf("Distribution: %s - %s (%s)\n", selectedDistro.Group, selectedDistro.Version, selectedDistro.Architecture)
-	fmt.Printf("Package ID:   %s\n", selectedDistro.PackageID)
+	if forceDirect {
+		fmt.Printf("URL:          %s\n", selectedDistro.URL)
+	} else {
+		fmt.Printf("Package ID:   %s\n", selectedDistro.PackageID)
+	}
 	fmt.Printf("Name:         %s\n", distroName)
 	fmt.Printf("Path:         %s\n", distroPath)
 	fmt.Printf("WSL Version:  %d\n", installWSLVersion)
@@ -155,37 +277,47 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("%s\n\n", strings.Repeat("=", 60))
 
+	if dryRun {
+		return runInstallDryRun(selectedDistro, distroName, distroPath, forceDirect)
+	}
+
 	// Create temporary directory in current working directory
-	cwd, _ := os.Getwd()
-	tempDir := filepath.Join(cwd, ".autowsl_tmp")
+	tempDir := tempDirPath()
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
 		return fmt.Errorf("failed to create temp directory '%s': %w", tempDir, err)
 	}
+	releaseLock, err := extractor.AcquireLock(tempDir)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
 
-	// Download the distribution using winget
+	// Download the distribution using winget, or directly when it is unavailable
 	fmt.Println("→ Downloading distribution...")
 	mgr := winget.NewManager(tempDir)
-
-	// Check if winget is available
-	if !mgr.IsWingetAvailable() {
-		_ = extractor.CleanupTempDir(tempDir)
-		return fmt.Errorf("winget is not available. Please install 'App Installer' from Microsoft Store")
-	}
-
 	downloadedFile, err := mgr.Download(winget.DownloadOptions{
-		PackageID: selectedDistro.PackageID,
+		PackageID:      selectedDistro.PackageID,
+		Distro:         selectedDistro,
+		ForceDirect:    forceDirect,
+		VerifyChecksum: installVerifyChecksum,
+		MaxAttempts:    installMaxRetries + 1,
 	})
 	if err != nil {
-		_ = extractor.CleanupTempDir(tempDir)
+		_ = cleanupTempDir(tempDir)
 		return fmt.Errorf("failed to download '%s': %w", selectedDistro.Version, err)
 	}
 	fmt.Println("  ✓ Download completed")
 
-	fmt.Println("\n→ Extracting package...")
-	tarFilePath, err := extractor.ExtractAppx(downloadedFile, tempDir)
-	if err != nil {
-		_ = extractor.CleanupTempDir(tempDir)
-		return fmt.Errorf("failed to extract package '%s': %w", filepath.Base(downloadedFile), err)
+	// Direct downloads may already be a rootfs tarball
+	tarFilePath := downloadedFile
+	isTar := downloader.DetectPackageType(downloadedFile, "") == downloader.PackageTar
+	if !isTar {
+		fmt.Println("\n→ Extracting package...")
+		tarFilePath, err = extractor.ExtractAppx(downloadedFile, tempDir, installExtractOptions())
+		if err != nil {
+			_ = cleanupTempDir(tempDir)
+			return fmt.Errorf("failed to extract package '%s': %w", filepath.Base(downloadedFile), err)
+		}
 	}
 
 	fmt.Printf("  ✓ Found rootfs: %s\n\n", filepath.Base(tarFilePath))
@@ -200,28 +332,35 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 
 	if err := wsl.Import(importOpts); err != nil {
-		_ = extractor.CleanupTempDir(tempDir)
+		_ = cleanupTempDir(tempDir)
 		return fmt.Errorf("failed to import distribution '%s' to '%s': %w", distroName, distroPath, err)
 	}
 
 	fmt.Println("  ✓ Import completed successfully")
 
+	writeInstallMetadata(distroPath, metadata.Metadata{
+		CatalogVersion: Version,
+		PackageID:      selectedDistro.PackageID,
+		DistroGroup:    selectedDistro.Group,
+		DistroVersion:  selectedDistro.Version,
+		WSLVersion:     installWSLVersion,
+		TargetArch:     installArch,
+	})
+
 	// Cleanup temporary directory
 	if installKeepTar {
 		fmt.Printf("\n→ Keeping tar file: %s\n", tarFilePath)
 		fmt.Println("  (You can use this for future installations)")
 
-		// Remove only the downloaded appx/appxbundle
-		if err := os.Remove(downloadedFile); err != nil {
-			fmt.Printf("  ⚠ Warning: Failed to remove downloaded package: %v\n", err)
+		// Remove only the downloaded appx/appxbundle; a tarball download is the tar file itself
+		if !isTar {
+			if err := os.Remove(downloadedFile); err != nil {
+				fmt.Printf("  ⚠ Warning: Failed to remove downloaded package: %v\n", err)
+			}
+			_ = os.Remove(checksum.SidecarPath(downloadedFile))
 		}
 	} else {
-		fmt.Println("\n→ Cleaning up temporary files...")
-		if err := extractor.CleanupTempDir(tempDir); err != nil {
-			fmt.Printf("  ⚠ Warning: Failed to cleanup temp directory: %v\n", err)
-		} else {
-			fmt.Println("  ✓ Cleanup completed")
-		}
+		finishTempDir(tempDir)
 	}
 
 	// Print success message with details
@@ -234,8 +373,13 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	if installKeepTar {
 		fmt.Printf("Tar file: %s\n", tarFilePath)
 	}
+	if noCleanup {
+		fmt.Printf("Temp dir: %s\n", tempDir)
+	}
 	fmt.Println(strings.Repeat("=", 60))
 
+	offerTerminalProfile(distroName)
+
 	// Parse extra vars for provisioning
 	var extraVarsSlice []string
 	if len(installExtraVars) > 0 {
@@ -249,26 +393,47 @@ func runInstall(cmd *cobra.Command, args []string) error {
 			DistroName:     distroName,
 			PlaybookInputs: installPlaybooks,
 			Tags:           installTags,
+			SkipTags:       installSkipTags,
+			TagsFile:       installTagsFile,
+			SkipTagsFile:   installSkipFile,
+			Limit:          installLimit,
+			Forks:          installForks,
+			OutputLog:      installOutputLog,
 			Verbose:        installVerbose,
 			ExtraVars:      extraVarsSlice,
+			ExtraVarsFile:  installVarsFile,
+			AnsibleCfg:     installAnsibleCfg,
 			TempDir:        tempDir,
+			MaxRetries:     installMaxRetries,
+
+			VaultPasswordFile: installVaultPasswordFile,
+			AskVaultPass:      installAskVaultPass,
+			VaultIDs:          installVaultIDs,
+			SkipGalaxyInstall: installSkipGalaxy,
+
+			GalaxyCollectionsFile: installGalaxyColls,
+			GalaxyRolesFile:       installGalaxyRoles,
+
+			ValidatePlaybooks: !installSkipValidate,
+
+			BecomePasswordFile:     installBecomePassFile,
+			RequiredAnsibleVersion: installAnsibleVer,
+			SyntaxCheck:            installSyntaxCheck,
+			CheckMode:              installCheck,
+			DiffMode:               installDiff,
+			ContinueOnError:        installContinueOnErr,
 		})
 
 		if err != nil {
 			if !installKeepTar {
-				_ = extractor.CleanupTempDir(tempDir)
+				_ = cleanupTempDir(tempDir)
 			}
 			return nil // runProvisioningPipeline already printed errors
 		}
 
 		// Cleanup temp dir after successful provisioning (unless keep-tar is set)
 		if !installKeepTar {
-			fmt.Println("\n→ Cleaning up temporary files...")
-			if err := extractor.CleanupTempDir(tempDir); err != nil {
-				fmt.Printf("  ⚠ Warning: Failed to cleanup temp directory: %v\n", err)
-			} else {
-				fmt.Println("  ✓ Cleanup completed")
-			}
+			finishTempDir(tempDir)
 		}
 	} else {
 		// No provisioning requested
@@ -280,12 +445,168 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runInstallDryRun prints the steps an install would take without downloading
+// anything or touching the file system
+func runInstallDryRun(selectedDistro distro.Distro, distroName, distroPath string, forceDirect bool) error {
+	tempDir := tempDirPath()
+	if forceDirect {
+		fmt.Printf("[dry-run] would download %s into %s\n", selectedDistro.URL, tempDir)
+	} else {
+		fmt.Printf("[dry-run] would download %s with winget into %s\n", selectedDistro.PackageID, tempDir)
+	}
+	fmt.Printf("[dry-run] would extract the rootfs tar into %s\n", tempDir)
+
+	if err := wsl.Import(wsl.ImportOptions{
+		Name:        distroName,
+		InstallPath: distroPath,
+		TarFilePath: filepath.Join(tempDir, "install.tar.gz"),
+		Version:     installWSLVersion,
+	}); err != nil {
+		return fmt.Errorf("failed to import distribution '%s' to '%s': %w", distroName, distroPath, err)
+	}
+
+	if len(installPlaybooks) > 0 {
+		return runProvisioningPipeline(ProvisioningPipelineOptions{
+			DistroName:     distroName,
+			PlaybookInputs: installPlaybooks,
+			Tags:           installTags,
+			SkipTags:       installSkipTags,
+			TagsFile:       installTagsFile,
+			SkipTagsFile:   installSkipFile,
+			Limit:          installLimit,
+			Forks:          installForks,
+			OutputLog:      installOutputLog,
+			Verbose:        installVerbose,
+			ExtraVars:      installExtraVars,
+			ExtraVarsFile:  installVarsFile,
+			AnsibleCfg:     installAnsibleCfg,
+			TempDir:        tempDir,
+			MaxRetries:     installMaxRetries,
+
+			VaultPasswordFile: installVaultPasswordFile,
+			AskVaultPass:      installAskVaultPass,
+			VaultIDs:          installVaultIDs,
+			SkipGalaxyInstall: installSkipGalaxy,
+
+			GalaxyCollectionsFile: installGalaxyColls,
+			GalaxyRolesFile:       installGalaxyRoles,
+
+			ValidatePlaybooks: !installSkipValidate,
+
+			BecomePasswordFile:     installBecomePassFile,
+			RequiredAnsibleVersion: installAnsibleVer,
+			SyntaxCheck:            installSyntaxCheck,
+			CheckMode:              installCheck,
+			DiffMode:               installDiff,
+			ContinueOnError:        installContinueOnErr,
+		})
+	}
+	return nil
+}
+
+// offerTerminalProfile offers to add a Windows Terminal profile for a new
+// distribution, or prints the profile JSON when Windows Terminal is not found
+func offerTerminalProfile(distroName string) {
+	if dryRun {
+		return
+	}
+
+	profile, err := windowsterminal.GenerateProfile(distroName)
+	if err != nil {
+		fmt.Printf("  ⚠ Warning: Failed to generate Windows Terminal profile: %v\n", err)
+		return
+	}
+	snippet, err := profile.JSON()
+	if err != nil {
+		fmt.Printf("  ⚠ Warning: %v\n", err)
+		return
+	}
+
+	settingsPath, err := windowsterminal.FindSettingsFile()
+	if err != nil {
+		fmt.Println("\nWindows Terminal settings not found. Add this profile manually:")
+		fmt.Println(snippet)
+		return
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Add a Windows Terminal profile for '%s'", distroName),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		return
+	}
+
+	if err := windowsterminal.AppendProfile(settingsPath, profile); err != nil {
+		fmt.Printf("  ⚠ Warning: Failed to update Windows Terminal settings: %v\n", err)
+		fmt.Println("Add this profile manually:")
+		fmt.Println(snippet)
+		return
+	}
+	fmt.Println("  ✓ Windows Terminal profile added")
+}
+
+// printExtractProgress shows extraction progress on one line, in the style
+// of the download progress
+func printExtractProgress(extracted, total int64) {
+	const mb = 1024 * 1024
+	fmt.Printf("\r  Extracting: %.0f MB / %.0f MB    ", float64(extracted)/mb, float64(total)/mb)
+	if extracted >= total {
+		fmt.Println()
+	}
+}
+
+// installExtractOptions returns the package extraction options for --arch
+func installExtractOptions() extractor.ExtractOptions {
+	return extractor.ExtractOptions{
+		Progress:  printExtractProgress,
+		ForceArch: system.HostArchitecture(installArch),
+	}
+}
+
+// writeInstallMetadata records how a distribution was installed in the
+// .autowsl.json file of its install directory
+func writeInstallMetadata(distroPath string, m metadata.Metadata) {
+	if dryRun {
+		return
+	}
+	m.InstalledAt = time.Now().UTC()
+	if err := metadata.Write(distroPath, m); err != nil {
+		fmt.Printf("  ⚠ Warning: Failed to write distribution metadata: %v\n", err)
+	}
+}
+
 // generateDistroName generates a default distribution name from the distro version
-func generateDistroName(d distro.Distro) string {
+func generateDistroName(d distro.Distro) (string, error) {
 	// Clean up the version name to create a valid distro name
 	name := strings.ReplaceAll(d.Version, " ", "-")
 	name = strings.ReplaceAll(name, ".", "")
 	name = strings.ToLower(name)
+	name = sanitizeDistroName(name)
+	if err := wsl.ValidateDistroName(name); err != nil {
+		return "", fmt.Errorf("cannot derive a distribution name from '%s': %w (use --name)", d.Version, err)
+	}
+	return name, nil
+}
+
+// sanitizeDistroName replaces characters WSL does not accept in distribution names
+func sanitizeDistroName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	name = b.String()
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	name = strings.Trim(name, "-")
+	if len(name) > wsl.MaxDistroNameLength {
+		name = strings.TrimRight(name[:wsl.MaxDistroNameLength], "-")
+	}
 	return name
 }
 
@@ -306,13 +627,14 @@ func runInstallFromTar(args []string) error {
 		defaultName := strings.TrimSuffix(tarBaseName, ".tar")
 		defaultName = strings.ReplaceAll(defaultName, " ", "-")
 		defaultName = strings.ToLower(defaultName)
+		defaultName = sanitizeDistroName(defaultName)
 
 		if isInteractive {
 			namePrompt := promptui.Prompt{
 				Label:   "Distribution name",
 				Default: defaultName,
 			}
-			if customName, err := namePrompt.Run(); err != nil {
+			if customName, err := ui.Prompt(namePrompt); err != nil {
 				return fmt.Errorf("failed to get distribution name: %w", err)
 			} else {
 				distroName = customName
@@ -322,6 +644,10 @@ func runInstallFromTar(args []string) error {
 		}
 	}
 
+	if err := wsl.ValidateDistroName(distroName); err != nil {
+		return err
+	}
+
 	// Check if distro already exists
 	exists, err := wsl.IsDistroInstalled(distroName)
 	if err != nil {
@@ -334,14 +660,13 @@ func runInstallFromTar(args []string) error {
 	// Determine installation path
 	distroPath := installPath
 	if distroPath == "" {
-		cwd, _ := os.Getwd()
-		distroPath = filepath.Join(cwd, "wsl-distros", distroName)
+		distroPath = filepath.Join(defaultInstallRoot(), distroName)
 		if isInteractive {
 			pathPrompt := promptui.Prompt{
 				Label:   "Installation path",
 				Default: distroPath,
 			}
-			if customPath, err := pathPrompt.Run(); err != nil {
+			if customPath, err := ui.Prompt(pathPrompt); err != nil {
 				return fmt.Errorf("failed to get installation path: %w", err)
 			} else {
 				distroPath = customPath
@@ -388,6 +713,16 @@ func runInstallFromTar(args []string) error {
 
 	fmt.Println("  ✓ Import completed successfully")
 
+	source := absTarPath
+	if installURL != "" {
+		source = installURL
+	}
+	writeInstallMetadata(distroPath, metadata.Metadata{
+		Source:     source,
+		WSLVersion: installWSLVersion,
+		TargetArch: installArch,
+	})
+
 	// Print success message with details
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Printf("✓ SUCCESS: WSL distribution installed from tar\n")
@@ -398,6 +733,8 @@ func runInstallFromTar(args []string) error {
 	fmt.Printf("Source:   %s\n", absTarPath)
 	fmt.Println(strings.Repeat("=", 60))
 
+	offerTerminalProfile(distroName)
+
 	// Parse extra vars for provisioning
 	var extraVarsSlice []string
 	if len(installExtraVars) > 0 {
@@ -405,8 +742,7 @@ func runInstallFromTar(args []string) error {
 	}
 
 	// Create temp directory for provisioning if needed
-	cwd, _ := os.Getwd()
-	tempDir := filepath.Join(cwd, ".autowsl_tmp")
+	tempDir := tempDirPath()
 
 	// Hyper Pipeline: Auto-provision if playbooks are specified
 	if len(installPlaybooks) > 0 {
@@ -415,9 +751,35 @@ func runInstallFromTar(args []string) error {
 			DistroName:     distroName,
 			PlaybookInputs: installPlaybooks,
 			Tags:           installTags,
+			SkipTags:       installSkipTags,
+			TagsFile:       installTagsFile,
+			SkipTagsFile:   installSkipFile,
+			Limit:          installLimit,
+			Forks:          installForks,
+			OutputLog:      installOutputLog,
 			Verbose:        installVerbose,
 			ExtraVars:      extraVarsSlice,
+			ExtraVarsFile:  installVarsFile,
+			AnsibleCfg:     installAnsibleCfg,
 			TempDir:        tempDir,
+			MaxRetries:     installMaxRetries,
+
+			VaultPasswordFile: installVaultPasswordFile,
+			AskVaultPass:      installAskVaultPass,
+			VaultIDs:          installVaultIDs,
+			SkipGalaxyInstall: installSkipGalaxy,
+
+			GalaxyCollectionsFile: installGalaxyColls,
+			GalaxyRolesFile:       installGalaxyRoles,
+
+			ValidatePlaybooks: !installSkipValidate,
+
+			BecomePasswordFile:     installBecomePassFile,
+			RequiredAnsibleVersion: installAnsibleVer,
+			SyntaxCheck:            installSyntaxCheck,
+			CheckMode:              installCheck,
+			DiffMode:               installDiff,
+			ContinueOnError:        installContinueOnErr,
 		})
 
 		if err != nil {
@@ -432,3 +794,100 @@ func runInstallFromTar(args []string) error {
 
 	return nil
 }
+
+// runInstallFromURL downloads a rootfs tarball or appx package from --url,
+// bypassing winget and the catalog, and then installs it like --from
+func runInstallFromURL(args []string) error {
+	if installName == "" {
+		installName = distroNameFromURL(installURL)
+	}
+	if err := wsl.ValidateDistroName(installName); err != nil {
+		return fmt.Errorf("%w (use --name)", err)
+	}
+
+	// Fail before a potentially large download if the name is taken
+	exists, err := wsl.IsDistroInstalled(installName)
+	if err != nil {
+		return fmt.Errorf("failed to check existing distributions: %w", err)
+	}
+	if exists && !dryRun {
+		return fmt.Errorf("distribution '%s' already exists", installName)
+	}
+
+	tempDir := tempDirPath()
+	if dryRun {
+		distroPath := installPath
+		if distroPath == "" {
+			distroPath = filepath.Join(defaultInstallRoot(), installName)
+		}
+		fmt.Printf("[dry-run] would download %s into %s\n", installURL, tempDir)
+		fmt.Printf("[dry-run] would extract the rootfs tar if it is an appx package\n")
+		return wsl.Import(wsl.ImportOptions{
+			Name:        installName,
+			InstallPath: distroPath,
+			TarFilePath: filepath.Join(tempDir, "install.tar.gz"),
+			Version:     installWSLVersion,
+		})
+	}
+
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return fmt.Errorf("failed to create temp directory '%s': %w", tempDir, err)
+	}
+	releaseLock, err := extractor.AcquireLock(tempDir)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
+	fmt.Printf("→ Downloading %s...\n", installURL)
+	d := downloader.New()
+	d.MaxAttempts = installMaxRetries + 1
+	downloadedFile, kind, err := d.DownloadURL(installURL, tempDir)
+	if err != nil {
+		_ = cleanupTempDir(tempDir)
+		return fmt.Errorf("failed to download '%s': %w", installURL, err)
+	}
+	fmt.Println("  ✓ Download completed")
+
+	tarFilePath := downloadedFile
+	if kind == downloader.PackageAppx {
+		fmt.Println("\n→ Extracting package...")
+		tarFilePath, err = extractor.ExtractAppx(downloadedFile, tempDir, installExtractOptions())
+		if err != nil {
+			_ = cleanupTempDir(tempDir)
+			return fmt.Errorf("failed to extract package '%s': %w", filepath.Base(downloadedFile), err)
+		}
+		fmt.Printf("  ✓ Found rootfs: %s\n", filepath.Base(tarFilePath))
+	}
+
+	installFromTar = tarFilePath
+	installErr := runInstallFromTar(args)
+
+	if installKeepTar {
+		if kind == downloader.PackageAppx {
+			_ = os.Remove(downloadedFile)
+		}
+		fmt.Printf("\n→ Keeping tar file: %s\n", tarFilePath)
+	} else if noCleanup {
+		fmt.Printf("\n→ Keeping temporary files (--no-cleanup): %s\n", tempDir)
+	} else if err := extractor.CleanupTempDir(tempDir); err != nil {
+		fmt.Printf("  ⚠ Warning: Failed to cleanup temp directory: %v\n", err)
+	}
+	return installErr
+}
+
+// distroNameFromURL derives a default distribution name from the last path
+// segment of a URL, e.g. ".../dev-rootfs.tar.gz?sig=..." becomes "dev-rootfs"
+func distroNameFromURL(rawURL string) string {
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	name := strings.ToLower(rawURL[strings.LastIndex(rawURL, "/")+1:])
+	for _, ext := range []string{".tar.gz", ".tar.xz", ".tgz", ".txz", ".tar", ".appxbundle", ".appx", ".msixbundle", ".msix", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	return sanitizeDistroName(name)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	ipAll       bool
+	ipStopAfter bool
+)
+
+var ipCmd = &cobra.Command{
+	Use:   "ip [distro-name]",
+	Short: "Print the WSL2 IP address of a distribution",
+	Long: `Print the IPv4 address of a distribution's eth0 interface. WSL2 assigns a new
+address on every restart. A stopped distribution is started first.
+
+The output is plain text for use in scripts: just the address, or one
+"<name> <address>" line per distribution with --all.
+
+Examples:
+  autowsl ip ubuntu-2204
+
+  # Don't leave the distribution running if it had to be started
+  autowsl ip ubuntu-2204 --stop-after
+
+  # Addresses of all running distributions
+  autowsl ip --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIP,
+}
+
+func init() {
+	rootCmd.AddCommand(ipCmd)
+	ipCmd.Flags().BoolVar(&ipAll, "all", false, "Print the addresses of all running distributions")
+	ipCmd.Flags().BoolVar(&ipStopAfter, "stop-after", false, "Stop the distribution again if it had to be started")
+	ipCmd.ValidArgsFunction = completeInstalledDistros
+}
+
+func runIP(cmd *cobra.Command, args []string) error {
+	if ipAll {
+		if len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with a distribution name")
+		}
+		return printAllIPs()
+	}
+
+	var distroName string
+	if len(args) > 0 {
+		distroName = args[0]
+	} else {
+		var err error
+		distroName, err = selectInstalledDistroInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	state, err := distroState(distroName)
+	if err != nil {
+		return err
+	}
+	if state == "" && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	started := false
+	if !strings.EqualFold(state, "Running") {
+		if err := wsl.Start(distroName); err != nil {
+			return err
+		}
+		started = true
+	}
+
+	ip, err := wsl.IPv4(distroName)
+	if started && ipStopAfter {
+		if stopErr := wsl.Stop(distroName); stopErr != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: %v\n", stopErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ip)
+	return nil
+}
+
+// printAllIPs prints "<name> <address>" for every running distribution
+func printAllIPs() error {
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+	for _, d := range distros {
+		if !strings.EqualFold(d.State, "Running") {
+			continue
+		}
+		ip, err := wsl.IPv4(d.Name)
+		if err != nil {
+			ip = "-"
+		}
+		fmt.Printf("%s %s\n", d.Name, ip)
+	}
+	return nil
+}
@@ -1,68 +1,263 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/batch"
+	"github.com/yuanjua/autowsl/internal/metadata"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/ui"
 	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	listOutput     string
+	backupCompress string
+	backupFormat   string
+)
+
+var (
+	listRunning bool
+	listStopped bool
+)
+
+var (
+	removeAll  bool
+	removeSpec string
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all installed WSL distributions",
-	RunE:  runList,
+	Long: `List all installed WSL distributions.
+
+Examples:
+  autowsl list
+  autowsl list --output json
+  autowsl list -o yaml
+
+  # Only running distributions
+  autowsl list --running`,
+	RunE: runList,
 }
 
 var removeCmd = &cobra.Command{
 	Use:   "remove <name>",
 	Short: "Remove a WSL distribution",
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runRemove,
+	Long: `Remove a WSL distribution, or several at once with --all or --from-spec.
+Removing several distributions asks you to type "yes" to confirm.
+
+Examples:
+  autowsl remove ubuntu-2204-lts
+
+  # Remove every installed distribution
+  autowsl remove --all
+
+  # Remove the distributions named in a batch spec file
+  autowsl remove --from-spec team.yml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRemove,
 }
 
 var backupCmd = &cobra.Command{
 	Use:   "backup <name>",
 	Short: "Backup a WSL distribution",
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runBackup,
+	Long: `Backup a WSL distribution to a tar file.
+
+With --output-format vhd a WSL 2 distribution is exported as a .vhdx disk
+image instead (Windows 11). Restore it with:
+  wsl --import <name> <install-dir> <file.vhdx> --vhd
+
+Examples:
+  autowsl backup ubuntu-2204-lts
+  autowsl backup ubuntu-2204-lts --compress gzip
+  autowsl backup ubuntu-2204-lts --compress xz
+  autowsl backup ubuntu-2204-lts --output-format vhd`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBackup,
 }
 
+var renameCmd = &cobra.Command{
+	Use:   "rename [old-name] [new-name]",
+	Short: "Rename a WSL distribution",
+	Long: `Rename an installed WSL distribution.
+The distribution is exported to a temporary tar file in .autowsl_tmp, imported
+under the new name next to its current location, and the old name is only
+unregistered once the import has succeeded.
+
+Examples:
+  # Interactive mode - select distro and enter the new name
+  autowsl rename
+
+  # Rename directly
+  autowsl rename ubuntu-2204-lts dev-box`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runRename,
+}
+
+var setDefaultCmd = &cobra.Command{
+	Use:   "set-default [distro-name]",
+	Short: "Set the default WSL distribution",
+	Long: `Set the distribution that 'wsl' starts when no -d option is given.
+
+Examples:
+  # Interactive mode - select from installed distros
+  autowsl set-default
+
+  # Set a specific distribution as default
+  autowsl set-default ubuntu-2204-lts`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSetDefault,
+}
+
+var defaultCmd = &cobra.Command{
+	Use:   "default",
+	Short: "Print the default WSL distribution",
+	Long: `Print the name of the distribution that 'wsl' starts when no -d option is given.
+Only the name is printed, so the output can be used in scripts.
+
+Examples:
+  autowsl default
+
+  # Use it in a PowerShell script
+  $distro = autowsl default`,
+	Args: cobra.NoArgs,
+	RunE: runDefault,
+}
+
+var startCmd = &cobra.Command{
+	Use:   "start <distro-name>",
+	Short: "Start a WSL distribution",
+	Long: `Start a WSL distribution in the background without opening a shell.
+WSL stops an idle distribution again after a few seconds unless something keeps
+running in it.
+
+Examples:
+  autowsl start ubuntu-2204-lts`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStart,
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop <distro-name>",
+	Short: "Stop a running WSL distribution",
+	Long: `Stop a running WSL distribution with 'wsl --terminate'. Open shells and
+processes in the distribution are killed, so you are asked to confirm first.
+
+Examples:
+  autowsl stop ubuntu-2204-lts
+  autowsl stop ubuntu-2204-lts --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStop,
+}
+
+var shutdownCmd = &cobra.Command{
+	Use:   "shutdown",
+	Short: "Stop all distributions and the WSL2 virtual machine",
+	Long: `Stop all running distributions and the WSL2 virtual machine with 'wsl --shutdown'.
+Needed after changing .wslconfig.
+
+Examples:
+  autowsl shutdown`,
+	Args: cobra.NoArgs,
+	RunE: runShutdown,
+}
+
+// startPollAttempts and startPollInterval bound how long start waits for the Running state
+const (
+	startPollAttempts = 10
+	startPollInterval = 500 * time.Millisecond
+)
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(removeCmd)
 	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(setDefaultCmd)
+	rootCmd.AddCommand(defaultCmd)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(shutdownCmd)
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", "table", "Output format: table, json, or yaml")
+	removeCmd.Flags().BoolVar(&removeAll, "all", false, "Remove every installed distribution")
+	removeCmd.Flags().StringVar(&removeSpec, "from-spec", "", "Remove the distributions named in a batch YAML spec file")
+	listCmd.Flags().BoolVar(&listRunning, "running", false, "Only list running distributions")
+	listCmd.Flags().BoolVar(&listStopped, "stopped", false, "Only list stopped distributions")
+	backupCmd.Flags().StringVar(&backupCompress, "compress", wsl.CompressionNone, "Compress the backup: none, gzip, or xz")
+	backupCmd.Flags().StringVar(&backupFormat, "output-format", "tar", "Backup format: tar, or vhd for a .vhdx disk image (Windows 11, WSL 2 only)")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	distros, err := wsl.ListInstalledDistros()
+	if listOutput != "table" && listOutput != "json" && listOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", listOutput)
+	}
+	if listRunning && listStopped {
+		return fmt.Errorf("--running and --stopped cannot be used together")
+	}
+
+	all, err := wsl.ListInstalledDistros()
 	if err != nil {
 		return fmt.Errorf("failed to list distributions: %w", err)
 	}
+	distros := wsl.FilterDistros(all, wsl.DistroFilter{Running: listRunning, Stopped: listStopped})
+	entries := listEntries(distros)
 
-	if len(distros) == 0 {
+	switch listOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(entries); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if len(all) == 0 {
 		fmt.Println("No WSL distributions are currently installed.")
 		fmt.Println("\nInstall one using: autowsl install")
 		return nil
 	}
+	if len(distros) == 0 {
+		fmt.Println("No WSL distributions match the filters.")
+		return nil
+	}
 
 	fmt.Println("\nInstalled WSL Distributions:")
 	fmt.Println()
 
 	// Create a tabwriter for nice formatting
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "DEFAULT\tNAME\tSTATE\tVERSION")
-	fmt.Fprintln(w, "-------\t----\t-----\t-------")
+	fmt.Fprintln(w, "DEFAULT\tNAME\tSTATE\tVERSION\tINSTALLED FROM")
+	fmt.Fprintln(w, "-------\t----\t-----\t-------\t--------------")
 
-	for _, d := range distros {
+	for _, d := range entries {
 		defaultMarker := " "
 		if d.Default {
 			defaultMarker = "*"
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", defaultMarker, d.Name, d.State, d.Version)
+		source := "-"
+		if d.Metadata != nil {
+			source = d.Metadata.DistroVersion
+			if source == "" {
+				source = filepath.Base(d.Metadata.Source)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", defaultMarker, d.Name, d.State, d.Version, source)
 	}
 
 	w.Flush()
@@ -71,7 +266,42 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// listEntry is an installed distribution plus its autowsl metadata, if any
+type listEntry struct {
+	wsl.InstalledDistro `yaml:",inline"`
+	Metadata            *metadata.Metadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// listEntries attaches the .autowsl.json metadata of each distribution.
+// Metadata is best effort: distributions without it are listed as-is.
+func listEntries(distros []wsl.InstalledDistro) []listEntry {
+	entries := make([]listEntry, 0, len(distros))
+	paths, _ := wsl.InstallPaths()
+	for _, d := range distros {
+		entry := listEntry{InstalledDistro: d}
+		if path, ok := paths[d.Name]; ok {
+			if m, err := metadata.Read(path); err == nil {
+				entry.Metadata = &m
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
 func runRemove(cmd *cobra.Command, args []string) error {
+	if removeAll && removeSpec != "" {
+		return fmt.Errorf("--all and --from-spec cannot be used together")
+	}
+	if removeAll || removeSpec != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("a distribution name cannot be combined with --all or --from-spec")
+		}
+		return runRemoveMany()
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("requires a distribution name, --all, or --from-spec")
+	}
 	distroName := args[0]
 
 	// Check if the distribution exists
@@ -79,7 +309,7 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to check distribution: %w", err)
 	}
-	if !exists {
+	if !exists && !dryRun {
 		return fmt.Errorf("distribution '%s' does not exist", distroName)
 	}
 
@@ -89,7 +319,7 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		IsConfirm: true,
 	}
 
-	_, err = prompt.Run()
+	_, err = ui.Prompt(prompt)
 	if err != nil {
 		fmt.Println("Removal cancelled")
 		return nil
@@ -106,21 +336,113 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runRemoveMany removes every installed distribution (--all) or those named
+// in a batch spec (--from-spec). Failures don't stop the remaining removals.
+func runRemoveMany() error {
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+
+	var targets []string
+	if removeAll {
+		for _, d := range distros {
+			targets = append(targets, d.Name)
+		}
+	} else {
+		entries, err := batch.Load(removeSpec)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if distroInstalled(distros, e.Name) || dryRun {
+				targets = append(targets, e.Name)
+			} else {
+				fmt.Printf("⚠ '%s' is not installed, skipping\n", e.Name)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		fmt.Println("No distributions to remove.")
+		return nil
+	}
+
+	fmt.Println("The following distributions will be removed:")
+	for _, name := range targets {
+		fmt.Printf("  - %s\n", name)
+	}
+	fmt.Println()
+	if !ui.ConfirmWord(fmt.Sprintf("Remove %d distribution(s)", len(targets)), "yes") {
+		fmt.Println("Removal cancelled")
+		return nil
+	}
+
+	client := wsl.DefaultClient()
+	var errs []error
+	for _, name := range targets {
+		fmt.Printf("→ Removing '%s'...\n", name)
+		if err := client.Unregister(name); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		fmt.Printf("  ✓ Removed '%s'\n", name)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to remove %d of %d distribution(s):\n%w", len(errs), len(targets), errors.Join(errs...))
+	}
+	fmt.Printf("\nSuccessfully removed %d distribution(s)\n", len(targets))
+	return nil
+}
+
+// distroInstalled reports whether name is among distros
+func distroInstalled(distros []wsl.InstalledDistro, name string) bool {
+	for _, d := range distros {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func runBackup(cmd *cobra.Command, args []string) error {
 	distroName := args[0]
 
+	if backupCompress != wsl.CompressionNone && backupCompress != wsl.CompressionGzip && backupCompress != wsl.CompressionXz {
+		return fmt.Errorf("invalid --compress %q (must be none, gzip, or xz)", backupCompress)
+	}
+	if backupFormat != "tar" && backupFormat != "vhd" {
+		return fmt.Errorf("invalid --output-format %q (must be tar or vhd)", backupFormat)
+	}
+	if backupFormat == "vhd" {
+		if backupCompress != wsl.CompressionNone {
+			return fmt.Errorf("--compress cannot be used with --output-format vhd")
+		}
+		if err := system.CheckFeature(system.FeatureVHDExport); err != nil {
+			return err
+		}
+	}
+
 	// Check if the distribution exists
 	exists, err := wsl.IsDistroInstalled(distroName)
 	if err != nil {
 		return fmt.Errorf("failed to check distribution: %w", err)
 	}
-	if !exists {
+	if !exists && !dryRun {
 		return fmt.Errorf("distribution '%s' does not exist", distroName)
 	}
+	if backupFormat == "vhd" && distroWSLVersion(distroName) == "1" {
+		return fmt.Errorf("'%s' uses WSL 1 and has no virtual disk; back it up as tar", distroName)
+	}
 
 	// Generate default backup filename
+	ext := ".tar"
+	if backupFormat == "vhd" {
+		ext = ".vhdx"
+	}
 	homeDir, _ := os.UserHomeDir()
-	defaultBackupPath := filepath.Join(homeDir, "WSL-Backups", fmt.Sprintf("%s-backup.tar", distroName))
+	defaultBackupPath := filepath.Join(homeDir, "WSL-Backups", fmt.Sprintf("%s-backup%s", distroName, ext))
 
 	// Prompt for backup location
 	prompt := promptui.Prompt{
@@ -128,7 +450,7 @@ func runBackup(cmd *cobra.Command, args []string) error {
 		Default: defaultBackupPath,
 	}
 
-	backupPath, err := prompt.Run()
+	backupPath, err := ui.Prompt(prompt)
 	if err != nil {
 		return fmt.Errorf("failed to get backup path: %w", err)
 	}
@@ -136,17 +458,312 @@ func runBackup(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\nBacking up '%s' to %s...\n", distroName, backupPath)
 	fmt.Println("This may take a while depending on the size of your distribution...")
 
-	if err := wsl.Export(distroName, backupPath); err != nil {
+	if backupCompress != wsl.CompressionNone {
+		fmt.Printf("The export will be compressed with %s afterwards.\n", backupCompress)
+	}
+
+	var result *wsl.ExportResult
+	if backupFormat == "vhd" {
+		result, err = exportVHDBackup(distroName, backupPath)
+	} else {
+		result, err = wsl.ExportWithOptions(wsl.ExportOptions{
+			Name:        distroName,
+			OutputPath:  backupPath,
+			Compression: backupCompress,
+		})
+	}
+	if err != nil {
 		return fmt.Errorf("failed to backup distribution: %w", err)
 	}
+	if dryRun {
+		return nil
+	}
 
-	// Get file size
-	fileInfo, _ := os.Stat(backupPath)
-	sizeInMB := float64(fileInfo.Size()) / 1024 / 1024
+	sizeInMB := float64(result.Size) / 1024 / 1024
+	m, hasMetadata := backupMetadata(distroName, result.Path)
 
 	fmt.Printf("\nSuccessfully backed up '%s'\n", distroName)
-	fmt.Printf("Location: %s\n", backupPath)
+	fmt.Printf("Location: %s\n", result.Path)
 	fmt.Printf("Size: %.2f MB\n", sizeInMB)
+	if backupCompress != wsl.CompressionNone && result.UncompressedSize > 0 {
+		ratio := float64(result.Size) / float64(result.UncompressedSize) * 100
+		fmt.Printf("Compression: %s (%.2f MB uncompressed, %.1f%% of original)\n",
+			backupCompress, float64(result.UncompressedSize)/1024/1024, ratio)
+	}
+
+	if hasMetadata {
+		fmt.Printf("Metadata: %s\n", metadata.BackupPath(result.Path))
+		if m.DistroVersion != "" {
+			fmt.Printf("Originally installed from %s (%s) on %s\n", m.DistroVersion, m.PackageID, m.InstalledAt.Local().Format("2006-01-02"))
+		}
+		if len(m.PlaybooksRun) > 0 {
+			fmt.Printf("Playbooks run: %s\n", strings.Join(m.PlaybooksRun, ", "))
+		}
+	}
 
 	return nil
 }
+
+// exportVHDBackup exports a distribution as a .vhdx and describes the file
+// like ExportWithOptions does for tar backups
+func exportVHDBackup(distroName, backupPath string) (*wsl.ExportResult, error) {
+	if err := wsl.ExportVHD(distroName, backupPath); err != nil {
+		return nil, err
+	}
+	result := &wsl.ExportResult{Path: backupPath}
+	if dryRun {
+		return result, nil
+	}
+	info, err := os.Stat(backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exported file: %w", err)
+	}
+	result.Size = info.Size()
+	return result, nil
+}
+
+// backupMetadata copies a distribution's .autowsl.json next to its backup so
+// the backup records where the distribution came from
+func backupMetadata(distroName, backupPath string) (metadata.Metadata, bool) {
+	installPath, err := wsl.GetInstallPath(distroName)
+	if err != nil {
+		return metadata.Metadata{}, false
+	}
+	m, err := metadata.Read(installPath)
+	if err != nil {
+		return metadata.Metadata{}, false
+	}
+	if err := metadata.WriteFile(metadata.BackupPath(backupPath), m); err != nil {
+		fmt.Printf("⚠ Warning: %v\n", err)
+		return m, false
+	}
+	return m, true
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	isInteractive := len(args) < 2
+
+	// Determine the distribution to rename
+	var oldName string
+	if len(args) > 0 {
+		oldName = args[0]
+	} else {
+		var err error
+		oldName, err = selectInstalledDistroInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Determine the new name
+	newName := ""
+	if len(args) > 1 {
+		newName = args[1]
+	} else {
+		namePrompt := promptui.Prompt{
+			Label: "New distribution name",
+		}
+		customName, err := ui.Prompt(namePrompt)
+		if err != nil {
+			return fmt.Errorf("failed to get distribution name: %w", err)
+		}
+		newName = customName
+	}
+
+	// Validate both names before doing any work
+	if err := wsl.ValidateDistroName(newName); err != nil {
+		return err
+	}
+	exists, err := wsl.IsDistroInstalled(oldName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return fmt.Errorf("distribution '%s' does not exist", oldName)
+	}
+	exists, err = wsl.IsDistroInstalled(newName)
+	if err != nil {
+		return fmt.Errorf("failed to check existing distributions: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("distribution '%s' already exists", newName)
+	}
+
+	if isInteractive {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Rename '%s' to '%s'", oldName, newName),
+			IsConfirm: true,
+		}
+		if _, err := ui.Prompt(prompt); err != nil {
+			fmt.Println("Rename cancelled")
+			return nil
+		}
+	}
+
+	fmt.Printf("\nRenaming '%s' to '%s'...\n", oldName, newName)
+	fmt.Println("This may take a while depending on the size of your distribution...")
+
+	if err := wsl.Rename(oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename distribution: %w", err)
+	}
+
+	fmt.Printf("Successfully renamed '%s' to '%s'\n", oldName, newName)
+	fmt.Printf("\nLaunch with:  wsl -d %s\n", newName)
+
+	return nil
+}
+
+func runSetDefault(cmd *cobra.Command, args []string) error {
+	var distroName string
+	if len(args) > 0 {
+		distroName = args[0]
+	} else {
+		var err error
+		distroName, err = selectInstalledDistroInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := wsl.SetDefault(distroName); err != nil {
+		return err
+	}
+
+	// Re-read the list to make sure WSL accepted the change
+	if !dryRun {
+		distros, err := wsl.ListInstalledDistros()
+		if err != nil {
+			return fmt.Errorf("failed to verify default distribution: %w", err)
+		}
+		current := ""
+		for _, d := range distros {
+			if d.Default {
+				current = d.Name
+			}
+		}
+		if !strings.EqualFold(current, distroName) {
+			if current == "" {
+				return fmt.Errorf("WSL does not report '%s' as the default distribution after setting it", distroName)
+			}
+			return fmt.Errorf("default distribution is still '%s' after setting '%s'", current, distroName)
+		}
+	}
+
+	fmt.Printf("Default distribution set to '%s'\n", distroName)
+
+	return nil
+}
+
+func runDefault(cmd *cobra.Command, args []string) error {
+	name, err := wsl.GetDefaultDistro()
+	if err != nil {
+		return err
+	}
+	fmt.Println(name)
+	return nil
+}
+
+func runStart(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+
+	state, err := distroState(distroName)
+	if err != nil {
+		return err
+	}
+	if state == "" && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+	if strings.EqualFold(state, "Running") {
+		fmt.Printf("'%s' is already running\n", distroName)
+		return nil
+	}
+
+	fmt.Printf("Starting '%s'...\n", distroName)
+	if err := wsl.Start(distroName); err != nil {
+		return err
+	}
+	recordDistroStart(distroName)
+	if dryRun {
+		return nil
+	}
+
+	for i := 0; i < startPollAttempts; i++ {
+		if state, err = distroState(distroName); err != nil {
+			return err
+		}
+		if strings.EqualFold(state, "Running") {
+			fmt.Printf("✓ '%s' is running\n", distroName)
+			return nil
+		}
+		time.Sleep(startPollInterval)
+	}
+	return fmt.Errorf("'%s' did not reach the Running state (current state: %s)", distroName, state)
+}
+
+func runStop(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+
+	state, err := distroState(distroName)
+	if err != nil {
+		return err
+	}
+	if state == "" && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+	if state != "" && !strings.EqualFold(state, "Running") {
+		fmt.Printf("'%s' is not running (%s)\n", distroName, state)
+		return nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Stop '%s'? Open shells and processes in it will be killed", distroName),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Stop cancelled")
+		return nil
+	}
+
+	if err := wsl.Stop(distroName); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Stopped '%s'\n", distroName)
+	return nil
+}
+
+func runShutdown(cmd *cobra.Command, args []string) error {
+	if err := wsl.Shutdown(); err != nil {
+		return err
+	}
+	fmt.Println("✓ WSL has been shut down")
+	return nil
+}
+
+// distroState returns the state of an installed distribution, or "" if it is not installed
+func distroState(distroName string) (string, error) {
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return "", fmt.Errorf("failed to list distributions: %w", err)
+	}
+	for _, d := range distros {
+		if d.Name == distroName {
+			return d.State, nil
+		}
+	}
+	return "", nil
+}
+
+// distroWSLVersion returns the WSL version ("1" or "2") of an installed
+// distribution, or "" when it can't be determined
+func distroWSLVersion(distroName string) string {
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return ""
+	}
+	for _, d := range distros {
+		if d.Name == distroName {
+			return d.Version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	movePath         string
+	moveKeepOriginal bool
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <distro-name> --path <new-path>",
+	Short: "Move a WSL distribution to another directory or drive",
+	Long: `Move a distribution's virtual disk to a new location.
+The distribution is exported to a temporary tar file in .autowsl_tmp and
+imported at the new path under a temporary name. The original is only
+unregistered once that import succeeds, and the copy then takes its name.
+
+With --keep-original the copy is imported as '<name>-moved' and the original
+stays registered, so you can verify the copy before removing the original.
+
+Examples:
+  # Move a distribution to another drive
+  autowsl move ubuntu-2204-lts --path D:\WSL\ubuntu-2204-lts
+
+  # Keep the original until the copy has been verified
+  autowsl move ubuntu-2204-lts --path D:\WSL\ubuntu-2204-lts --keep-original`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMove,
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+	moveCmd.Flags().StringVar(&movePath, "path", "", "New installation path (required)")
+	moveCmd.Flags().BoolVar(&moveKeepOriginal, "keep-original", false, "Keep the original registration and import the copy as '<name>-moved'")
+	_ = moveCmd.MarkFlagRequired("path")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+	if moveKeepOriginal {
+		exists, err := wsl.IsDistroInstalled(distroName + "-moved")
+		if err != nil {
+			return fmt.Errorf("failed to check existing distributions: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("distribution '%s-moved' already exists", distroName)
+		}
+	}
+
+	newPath, err := filepath.Abs(movePath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for '%s': %w", movePath, err)
+	}
+
+	// Refuse to "move" onto the current location
+	oldPath, err := wsl.GetInstallPath(distroName)
+	if err == nil && strings.EqualFold(filepath.Clean(oldPath), filepath.Clean(newPath)) {
+		return fmt.Errorf("'%s' is already installed at %s", distroName, oldPath)
+	}
+
+	// The export needs room for the tar and the import for the new disk
+	tempDir := tempDirPath()
+	var vhdSize int64
+	vhdPath, err := wsl.VHDPath(distroName)
+	if err == nil {
+		vhdSize, _ = wsl.GetVHDSize(vhdPath)
+	} else if !dryRun && distroWSLVersion(distroName) == "2" {
+		// A WSL 2 distribution without its virtual disk can't be exported
+		return fmt.Errorf("cannot move '%s': %w", distroName, err)
+	}
+	if vhdSize > 0 {
+		if err := checkMoveSpace(newPath, tempDir, vhdSize); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\n%s\n", strings.Repeat("=", 60))
+	fmt.Printf("Move Configuration\n")
+	fmt.Printf("%s\n", strings.Repeat("=", 60))
+	fmt.Printf("Distribution: %s\n", distroName)
+	if oldPath != "" {
+		fmt.Printf("From:         %s\n", oldPath)
+	}
+	fmt.Printf("To:           %s\n", newPath)
+	if vhdSize > 0 {
+		fmt.Printf("Disk size:    %.2f MB\n", float64(vhdSize)/1024/1024)
+	}
+	if moveKeepOriginal {
+		fmt.Printf("Original:     kept (copy imported as '%s-moved')\n", distroName)
+	}
+	fmt.Printf("%s\n\n", strings.Repeat("=", 60))
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Move '%s'", distroName),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Move cancelled")
+		return nil
+	}
+
+	fmt.Printf("→ Moving '%s'...\n", distroName)
+	fmt.Println("  This may take a while depending on the size of your distribution...")
+
+	movedName, err := wsl.Move(wsl.MoveOptions{
+		Name:         distroName,
+		NewPath:      newPath,
+		TempDir:      tempDir,
+		KeepOriginal: moveKeepOriginal,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move distribution: %w", err)
+	}
+
+	fmt.Printf("\nSuccessfully moved '%s' to %s\n", distroName, newPath)
+	if moveKeepOriginal {
+		fmt.Printf("\nVerify the copy with:  wsl -d %s\n", movedName)
+		fmt.Printf("Then remove the original: autowsl remove %s\n", distroName)
+	} else {
+		fmt.Printf("\nLaunch with:  wsl -d %s\n", movedName)
+	}
+
+	return nil
+}
+
+// checkMoveSpace makes sure there is room for the intermediate tar and the new
+// virtual disk, counting both against one volume when they share a drive
+func checkMoveSpace(newPath, tempDir string, vhdSize int64) error {
+	required := map[string]int64{}
+	newVolume := existingParent(newPath)
+	tempVolume := existingParent(tempDir)
+	required[newVolume] += vhdSize
+	required[tempVolume] += vhdSize
+	if filepath.VolumeName(newVolume) != "" && strings.EqualFold(filepath.VolumeName(newVolume), filepath.VolumeName(tempVolume)) {
+		required = map[string]int64{newVolume: 2 * vhdSize}
+	}
+
+	for dir, need := range required {
+		free, err := system.FreeDiskSpace(dir)
+		if err != nil {
+			fmt.Printf("⚠ Warning: could not check free space on %s: %v\n", dir, err)
+			continue
+		}
+		if int64(free) < need {
+			return fmt.Errorf("not enough free space on %s: need %.2f MB, have %.2f MB",
+				dir, float64(need)/1024/1024, float64(free)/1024/1024)
+		}
+	}
+	return nil
+}
+
+// existingParent returns path or its closest ancestor that exists
+func existingParent(path string) string {
+	dir := path
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var portProxyListOutput string
+
+var portProxyCmd = &cobra.Command{
+	Use:   "portproxy",
+	Short: "Expose WSL2 ports to other machines with Windows port-proxy rules",
+	Long: `Manage Windows port-proxy rules (netsh interface portproxy) that forward a port
+on all Windows interfaces to a WSL2 distribution, so services running in WSL2
+are reachable from other machines.
+
+Adding and removing rules requires an elevated prompt. The WSL2 address
+changes whenever WSL restarts, so rules need to be added again after a restart.
+Windows Firewall may also need to allow the listen port.
+
+Examples:
+  # Forward port 8080 on Windows to port 80 in ubuntu-2204
+  autowsl portproxy add ubuntu-2204 8080:80
+
+  autowsl portproxy list
+
+  autowsl portproxy remove 8080`,
+}
+
+var portProxyAddCmd = &cobra.Command{
+	Use:               "add <distro-name> <listen-port>:<guest-port>",
+	Short:             "Forward a Windows port to a distribution",
+	Args:              cobra.ExactArgs(2),
+	RunE:              runPortProxyAdd,
+	ValidArgsFunction: completeInstalledDistros,
+}
+
+var portProxyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the Windows port-proxy rules",
+	Args:  cobra.NoArgs,
+	RunE:  runPortProxyList,
+}
+
+var portProxyRemoveCmd = &cobra.Command{
+	Use:   "remove <listen-port>",
+	Short: "Remove the port-proxy rule for a Windows port",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPortProxyRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(portProxyCmd)
+	portProxyCmd.AddCommand(portProxyAddCmd)
+	portProxyCmd.AddCommand(portProxyListCmd)
+	portProxyCmd.AddCommand(portProxyRemoveCmd)
+
+	portProxyListCmd.Flags().StringVarP(&portProxyListOutput, "output", "o", "table", "Output format: table, json, or yaml")
+}
+
+func runPortProxyAdd(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+	listenPort, guestPort, err := wsl.ParsePortMapping(args[1])
+	if err != nil {
+		return err
+	}
+
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+	found := false
+	for _, d := range distros {
+		if d.Name == distroName {
+			found = true
+			if d.Version == "1" {
+				return fmt.Errorf("'%s' uses WSL 1, which shares the Windows network; no port proxy is needed", distroName)
+			}
+		}
+	}
+	if !found && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	client := wsl.DefaultClient()
+	if !client.IsElevated() {
+		return wsl.ErrNotElevated
+	}
+
+	ip, err := client.GetWSL2IP(distroName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("→ Forwarding port %d to %s:%d (%s)...\n", listenPort, ip, guestPort, distroName)
+	if err := client.AddPortProxy(listenPort, guestPort, ip); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+	fmt.Printf("✓ Port %d forwarded to '%s'\n", listenPort, distroName)
+	fmt.Println("  The WSL2 address changes on restart; add the rule again afterwards.")
+	return nil
+}
+
+func runPortProxyList(cmd *cobra.Command, args []string) error {
+	if portProxyListOutput != "table" && portProxyListOutput != "json" && portProxyListOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", portProxyListOutput)
+	}
+
+	proxies, err := wsl.ListPortProxies()
+	if err != nil {
+		return err
+	}
+
+	switch portProxyListOutput {
+	case "json":
+		if proxies == nil {
+			proxies = []wsl.PortProxy{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(proxies)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(proxies); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if len(proxies) == 0 {
+		fmt.Println("No port-proxy rules. Add one with 'autowsl portproxy add'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "LISTEN\tCONNECT")
+	for _, p := range proxies {
+		fmt.Fprintf(w, "%s:%d\t%s:%d\n", p.ListenAddress, p.ListenPort, p.ConnectAddress, p.ConnectPort)
+	}
+	return w.Flush()
+}
+
+func runPortProxyRemove(cmd *cobra.Command, args []string) error {
+	listenPort, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid port '%s'", args[0])
+	}
+
+	fmt.Printf("→ Removing port-proxy rule for port %d...\n", listenPort)
+	if err := wsl.RemovePortProxy(listenPort); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+	fmt.Printf("✓ Removed port-proxy rule for port %d\n", listenPort)
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	packagesOutput string
+	packagesGrep   string
+)
+
+var packagesCmd = &cobra.Command{
+	Use:   "packages",
+	Short: "Inspect packages installed in a distribution",
+}
+
+var packagesListCmd = &cobra.Command{
+	Use:   "list <distro-name>",
+	Short: "List the packages installed in a distribution",
+	Long: `List the packages installed in a distribution with their versions. The package
+manager (apt, dnf, yum, zypper, pacman, or apk) is detected automatically.
+
+Examples:
+  autowsl packages list ubuntu-2204
+
+  # Only packages matching a regular expression
+  autowsl packages list ubuntu-2204 --grep '^python3'
+
+  # Machine-readable output for audits
+  autowsl packages list ubuntu-2204 -o json > packages.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPackagesList,
+}
+
+func init() {
+	rootCmd.AddCommand(packagesCmd)
+	packagesCmd.AddCommand(packagesListCmd)
+	packagesListCmd.Flags().StringVarP(&packagesOutput, "output", "o", "table", "Output format: table, json, or yaml")
+	packagesListCmd.Flags().StringVar(&packagesGrep, "grep", "", "Only list packages whose name matches this regular expression")
+}
+
+func runPackagesList(cmd *cobra.Command, args []string) error {
+	if packagesOutput != "table" && packagesOutput != "json" && packagesOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", packagesOutput)
+	}
+	var pattern *regexp.Regexp
+	if packagesGrep != "" {
+		var err error
+		if pattern, err = regexp.Compile(packagesGrep); err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+	}
+
+	distroName := args[0]
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists {
+		return distroNotFoundError(distroName)
+	}
+
+	pmName, err := ansible.DetectPackageManager(distroName)
+	if err != nil {
+		return err
+	}
+	packages, err := wsl.ListPackages(distroName, pmName)
+	if err != nil {
+		return err
+	}
+
+	if pattern != nil {
+		matched := packages[:0]
+		for _, p := range packages {
+			if pattern.MatchString(p.Name) {
+				matched = append(matched, p)
+			}
+		}
+		packages = matched
+	}
+	if packages == nil {
+		packages = []wsl.Package{}
+	}
+
+	switch packagesOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(packages)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(packages); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if len(packages) == 0 {
+		fmt.Println("No matching packages found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION")
+	for _, p := range packages {
+		fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Version)
+	}
+	w.Flush()
+	fmt.Printf("\n%d packages (%s)\n", len(packages), pmName)
+	return nil
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/profile"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// profileName is the --profile flag of install and provision
+	profileName string
+	// activeProfile is the profile loaded for the current command, if any
+	activeProfile *profile.Profile
+
+	profileAddDistro     string
+	profileAddName       string
+	profileAddPath       string
+	profileAddPlaybooks  []string
+	profileAddTags       []string
+	profileAddExtraVars  []string
+	profileAddWSLVersion int
+	profileAddKeepTar    bool
+	profileAddForce      bool
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named install and provision profiles",
+	Long: `Manage named profiles of defaults for install and provision. Pass --profile <name>
+to either command to use a profile; flags on the command line still override it.
+
+Examples:
+  # A profile for frontend work
+  autowsl profile add frontend --distro "Ubuntu 22.04 LTS" --name frontend --playbooks node,git
+
+  # Install using the profile
+  autowsl install --profile frontend
+
+  # Provision an existing distribution with the profile's playbooks
+  autowsl provision --profile frontend
+
+  autowsl profile list
+  autowsl profile show frontend
+  autowsl profile delete frontend`,
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfileAdd,
+}
+
+var profileShowCmd = &cobra.Command{
+	Use:               "show <name>",
+	Short:             "Show the settings of a profile",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runProfileShow,
+	ValidArgsFunction: completeProfiles,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runProfileList,
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Delete a profile",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runProfileDelete,
+	ValidArgsFunction: completeProfiles,
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileShowCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+
+	profileAddCmd.Flags().StringVar(&profileAddDistro, "distro", "", "Catalog version to install, e.g. \"Ubuntu 22.04 LTS\"")
+	profileAddCmd.Flags().StringVar(&profileAddName, "name", "", "Distribution name to install or provision")
+	profileAddCmd.Flags().StringVar(&profileAddPath, "path", "", "Installation path")
+	profileAddCmd.Flags().StringSliceVar(&profileAddPlaybooks, "playbooks", nil, "Playbook files, URLs, or aliases (comma-separated or repeat flag)")
+	profileAddCmd.Flags().StringSliceVar(&profileAddTags, "tags", nil, "Ansible tags to run (comma-separated)")
+	profileAddCmd.Flags().StringArrayVar(&profileAddExtraVars, "extra-vars", nil, "Extra variables in key=val format (repeatable)")
+	profileAddCmd.Flags().IntVar(&profileAddWSLVersion, "version", 0, "WSL version to use (1 or 2)")
+	profileAddCmd.Flags().BoolVar(&profileAddKeepTar, "keep-tar", false, "Keep the extracted tar file for future use")
+	profileAddCmd.Flags().BoolVar(&profileAddForce, "force", false, "Replace an existing profile with the same name")
+	_ = profileAddCmd.RegisterFlagCompletionFunc("playbooks", completePlaybookAliases)
+
+	for _, c := range []*cobra.Command{installCmd, provisionCmd} {
+		c.PersistentFlags().StringVar(&profileName, "profile", "", "Load defaults from a saved profile (see 'autowsl profile')")
+		_ = c.RegisterFlagCompletionFunc("profile", completeProfiles)
+	}
+}
+
+func runProfileAdd(cmd *cobra.Command, args []string) error {
+	p := profile.Profile{
+		Name:                 args[0],
+		DefaultDistroVersion: profileAddDistro,
+		DefaultName:          profileAddName,
+		DefaultPath:          profileAddPath,
+		Playbooks:            profileAddPlaybooks,
+		Tags:                 profileAddTags,
+	}
+	if err := profile.ValidateName(p.Name); err != nil {
+		return err
+	}
+
+	// Only record what was given explicitly, not values filled in from the config file
+	if cmd.Flags().Changed("version") {
+		if profileAddWSLVersion != 1 && profileAddWSLVersion != 2 {
+			return fmt.Errorf("invalid --version %d (must be 1 or 2)", profileAddWSLVersion)
+		}
+		p.WSLVersion = profileAddWSLVersion
+	}
+	if cmd.Flags().Changed("keep-tar") {
+		p.KeepTar = profileAddKeepTar
+	}
+	for _, kv := range profileAddExtraVars {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --extra-vars %q (expected key=val)", kv)
+		}
+		if p.ExtraVars == nil {
+			p.ExtraVars = make(map[string]string)
+		}
+		p.ExtraVars[key] = value
+	}
+
+	if _, err := profile.Load(p.Name); err == nil && !profileAddForce {
+		return fmt.Errorf("profile '%s' already exists; use --force to replace it", p.Name)
+	} else if err != nil && !errors.Is(err, profile.ErrNotFound) {
+		return err
+	}
+
+	if err := profile.Save(p); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Saved profile '%s'\n", p.Name)
+	return nil
+}
+
+func runProfileShow(cmd *cobra.Command, args []string) error {
+	p, err := profile.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(p); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	profiles, err := profile.List()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Println("No profiles found. Create one with 'autowsl profile add <name>'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDISTRIBUTION\tDISTRO NAME\tPLAYBOOKS")
+	for _, p := range profiles {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, orDash(p.DefaultDistroVersion), orDash(p.DefaultName), orDash(strings.Join(p.Playbooks, ",")))
+	}
+	return w.Flush()
+}
+
+func runProfileDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if _, err := profile.Load(name); err != nil {
+		return err
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Delete profile '%s'", name),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Deletion cancelled")
+		return nil
+	}
+
+	if err := profile.Delete(name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Deleted profile '%s'\n", name)
+	return nil
+}
+
+// applyProfileDefaults loads --profile and fills in the flags the user did
+// not provide. It runs after the config defaults, so a profile wins over the
+// config file and explicit flags win over both.
+func applyProfileDefaults(cmd *cobra.Command) error {
+	activeProfile = nil
+	if f := cmd.Flags().Lookup("profile"); f == nil || profileName == "" {
+		return nil
+	}
+
+	p, err := profile.Load(profileName)
+	if err != nil {
+		return err
+	}
+	activeProfile = &p
+
+	for _, fv := range profileFlagValues(p) {
+		f := cmd.Flags().Lookup(fv.name)
+		if f == nil || f.Changed || len(fv.values) == 0 {
+			continue
+		}
+		values := fv.values
+		if f.Value.Type() == "string" {
+			// provision takes all extra vars in a single string
+			values = []string{strings.Join(values, " ")}
+		}
+		for _, v := range values {
+			if err := f.Value.Set(v); err != nil {
+				return fmt.Errorf("invalid value in profile '%s' for --%s: %w", p.Name, fv.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// profileFlagValue is the value a profile gives to one flag
+type profileFlagValue struct {
+	name   string
+	values []string
+}
+
+// profileFlagValues maps a profile to install and provision flags
+func profileFlagValues(p profile.Profile) []profileFlagValue {
+	var extraVars []string
+	for key, value := range p.ExtraVars {
+		extraVars = append(extraVars, key+"="+value)
+	}
+	sort.Strings(extraVars)
+
+	values := []profileFlagValue{
+		{name: "playbooks", values: p.Playbooks},
+		{name: "tags", values: p.Tags},
+		{name: "extra-vars", values: extraVars},
+	}
+	if p.DefaultName != "" {
+		values = append(values, profileFlagValue{name: "name", values: []string{p.DefaultName}})
+	}
+	if p.DefaultPath != "" {
+		values = append(values, profileFlagValue{name: "path", values: []string{p.DefaultPath}})
+	}
+	if p.WSLVersion != 0 {
+		values = append(values, profileFlagValue{name: "version", values: []string{strconv.Itoa(p.WSLVersion)}})
+	}
+	if p.KeepTar {
+		values = append(values, profileFlagValue{name: "keep-tar", values: []string{"true"}})
+	}
+	return values
+}
+
+// completeProfiles completes saved profile names
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := profile.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// orDash returns s, or "-" when it is empty, for table cells
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
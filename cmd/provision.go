@@ -1,24 +1,82 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
+	"time"
 
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/config"
+	"github.com/yuanjua/autowsl/internal/hooks"
+	"github.com/yuanjua/autowsl/internal/ui"
 	"github.com/yuanjua/autowsl/internal/wsl"
 )
 
 var (
-	provisionTags      []string
-	provisionPlaybooks []string
-	provisionExtraVars string
-	provisionRepo      string
-	provisionVerbose   bool
+	provisionTags       []string
+	provisionSkipTags   []string
+	provisionTagsFile   string
+	provisionSkipFile   string
+	provisionLimit      string
+	provisionForks      int
+	provisionOutputLog  string
+	provisionAnsibleVer string
+	provisionPlaybooks  []string
+	provisionExtraVars  string
+	provisionVarsFile   string
+	provisionAnsibleCfg string
+	provisionPipPkgs    []string
+	provisionSummary    string
+	provisionRepo       string
+	provisionRepoSSHKey string
+	provisionRepoBranch string
+	provisionRepoBook   string
+	provisionVerbose    bool
+	provisionMaxRetries int
+
+	provisionForceRefresh bool
+
+	provisionVaultPasswordFile string
+	provisionAskVaultPass      bool
+	provisionVaultIDs          []ansible.VaultID
+	provisionSkipGalaxy        bool
+	provisionGalaxyColls       string
+	provisionGalaxyRoles       string
+	provisionBecomePassFile    string
+	provisionSyntaxCheck       bool
+	provisionSkipValidate      bool
+	provisionReinstallAnsible  bool
+	provisionCheck             bool
+	provisionDiff              bool
+	provisionContinueOnErr     bool
+
+	provisionParallel    bool
+	provisionMaxParallel int
+
+	provisionPreHooks    []string
+	provisionPostHooks   []string
+	provisionStrictHooks bool
+
+	provisionStartFresh bool
+
+	provisionEnableSystemd bool
+
+	provisionConnection string
+	provisionInventory  string
+
+	provisionPreCmds        []string
+	provisionPostCmds       []string
+	provisionStrictPostCmds bool
 )
 
+// startFreshTimeout bounds how long --start-fresh waits for the distribution to stop
+const startFreshTimeout = 15 * time.Second
+
 var provisionCmd = &cobra.Command{
 	Use:   "provision [distro-name]",
 	Short: "Provision a WSL distribution with Ansible",
@@ -43,13 +101,92 @@ Examples:
   # Use playbook from Git repository
   autowsl provision ubuntu-2204 --repo https://github.com/user/ansible-playbooks
 
+  # Private repository over SSH, specific branch
+  autowsl provision ubuntu-2204 --repo git@github.com:org/infra.git --repo-ssh-key ~/.ssh/id_ed25519 --repo-branch dev
+
+  # Pick the playbook inside the repository instead of choosing from a list
+  autowsl provision ubuntu-2204 --repo https://github.com/user/ansible-playbooks --repo-playbook playbooks/ubuntu/desktop.yml
+
   # Run specific tags only
   autowsl provision ubuntu-2204 --playbooks ./setup.yml --tags docker,nodejs
 
+  # Skip specific tags
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --skip-tags slow
+
+  # Read a long tag list from a file (tags: [docker, nodejs, python])
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --tags-file ./tags.yml
+
+  # Only run plays that target localhost in a multi-play playbook
+  autowsl provision ubuntu-2204 --playbooks ./site.yml --limit localhost
+
+  # More Ansible forks for plays with many includes (local mode runs a
+  # single host, so the effect is limited)
+  autowsl provision ubuntu-2204 --playbooks ./site.yml --forks 10
+
+  # Keep a copy of the Ansible output for later review
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --output-log provision.log
+
+  # Upgrade an Ansible that is too old for the playbooks
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --force-reinstall-ansible
+
   # Pass extra variables
   autowsl provision ubuntu-2204 --playbooks ./setup.yml --extra-vars "user=john env=dev"
   autowsl provision ubuntu-2204 --playbooks ./setup.yml --extra-vars "user=john,env=dev"
 
+  # Structured variables from a JSON or YAML file
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --extra-vars-file ./vars.yml
+
+  # Install Python libraries the playbooks need first
+  autowsl provision ubuntu-2204 --playbooks ./aws.yml --pip-packages boto3,botocore
+
+  # Save the results for CI as JSON
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --summary-json ./provision-summary.json
+
+  # Decrypt Ansible Vault secrets
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --vault-password-file ./.vault-pass
+
+  # Several vault IDs; "prompt" asks for that password on the terminal
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --vault-id dev@./.vault-dev --vault-id prod@prompt
+
+  # Distros where sudo asks for a password
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --become-password-file ./.sudo-pass
+
+  # Collections from a requirements.yml next to the playbook are installed
+  # automatically; skip that when they are already present
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --skip-galaxy-install
+
+  # Install Galaxy collections and roles from explicit requirements files
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --galaxy-collections-file ./collections.yml --galaxy-roles-file ./roles.yml
+
+  # Preview changes without applying them
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --check --diff
+
+  # Catch playbook errors before anything runs
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml,./dev.yml --syntax-check
+
+  # Run every playbook and report all failures at the end
+  autowsl provision ubuntu-2204 --playbooks ./base.yml,./dev.yml,./extras.yml --continue-on-error
+
+  # Run independent playbooks concurrently (output is shown per playbook)
+  autowsl provision ubuntu-2204 --playbooks curl,git,docker --parallel --max-parallel 2
+
+  # Run Windows-side commands before and after (PowerShell; AUTOWSL_DISTRO,
+  # AUTOWSL_PLAYBOOK and AUTOWSL_STATUS are set). Hooks can also be set under
+  # hooks.pre_provision / hooks.post_provision in the config file.
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --post-hook 'New-NetFirewallRule -DisplayName wsl-dev -LocalPort 3000 -Protocol TCP'
+
+  # Stop the distribution first so no services hold files, and restart it afterwards
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --start-fresh
+
+  # Enable systemd in /etc/wsl.conf (restarting the distro) before playbooks that manage services
+  autowsl provision ubuntu-2204 --playbooks ./services.yml --enable-systemd
+
+  # Run shell commands in the distribution around each playbook
+  autowsl provision ubuntu-2204 --playbooks ./setup.yml --pre-cmd 'sudo apt-get update' --post-cmd 'sudo apt-get autoremove -y'
+
+  # Use the distribution as a control node for remote hosts over SSH
+  autowsl provision ubuntu-2204 --playbooks ./vms.yml --connection ssh --inventory ./hosts.ini
+
   # Verbose output
   autowsl provision ubuntu-2204 --verbose`,
 	RunE: runProvision,
@@ -58,10 +195,91 @@ Examples:
 func init() {
 	rootCmd.AddCommand(provisionCmd)
 	provisionCmd.Flags().StringSliceVar(&provisionTags, "tags", nil, "Ansible tags to run (comma-separated)")
+	provisionCmd.Flags().StringSliceVar(&provisionSkipTags, "skip-tags", nil, "Ansible tags to skip (comma-separated)")
+	provisionCmd.Flags().StringVar(&provisionTagsFile, "tags-file", "", "YAML file listing more tags to run (tags: [a, b]), merged with --tags")
+	provisionCmd.Flags().StringVar(&provisionSkipFile, "skip-tags-file", "", "YAML file listing more tags to skip (tags: [a, b]), merged with --skip-tags")
+	provisionCmd.Flags().IntVar(&provisionForks, "forks", 0, "Parallel processes for ansible-playbook --forks (0 = Ansible default; little effect in local mode)")
+	provisionCmd.Flags().StringVar(&provisionConnection, "connection", "local", "Ansible connection type; anything but local runs against the --inventory hosts from inside the distribution")
+	provisionCmd.Flags().StringVar(&provisionInventory, "inventory", "", "Inventory file of remote hosts for a non-local --connection")
+	provisionCmd.Flags().StringVar(&provisionLimit, "limit", "", "Ansible host pattern for --limit (localhost or all are the only hosts in local mode)")
 	provisionCmd.Flags().StringSliceVar(&provisionPlaybooks, "playbooks", []string{}, "Playbook files, URLs, or aliases (comma-separated or repeat flag)")
 	provisionCmd.Flags().StringVar(&provisionExtraVars, "extra-vars", "", "Extra variables in key=val format (space or comma-separated)")
+	provisionCmd.Flags().StringVar(&provisionVarsFile, "extra-vars-file", "", "JSON or YAML file of extra variables; --extra-vars values override it")
+	provisionCmd.Flags().StringVar(&provisionAnsibleCfg, "ansible-cfg", "", "Custom ansible.cfg to run the playbooks with (sets ANSIBLE_CONFIG)")
+	provisionCmd.Flags().StringVar(&provisionSummary, "summary-json", "", "Write the playbook execution summary to this file as JSON (written on failure too)")
+	provisionCmd.Flags().StringSliceVar(&provisionPipPkgs, "pip-packages", nil, "Python packages to install with pip3 before the playbooks run (comma-separated)")
 	provisionCmd.Flags().StringVar(&provisionRepo, "repo", "", "Git repository URL containing playbooks")
+	provisionCmd.Flags().StringVar(&provisionRepoSSHKey, "repo-ssh-key", "", "Private SSH key for cloning a private --repo")
+	provisionCmd.Flags().StringVar(&provisionRepoBranch, "repo-branch", "", "Branch or tag of --repo to check out")
+	provisionCmd.Flags().StringVar(&provisionRepoBook, "repo-playbook", "", "Playbook to run from --repo, relative to the repository root (default: choose interactively)")
+	provisionCmd.Flags().StringVar(&provisionOutputLog, "output-log", "", "Also append Ansible output to this file")
+	provisionCmd.Flags().BoolVar(&provisionReinstallAnsible, "force-reinstall-ansible", false, "Install Ansible again even if it is present, e.g. to upgrade it")
+	provisionCmd.Flags().StringVar(&provisionAnsibleVer, "ansible-version", "", "Minimum Ansible version the playbooks need (e.g. 2.14); fail early if older")
 	provisionCmd.Flags().BoolVarP(&provisionVerbose, "verbose", "v", false, "Verbose output")
+	provisionCmd.Flags().IntVar(&provisionMaxRetries, "max-retries", 3, "Retries for transient network failures when downloading playbooks")
+	provisionCmd.Flags().BoolVar(&provisionForceRefresh, "force-refresh-playbooks", false, "Download playbook URLs again instead of reusing copies from the last hour")
+	provisionCmd.Flags().StringVar(&provisionVaultPasswordFile, "vault-password-file", "", "Ansible Vault password file")
+	provisionCmd.Flags().BoolVar(&provisionAskVaultPass, "ask-vault-pass", false, "Prompt for the Ansible Vault password")
+	provisionCmd.MarkFlagsMutuallyExclusive("vault-password-file", "ask-vault-pass")
+	provisionCmd.Flags().Var(vaultIDsValue{&provisionVaultIDs}, "vault-id", "Ansible vault ID as label@file or label@prompt (repeatable)")
+	provisionCmd.Flags().StringVar(&provisionBecomePassFile, "become-password-file", "", "File with the sudo password for Ansible privilege escalation")
+	provisionCmd.Flags().BoolVar(&provisionSkipGalaxy, "skip-galaxy-install", false, "Don't install collections from a requirements.yml next to the playbook")
+	provisionCmd.Flags().StringVar(&provisionGalaxyColls, "galaxy-collections-file", "", "Requirements file of Galaxy collections to install before the playbooks")
+	provisionCmd.Flags().StringVar(&provisionGalaxyRoles, "galaxy-roles-file", "", "Requirements file of Galaxy roles to install before the playbooks")
+	provisionCmd.Flags().BoolVar(&provisionCheck, "check", false, "Run Ansible in check mode: report what would change without changing it")
+	provisionCmd.Flags().BoolVar(&provisionDiff, "diff", false, "Show the changes Ansible makes (or would make with --check) to files")
+	provisionCmd.Flags().BoolVar(&provisionSkipValidate, "skip-validation", false, "Don't check the YAML structure of the playbooks before provisioning")
+	provisionCmd.Flags().BoolVar(&provisionSyntaxCheck, "syntax-check", false, "Check the syntax of all playbooks before running any of them")
+	provisionCmd.Flags().BoolVar(&provisionContinueOnErr, "continue-on-error", false, "Run the remaining playbooks when one fails instead of stopping")
+	provisionCmd.Flags().BoolVar(&provisionParallel, "parallel", false, "Run playbooks concurrently; all playbooks run even if one fails")
+	provisionCmd.MarkFlagsMutuallyExclusive("check", "parallel")
+	provisionCmd.MarkFlagsMutuallyExclusive("diff", "parallel")
+	provisionCmd.Flags().IntVar(&provisionMaxParallel, "max-parallel", defaultMaxParallel, "Maximum number of playbooks to run at once with --parallel")
+	provisionCmd.Flags().StringArrayVar(&provisionPreHooks, "pre-hook", nil, "PowerShell command to run on Windows before provisioning; a failure aborts (repeatable)")
+	provisionCmd.Flags().StringArrayVar(&provisionPostHooks, "post-hook", nil, "PowerShell command to run on Windows after provisioning (repeatable)")
+	provisionCmd.Flags().BoolVar(&provisionStrictHooks, "strict-hooks", false, "Fail the command when a post-provision hook fails")
+	provisionCmd.Flags().StringArrayVar(&provisionPreCmds, "pre-cmd", nil, "Shell command to run in the distribution before each playbook; a failure aborts (repeatable)")
+	provisionCmd.Flags().StringArrayVar(&provisionPostCmds, "post-cmd", nil, "Shell command to run in the distribution after each playbook; failures only warn (repeatable)")
+	provisionCmd.Flags().BoolVar(&provisionStrictPostCmds, "strict-post-cmds", false, "Fail the playbook when a --post-cmd command fails")
+	provisionCmd.Flags().BoolVar(&provisionStartFresh, "start-fresh", false, "Terminate the distribution before provisioning and restart it after a successful run")
+	provisionCmd.Flags().BoolVar(&provisionEnableSystemd, "enable-systemd", false, "Enable systemd in /etc/wsl.conf and restart the distribution before running playbooks (WSL 2 only)")
+	_ = provisionCmd.RegisterFlagCompletionFunc("playbooks", completePlaybookAliases)
+}
+
+// selectRepoPlaybook asks which YAML file of a cloned repository to run. The
+// common playbook names are offered first; without a terminal the first of
+// them is used.
+func selectRepoPlaybook(distroName, repoDir string) (string, error) {
+	candidates, err := ansible.ListRepoPlaybooks(distroName, repoDir)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no .yml playbooks found in the repository")
+	}
+	if len(candidates) == 1 {
+		fmt.Printf("Using playbook: %s\n", candidates[0])
+		return candidates[0], nil
+	}
+
+	prompt := promptui.Select{
+		Label: "Select a playbook from the repository",
+		Items: candidates,
+		Size:  12,
+	}
+	idx, _, err := ui.Select(prompt)
+	if errors.Is(err, ui.ErrNoDefault) {
+		for _, name := range ansible.CommonPlaybookNames {
+			if candidates[0] == name {
+				fmt.Printf("Using playbook: %s\n", name)
+				return name, nil
+			}
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("playbook selection cancelled: %w", err)
+	}
+	return candidates[idx], nil
 }
 
 func runProvision(cmd *cobra.Command, args []string) error {
@@ -71,6 +289,8 @@ func runProvision(cmd *cobra.Command, args []string) error {
 	// Get distribution name using shared helper
 	if len(args) > 0 {
 		distroName = args[0]
+	} else if activeProfile != nil && activeProfile.DefaultName != "" {
+		distroName = activeProfile.DefaultName
 	} else {
 		var err error
 		distroName, err = selectInstalledDistroInteractive()
@@ -84,31 +304,21 @@ func runProvision(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to check distribution: %w", err)
 	}
-	if !exists {
-		// List available distros to help the user
-		distros, listErr := wsl.ListInstalledDistros()
-		if listErr == nil && len(distros) > 0 {
-			fmt.Fprintf(os.Stderr, "\nError: distribution '%s' does not exist\n\n", distroName)
-			fmt.Fprintln(os.Stderr, "Available installed distributions:")
-			fmt.Fprintln(os.Stderr, strings.Repeat("-", 60))
-			for _, d := range distros {
-				marker := " "
-				if d.Default {
-					marker = "*"
-				}
-				status := d.State
-				fmt.Fprintf(os.Stderr, "%s %-30s (%s)\n", marker, d.Name, status)
-			}
-			fmt.Fprintln(os.Stderr, strings.Repeat("-", 60))
-			fmt.Fprintln(os.Stderr, "\nTip: Use 'autowsl list' to see all installed distributions")
-			fmt.Fprintln(os.Stderr, "     Use 'autowsl install' to install a new distribution")
-			return fmt.Errorf("distribution not found")
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	if provisionRepoBook != "" {
+		if provisionRepo == "" {
+			return fmt.Errorf("--repo-playbook requires --repo")
+		}
+		if err := ansible.ValidateRepoPlaybookPath(provisionRepoBook); err != nil {
+			return err
 		}
-		return fmt.Errorf("distribution '%s' does not exist", distroName)
 	}
 
 	// If no playbooks specified via flags, use interactive prompt
-	if len(provisionPlaybooks) == 0 {
+	if len(provisionPlaybooks) == 0 && provisionRepo == "" {
 		// Interactive mode - prompt for playbooks (both with and without distro arg)
 		playbookInputs, err = promptForPlaybooks()
 		if err != nil {
@@ -118,11 +328,38 @@ func runProvision(cmd *cobra.Command, args []string) error {
 		playbookInputs = provisionPlaybooks
 	}
 
+	provisionHooks := config.Get().Hooks.Merge(hooks.Hooks{PreProvision: provisionPreHooks, PostProvision: provisionPostHooks})
+	hookEnv := hooks.Env{Distro: distroName, Playbook: strings.Join(playbookInputs, ",")}
+	if provisionRepo != "" {
+		hookEnv.Playbook = provisionRepo
+	}
+	if len(provisionHooks.PreProvision) > 0 {
+		hookEnv.Status = hooks.StatusPending
+		if err := hooks.Run(provisionHooks.PreProvision, hookEnv); err != nil {
+			return fmt.Errorf("pre-provision %w", err)
+		}
+	}
+
+	if provisionStartFresh {
+		fmt.Printf("→ Terminating '%s' for a fresh start...\n", distroName)
+		if err := stopDistroAndWait(distroName); err != nil {
+			return err
+		}
+		fmt.Printf("✓ '%s' is stopped\n\n", distroName)
+	}
+
+	if provisionEnableSystemd {
+		if err := enableSystemd(distroName); err != nil {
+			return err
+		}
+	}
+
 	// Create temp directory for downloads
-	cwd, _ := os.Getwd()
-	tempDir := filepath.Join(cwd, ".autowsl_tmp")
-	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+	tempDir := tempDirPath()
+	if !dryRun {
+		if err := os.MkdirAll(tempDir, 0755); err != nil {
+			return fmt.Errorf("failed to create temp directory: %w", err)
+		}
 	}
 
 	// Handle repo-based provisioning (legacy mode)
@@ -130,21 +367,28 @@ func runProvision(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using playbook from Git repository\n\n")
 
 		tmpDir := "/tmp/autowsl-playbooks"
-		if err := ansible.CloneGitRepo(distroName, provisionRepo, tmpDir); err != nil {
+		if err := ansible.CloneGitRepo(distroName, ansible.CloneOptions{
+			RepoURL:    provisionRepo,
+			DestDir:    tmpDir,
+			SSHKeyPath: provisionRepoSSHKey,
+			Branch:     provisionRepoBranch,
+		}); err != nil {
 			return err
 		}
 
-		// Look for common playbook names
-		commonNames := []string{"site.yml", "main.yml", "playbook.yml", "default.yml"}
-		for _, name := range commonNames {
-			testPath := filepath.Join(tmpDir, name)
-			playbookInputs = []string{testPath}
-			break
-		}
-
-		if len(playbookInputs) == 0 {
-			return fmt.Errorf("no playbook found in repository (looked for: %s)", strings.Join(commonNames, ", "))
+		repoPlaybook := provisionRepoBook
+		if repoPlaybook == "" {
+			if dryRun {
+				fmt.Println("[dry-run] would choose a playbook from the repository; pass --repo-playbook to preview it")
+				return nil
+			}
+			repoPlaybook, err = selectRepoPlaybook(distroName, tmpDir)
+			if err != nil {
+				return err
+			}
 		}
+		// Windows reads the clone through the distribution's \\wsl$ share
+		playbookInputs = []string{wsl.WindowsPath(distroName, path.Join(tmpDir, strings.ReplaceAll(repoPlaybook, `\`, "/")))}
 	}
 
 	// Process extra-vars
@@ -156,12 +400,113 @@ func runProvision(cmd *cobra.Command, args []string) error {
 	}
 
 	// Use shared provisioning pipeline
-	return runProvisioningPipeline(ProvisioningPipelineOptions{
+	err = runProvisioningPipeline(ProvisioningPipelineOptions{
 		DistroName:     distroName,
 		PlaybookInputs: playbookInputs,
 		Tags:           provisionTags,
+		SkipTags:       provisionSkipTags,
+		TagsFile:       provisionTagsFile,
+		SkipTagsFile:   provisionSkipFile,
+		Limit:          provisionLimit,
+		Forks:          provisionForks,
+		Connection:     provisionConnection,
+		InventoryPath:  provisionInventory,
+		OutputLog:      provisionOutputLog,
 		Verbose:        provisionVerbose,
 		ExtraVars:      extraVarsSlice,
+		ExtraVarsFile:  provisionVarsFile,
+		AnsibleCfg:     provisionAnsibleCfg,
+		PipPackages:    provisionPipPkgs,
+		SummaryJSON:    provisionSummary,
 		TempDir:        tempDir,
+		MaxRetries:     provisionMaxRetries,
+
+		ForceRefreshPlaybooks: provisionForceRefresh,
+
+		VaultPasswordFile: provisionVaultPasswordFile,
+		AskVaultPass:      provisionAskVaultPass,
+		VaultIDs:          provisionVaultIDs,
+		SkipGalaxyInstall: provisionSkipGalaxy,
+
+		GalaxyCollectionsFile: provisionGalaxyColls,
+		GalaxyRolesFile:       provisionGalaxyRoles,
+
+		ValidatePlaybooks: !provisionSkipValidate,
+
+		BecomePasswordFile:     provisionBecomePassFile,
+		RequiredAnsibleVersion: provisionAnsibleVer,
+		ForceReinstallAnsible:  provisionReinstallAnsible,
+		SyntaxCheck:            provisionSyntaxCheck,
+		CheckMode:              provisionCheck,
+		DiffMode:               provisionDiff,
+		ContinueOnError:        provisionContinueOnErr,
+
+		Parallel:    provisionParallel,
+		MaxParallel: provisionMaxParallel,
+
+		PrePlaybookCmds:  provisionPreCmds,
+		PostPlaybookCmds: provisionPostCmds,
+		StrictPostCmds:   provisionStrictPostCmds,
 	})
+
+	if provisionStartFresh && err == nil {
+		fmt.Printf("\n→ Restarting '%s'...\n", distroName)
+		if restartErr := restartDistro(distroName); restartErr != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: failed to restart '%s': %v\n", distroName, restartErr)
+		} else {
+			fmt.Printf("✓ '%s' restarted\n", distroName)
+		}
+	}
+
+	if len(provisionHooks.PostProvision) > 0 {
+		hookEnv.Status = hooks.StatusSuccess
+		if err != nil {
+			hookEnv.Status = hooks.StatusFailed
+		}
+		if hookErr := hooks.Run(provisionHooks.PostProvision, hookEnv); hookErr != nil {
+			if provisionStrictHooks && err == nil {
+				return fmt.Errorf("post-provision %w", hookErr)
+			}
+			fmt.Fprintf(os.Stderr, "⚠ Warning: post-provision %v\n", strings.SplitN(hookErr.Error(), "\n", 2)[0])
+		}
+	}
+	return err
+}
+
+// enableSystemd turns on systemd in the distribution's wsl.conf and restarts it
+// so playbooks can manage services. WSL 1 distributions are left alone.
+func enableSystemd(distroName string) error {
+	if distroWSLVersion(distroName) == "1" {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: '%s' runs on WSL 1, which does not support systemd; skipping --enable-systemd\n\n", distroName)
+		return nil
+	}
+	if ansible.SystemdEnabled(distroName) {
+		fmt.Printf("✓ systemd is already enabled in '%s'\n\n", distroName)
+		return nil
+	}
+	if err := ansible.EnsureSystemd(distroName); err != nil {
+		return err
+	}
+	fmt.Printf("→ Restarting '%s' to start systemd...\n", distroName)
+	if err := restartDistro(distroName); err != nil {
+		return fmt.Errorf("failed to restart '%s' after enabling systemd: %w", distroName, err)
+	}
+	fmt.Printf("✓ '%s' restarted with systemd\n\n", distroName)
+	return nil
+}
+
+// stopDistroAndWait terminates a distribution and waits until WSL reports it stopped
+func stopDistroAndWait(name string) error {
+	if err := wsl.Stop(name); err != nil {
+		return err
+	}
+	return wsl.WaitForState(name, "Stopped", startFreshTimeout)
+}
+
+// restartDistro terminates a distribution and boots it again
+func restartDistro(name string) error {
+	if err := stopDistroAndWait(name); err != nil {
+		return err
+	}
+	return wsl.Start(name)
 }
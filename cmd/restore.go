@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	restoreName       string
+	restorePath       string
+	restoreWSLVersion int
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <backup-file> [distro-name]",
+	Short: "Restore a WSL distribution from a backup",
+	Long: `Restore a WSL distribution from a tar file created by 'autowsl backup'.
+When no name is given, one is derived from the backup file name. If that name
+is already taken you are asked for another one.
+
+Examples:
+  # Restore under a name derived from the file (ubuntu-2204-lts)
+  autowsl restore ~/WSL-Backups/ubuntu-2204-lts-backup.tar
+
+  # Restore under a new name and location
+  autowsl restore ./dev-box-backup.tar.gz dev-box-2 --path D:\WSL\dev-box-2
+
+  # Restore as a WSL 1 distribution
+  autowsl restore ./legacy-backup.tar --version 1`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().StringVar(&restoreName, "name", "", "Name for the restored distribution")
+	restoreCmd.Flags().StringVar(&restorePath, "path", "", "Installation path for the restored distribution")
+	restoreCmd.Flags().IntVar(&restoreWSLVersion, "version", 2, "WSL version to use (1 or 2)")
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	backupFile := args[0]
+
+	if restoreWSLVersion != 1 && restoreWSLVersion != 2 {
+		return fmt.Errorf("invalid --version %d (must be 1 or 2)", restoreWSLVersion)
+	}
+
+	absTarPath, err := filepath.Abs(backupFile)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for backup file: %w", err)
+	}
+	if err := verifyBackupReadable(absTarPath); err != nil {
+		return err
+	}
+
+	// Determine the distribution name
+	distroName := restoreName
+	if len(args) > 1 {
+		if restoreName != "" && restoreName != args[1] {
+			return fmt.Errorf("distribution name given both as argument ('%s') and --name ('%s')", args[1], restoreName)
+		}
+		distroName = args[1]
+	}
+	if distroName == "" {
+		distroName, err = generateDistroName(distro.Distro{Version: backupBaseName(absTarPath)})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Ask for another name until we find one that is free
+	for {
+		if err := wsl.ValidateDistroName(distroName); err != nil {
+			return err
+		}
+		exists, err := wsl.IsDistroInstalled(distroName)
+		if err != nil {
+			return fmt.Errorf("failed to check existing distributions: %w", err)
+		}
+		if !exists {
+			break
+		}
+
+		fmt.Printf("Distribution '%s' already exists.\n", distroName)
+		namePrompt := promptui.Prompt{
+			Label:   "New distribution name",
+			Default: distroName + "-restored",
+		}
+		customName, err := ui.Prompt(namePrompt)
+		if err != nil {
+			return fmt.Errorf("distribution '%s' already exists", distroName)
+		}
+		distroName = customName
+	}
+
+	distroPath := restorePath
+	if distroPath == "" {
+		distroPath = filepath.Join(defaultInstallRoot(), distroName)
+	}
+
+	fileInfo, _ := os.Stat(absTarPath)
+	sizeInMB := float64(fileInfo.Size()) / 1024 / 1024
+
+	// Display configuration
+	fmt.Printf("\n%s\n", strings.Repeat("=", 60))
+	fmt.Printf("Restore Configuration\n")
+	fmt.Printf("%s\n", strings.Repeat("=", 60))
+	fmt.Printf("Backup:       %s (%.2f MB)\n", filepath.Base(absTarPath), sizeInMB)
+	fmt.Printf("Name:         %s\n", distroName)
+	fmt.Printf("Path:         %s\n", distroPath)
+	fmt.Printf("WSL Version:  %d\n", restoreWSLVersion)
+	fmt.Printf("%s\n\n", strings.Repeat("=", 60))
+
+	fmt.Println("→ Importing to WSL...")
+	fmt.Println("  This may take a while depending on the size of the backup...")
+	importOpts := wsl.ImportOptions{
+		Name:        distroName,
+		InstallPath: distroPath,
+		TarFilePath: absTarPath,
+		Version:     restoreWSLVersion,
+	}
+	if err := wsl.Import(importOpts); err != nil {
+		return fmt.Errorf("failed to import distribution '%s' to '%s': %w", distroName, distroPath, err)
+	}
+	fmt.Println("  ✓ Import completed successfully")
+
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("✓ SUCCESS: WSL distribution restored\n")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("Name:     %s\n", distroName)
+	fmt.Printf("Location: %s\n", distroPath)
+	fmt.Printf("Version:  WSL %d\n", restoreWSLVersion)
+	fmt.Printf("Source:   %s\n", absTarPath)
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("\nLaunch with:  wsl -d %s\n\n", distroName)
+
+	return nil
+}
+
+// backupBaseName strips archive extensions and the "-backup" suffix that
+// 'autowsl backup' adds, e.g. "ubuntu-backup.tar.gz" -> "ubuntu"
+func backupBaseName(path string) string {
+	name := filepath.Base(path)
+	for _, ext := range []string{".gz", ".xz", ".tar"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return strings.TrimSuffix(name, "-backup")
+}
+
+// verifyBackupReadable makes sure the backup can be opened and, for plain and
+// gzip-compressed tars, that it starts with a valid tar header
+func verifyBackupReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("backup file does not exist: %s", path)
+		}
+		return fmt.Errorf("cannot read backup file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(6)
+	if err != nil {
+		return fmt.Errorf("backup file '%s' is empty or truncated", path)
+	}
+
+	var r io.Reader = br
+	switch {
+	case bytes.Equal(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		// xz cannot be inspected without an external tool; leave it to wsl --import
+		return nil
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("backup file '%s' is not a valid gzip archive: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	if _, err := tar.NewReader(r).Next(); err != nil {
+		return fmt.Errorf("backup file '%s' is not a valid tar archive: %w", path, err)
+	}
+	return nil
+}
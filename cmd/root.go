@@ -3,8 +3,20 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/config"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/history"
+	"github.com/yuanjua/autowsl/internal/hooks"
+	"github.com/yuanjua/autowsl/internal/runner"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wsl"
 )
 
 // Version is set during build time
@@ -16,6 +28,76 @@ var rootCmd = &cobra.Command{
 	Long: `AutoWSL is a CLI tool to interactively select, download, and install 
 WSL distributions from official sources.`,
 	Version: Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyConfigDefaults(cmd); err != nil {
+			return err
+		}
+		if err := applyProfileDefaults(cmd); err != nil {
+			return err
+		}
+		wsl.SetDryRun(dryRun)
+		ansible.SetDryRun(dryRun)
+		wsl.SetWSLPath(wslPathFlag)
+		ansible.SetWSLPath(wslPathFlag)
+		if commandTimeout < 0 {
+			return fmt.Errorf("invalid --timeout %s (must not be negative)", commandTimeout)
+		}
+		wsl.SetTimeout(commandTimeout)
+		ansible.SetTimeout(commandTimeout)
+		extractor.SetDryRun(dryRun)
+		hooks.SetDryRun(dryRun)
+		if keepHistory < 0 {
+			return fmt.Errorf("invalid --keep-history %d (must not be negative)", keepHistory)
+		}
+		history.MaxEntries = keepHistory
+		if assumeYes {
+			ui.SetPrompter(ui.NonInteractivePrompter{})
+		}
+		// Commands that read the catalog load catalog_url with useRemoteCatalog
+		remoteCatalogLoaded = false
+		if catalogPath != "" {
+			if err := distro.LoadCatalog(catalogPath, catalogReplace); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var (
+	catalogPath    string
+	catalogReplace bool
+	dryRun         bool
+	assumeYes      bool
+	wslPathFlag    string
+	playbookDir    string
+)
+
+var noCleanup bool
+
+var commandTimeout time.Duration
+
+var keepHistory int
+
+// configFlagKeys maps command flags to the config keys that provide their defaults
+var configFlagKeys = map[string]string{
+	"version":     "default_wsl_version",
+	"keep-tar":    "keep_tar",
+	"max-retries": "max_retries",
+	"no-cleanup":  "no_cleanup",
+
+	"keep-history": "history_limit",
+}
+
+// configFlagCommands limits a config default to the commands whose flag of
+// that name means the same thing; other commands keep their own defaults
+var configFlagCommands = map[string][]string{
+	"version": {"install", "copy", "import"},
+}
+
+// RootCommand returns the autowsl command tree, e.g. to run commands from tests
+func RootCommand() *cobra.Command {
+	return rootCmd
 }
 
 func Execute() {
@@ -26,5 +108,48 @@ func Execute() {
 }
 
 func init() {
-	// Global flags can be added here
+	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVar(&catalogPath, "catalog", "", "Path to a JSON distro catalog to merge with the built-in one")
+	rootCmd.PersistentFlags().BoolVar(&catalogReplace, "catalog-replace", false, "Use only the --catalog file instead of merging it")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the commands that would run without changing anything")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Never prompt: accept confirmations and use defaults (for CI)")
+	rootCmd.PersistentFlags().StringVar(&playbookDir, "playbook-dir", "", "Directory searched for playbook aliases (default: playbooks_dir from the config file, else ./playbooks)")
+	rootCmd.PersistentFlags().BoolVar(&noCleanup, "no-cleanup", false, "Keep the temp directory (.autowsl_tmp) after install, copy and provision (for debugging)")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", 0, "Kill any single wsl.exe command that runs longer than this, e.g. 30s, 5m or 1h30m (default: no limit)")
+	rootCmd.PersistentFlags().IntVar(&keepHistory, "keep-history", history.DefaultMaxEntries, "Playbook runs kept in the history log; older ones are dropped (default: history_limit from the config file)")
+	rootCmd.PersistentFlags().StringVar(&wslPathFlag, "wsl-path", "", "Path to the wsl.exe binary to use (default: wsl.exe from PATH)")
+}
+
+// wslExecutable returns the wsl.exe binary selected with --wsl-path
+func wslExecutable() string {
+	if wslPathFlag != "" {
+		return wslPathFlag
+	}
+	return runner.DefaultWSLPath
+}
+
+// initConfig loads ~/.autowsl.yml before any subcommand runs
+func initConfig() {
+	if err := config.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// applyConfigDefaults fills in flags the user did not provide from the
+// environment or the config file
+func applyConfigDefaults(cmd *cobra.Command) error {
+	var applyErr error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		key, ok := configFlagKeys[f.Name]
+		if !ok || f.Changed || !config.IsSet(key) {
+			return
+		}
+		if commands, scoped := configFlagCommands[f.Name]; scoped && !slices.Contains(commands, cmd.Name()) {
+			return
+		}
+		if err := f.Value.Set(config.GetString(key)); err != nil && applyErr == nil {
+			applyErr = fmt.Errorf("invalid config value for '%s': %w", key, err)
+		}
+	})
+	return applyErr
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	runUser    string
+	runTimeout time.Duration
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <distro-name> -- <command> [args...]",
+	Short: "Run a command inside a WSL distribution",
+	Long: `Run a command inside a WSL distribution without opening an interactive shell.
+Use -- to separate autowsl flags from the command to run. The exit code of the
+command is returned as the exit code of autowsl.
+
+Examples:
+  # Print the kernel version
+  autowsl run ubuntu-2204-lts -- uname -a
+
+  # Run as root
+  autowsl run ubuntu-2204-lts --user root -- apt-get update
+
+  # Abort if the command takes too long
+  autowsl run ubuntu-2204-lts --timeout 30s -- ./long-task.sh`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runRun,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVarP(&runUser, "user", "u", "", "Run the command as this user (e.g. root)")
+	runCmd.Flags().DurationVar(&runTimeout, "timeout", 0, "Kill the command after this duration (e.g. 30s, 5m)")
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash != 1 {
+		return fmt.Errorf("usage: autowsl run <distro-name> -- <command> [args...]")
+	}
+
+	distroName := args[0]
+	command := args[1:]
+
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	recordDistroStart(distroName)
+	err = wsl.RunCommand(distroName, command, wsl.RunCommandOptions{
+		User:    runUser,
+		Timeout: runTimeout,
+	})
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Exit with the same code as the guest command
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	shellUser string
+	shellPath string
+	shellExec string
+)
+
+var shellCmd = &cobra.Command{
+	Use:     "shell [distro-name] [-- <command> [args...]]",
+	Aliases: []string{"enter"},
+	Short:   "Open a shell in a WSL distribution",
+	Long: `Open a shell in a WSL distribution interactively or by name.
+
+With --user or --shell the given shell is started as that user. Without --shell
+the user's login shell is read from /etc/passwd in the distribution.
+
+With --exec, or a command after --, the command runs instead of a shell and
+autowsl exits with its exit code.
+
+Examples:
+  # Interactive mode - select from installed distros
+  autowsl shell
+
+  # Open a shell in a specific distribution by name
+  autowsl shell ubuntu-2004-lts
+
+  # Open a root shell
+  autowsl shell ubuntu-2004-lts --user root
+
+  # Use zsh instead of the login shell
+  autowsl shell ubuntu-2004-lts --shell /bin/zsh
+
+  # Run a single command and exit
+  autowsl enter ubuntu-2004-lts --exec "ls /home"
+  autowsl enter ubuntu-2004-lts --user root -- apt-get update`,
+	RunE: runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+	shellCmd.Flags().StringVarP(&shellUser, "user", "u", "", "Open the shell or run the command as this user (e.g. root)")
+	shellCmd.Flags().StringVar(&shellPath, "shell", "", "Absolute path of the shell to start (default: the user's login shell)")
+	shellCmd.Flags().StringVar(&shellExec, "exec", "", "Run this command with sh -c instead of opening a shell")
+	shellCmd.MarkFlagsMutuallyExclusive("exec", "shell")
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	var distroName string
+	var err error
+
+	// A command after -- or from --exec runs instead of the shell
+	var command []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		command = args[dash:]
+		args = args[:dash]
+		if len(command) == 0 {
+			return fmt.Errorf("no command given after --")
+		}
+		if shellExec != "" {
+			return fmt.Errorf("use either --exec or a command after --, not both")
+		}
+		if shellPath != "" {
+			return fmt.Errorf("--shell cannot be combined with a command after --")
+		}
+	} else if shellExec != "" {
+		command = []string{"sh", "-c", shellExec}
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("accepts at most 1 distribution name, received %d", len(args))
+	}
+
+	if shellPath != "" {
+		if err := wsl.ValidateShellPath(shellPath); err != nil {
+			return err
+		}
+	}
+
+	// Determine distro name - from args or interactive selection
+	if len(args) > 0 {
+		distroName = args[0]
+
+		// Verify the distribution exists
+		exists, err := wsl.IsDistroInstalled(distroName)
+		if err != nil {
+			return fmt.Errorf("failed to check distribution: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("distribution '%s' is not installed", distroName)
+		}
+	} else {
+		// Use interactive selection from installed distros
+		distroName, err = selectInstalledDistroInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	if command != nil {
+		return runShellCommand(distroName, command)
+	}
+	if shellUser != "" || shellPath != "" {
+		return runCustomShell(distroName)
+	}
+
+	fmt.Printf("Entering '%s'...\n\n", distroName)
+	recordDistroStart(distroName)
+
+	// Execute wsl -d <distro-name>
+	wslPath, err := exec.LookPath(wslExecutable())
+	if err != nil {
+		return fmt.Errorf("failed to find wsl.exe: %w", err)
+	}
+
+	// Create command to enter the WSL distribution
+	wslCmd := exec.Command(wslPath, "-d", distroName)
+	wslCmd.Stdin = os.Stdin
+	wslCmd.Stdout = os.Stdout
+	wslCmd.Stderr = os.Stderr
+
+	err = wslCmd.Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			// Exit with the same code as wsl
+			os.Exit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to enter distribution: %w", err)
+	}
+
+	return nil
+}
+
+// runCustomShell starts --shell, or the login shell of --user, with
+// wsl -d <distro-name> -u <user> -- <shell>
+func runCustomShell(distroName string) error {
+	shell := shellPath
+	if shell == "" {
+		if dryRun {
+			shell = "<login shell>"
+			fmt.Println("[dry-run] would read the login shell from /etc/passwd")
+		} else {
+			var err error
+			if shell, err = wsl.LoginShell(distroName, shellUser); err != nil {
+				return err
+			}
+		}
+	}
+
+	if shellUser != "" {
+		fmt.Printf("Entering '%s' as %s (%s)...\n\n", distroName, shellUser, shell)
+	} else {
+		fmt.Printf("Entering '%s' (%s)...\n\n", distroName, shell)
+	}
+	recordDistroStart(distroName)
+
+	exitCode, err := wsl.Exec(distroName, shellUser, []string{shell})
+	if err != nil {
+		return fmt.Errorf("failed to enter distribution: %w", err)
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// runShellCommand runs command with wsl -d <distro-name> [-u <user>] -- <command>
+// and exits with its exit code
+func runShellCommand(distroName string, command []string) error {
+	recordDistroStart(distroName)
+
+	exitCode, err := wsl.Exec(distroName, shellUser, command)
+	if err != nil {
+		return fmt.Errorf("failed to run command: %w", err)
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
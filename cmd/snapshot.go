@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/snapshot"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	snapshotName        string
+	snapshotListOutput  string
+	snapshotRestoreName string
+	snapshotRestorePath string
+	snapshotRestoreVer  int
+	snapshotPruneKeep   int
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot <distro-name>",
+	Short: "Take and manage snapshots of a distribution",
+	Long: `Export a distribution to the snapshot directory (~/.autowsl/snapshots/<distro>)
+under a generated name. Snapshots can be listed, restored as new distributions,
+deleted, and pruned.
+
+Examples:
+  # Take a snapshot named <distro>-<timestamp>
+  autowsl snapshot ubuntu-2204-lts
+
+  # Take a named snapshot
+  autowsl snapshot ubuntu-2204-lts --name before-upgrade
+
+  autowsl snapshot list ubuntu-2204-lts
+
+  # Restore as a new distribution (ubuntu-2204-lts-1, -2, ...)
+  autowsl snapshot restore ubuntu-2204-lts before-upgrade
+
+  autowsl snapshot delete ubuntu-2204-lts before-upgrade
+
+  # Keep only the 3 newest snapshots
+  autowsl snapshot prune ubuntu-2204-lts --keep 3`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runSnapshot,
+	ValidArgsFunction: completeInstalledDistros,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:               "list <distro-name>",
+	Short:             "List the snapshots of a distribution",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runSnapshotList,
+	ValidArgsFunction: completeInstalledDistros,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:               "restore <distro-name> <snapshot-name>",
+	Short:             "Restore a snapshot as a new distribution",
+	Args:              cobra.ExactArgs(2),
+	RunE:              runSnapshotRestore,
+	ValidArgsFunction: completeSnapshots,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:               "delete <distro-name> <snapshot-name>",
+	Short:             "Delete a snapshot",
+	Args:              cobra.ExactArgs(2),
+	RunE:              runSnapshotDelete,
+	ValidArgsFunction: completeSnapshots,
+}
+
+var snapshotPruneCmd = &cobra.Command{
+	Use:               "prune <distro-name>",
+	Short:             "Delete all but the newest snapshots of a distribution",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runSnapshotPrune,
+	ValidArgsFunction: completeInstalledDistros,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	snapshotCmd.AddCommand(snapshotPruneCmd)
+
+	snapshotCmd.Flags().StringVar(&snapshotName, "name", "", "Snapshot name (default: <distro>-<timestamp>)")
+	snapshotListCmd.Flags().StringVarP(&snapshotListOutput, "output", "o", "table", "Output format: table, json, or yaml")
+	snapshotRestoreCmd.Flags().StringVar(&snapshotRestoreName, "name", "", "Name for the restored distribution (default: <distro>-<n>)")
+	snapshotRestoreCmd.Flags().StringVar(&snapshotRestorePath, "path", "", "Installation path for the restored distribution")
+	snapshotRestoreCmd.Flags().IntVar(&snapshotRestoreVer, "version", 2, "WSL version to use (1 or 2)")
+	snapshotPruneCmd.Flags().IntVar(&snapshotPruneKeep, "keep", 5, "Number of newest snapshots to keep")
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	now := time.Now()
+	name := snapshotName
+	if name == "" {
+		name = snapshot.NewName(distroName, now)
+	}
+	if err := snapshot.ValidateName(name); err != nil {
+		return err
+	}
+	if _, err := snapshot.Load(distroName, name); err == nil {
+		return fmt.Errorf("snapshot '%s' of '%s' already exists", name, distroName)
+	}
+
+	tarPath := snapshot.TarPath(snapshot.Dir(), distroName, name)
+	if !dryRun {
+		if err := os.MkdirAll(filepath.Dir(tarPath), 0755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+	}
+
+	fmt.Printf("→ Taking snapshot '%s' of '%s'...\n", name, distroName)
+	result, err := wsl.ExportWithOptions(wsl.ExportOptions{Name: distroName, OutputPath: tarPath})
+	if err != nil {
+		return fmt.Errorf("failed to take snapshot: %w", err)
+	}
+	if dryRun {
+		return nil
+	}
+
+	s := snapshot.Snapshot{Name: name, Distro: distroName, CreatedAt: now.UTC(), Size: result.Size}
+	if err := snapshot.Save(s); err != nil {
+		_ = os.Remove(tarPath)
+		return err
+	}
+
+	fmt.Printf("✓ Snapshot '%s' saved (%.2f MB)\n", name, float64(result.Size)/1024/1024)
+	fmt.Printf("  Location: %s\n", result.Path)
+	return nil
+}
+
+func runSnapshotList(cmd *cobra.Command, args []string) error {
+	if snapshotListOutput != "table" && snapshotListOutput != "json" && snapshotListOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", snapshotListOutput)
+	}
+
+	snapshots, err := snapshot.List(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch snapshotListOutput {
+	case "json":
+		if snapshots == nil {
+			snapshots = []snapshot.Snapshot{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshots)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(snapshots); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots of '%s'. Take one with 'autowsl snapshot %s'.\n", args[0], args[0])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tSIZE")
+	for _, s := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%.2f MB\n", s.Name, s.CreatedAt.Local().Format("2006-01-02 15:04:05"), float64(s.Size)/1024/1024)
+	}
+	return w.Flush()
+}
+
+func runSnapshotRestore(cmd *cobra.Command, args []string) error {
+	distroName, name := args[0], args[1]
+
+	if snapshotRestoreVer != 1 && snapshotRestoreVer != 2 {
+		return fmt.Errorf("invalid --version %d (must be 1 or 2)", snapshotRestoreVer)
+	}
+
+	s, err := snapshot.Load(distroName, name)
+	if err != nil {
+		return err
+	}
+	tarPath := snapshot.TarPath(snapshot.Dir(), s.Distro, s.Name)
+	if _, err := os.Stat(tarPath); err != nil {
+		return fmt.Errorf("snapshot '%s' has no tar file: %w", name, err)
+	}
+
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+	installed := make([]string, 0, len(distros))
+	for _, d := range distros {
+		installed = append(installed, d.Name)
+	}
+
+	newName := snapshotRestoreName
+	if newName == "" {
+		newName = snapshot.RestoreName(distroName, installed)
+	}
+	if err := wsl.ValidateDistroName(newName); err != nil {
+		return err
+	}
+	for _, existing := range installed {
+		if existing == newName {
+			return fmt.Errorf("distribution '%s' already exists", newName)
+		}
+	}
+
+	installPath := snapshotRestorePath
+	if installPath == "" {
+		installPath = filepath.Join(defaultInstallRoot(), newName)
+	}
+
+	fmt.Printf("→ Restoring snapshot '%s' as '%s'...\n", name, newName)
+	if err := wsl.Import(wsl.ImportOptions{
+		Name:        newName,
+		InstallPath: installPath,
+		TarFilePath: tarPath,
+		Version:     snapshotRestoreVer,
+	}); err != nil {
+		return fmt.Errorf("failed to import distribution '%s' to '%s': %w", newName, installPath, err)
+	}
+	if dryRun {
+		return nil
+	}
+
+	fmt.Printf("✓ Restored '%s' from snapshot '%s'\n", newName, name)
+	fmt.Printf("  Location: %s\n", installPath)
+	fmt.Printf("\nLaunch with:  autowsl shell %s\n", newName)
+	return nil
+}
+
+func runSnapshotDelete(cmd *cobra.Command, args []string) error {
+	distroName, name := args[0], args[1]
+	if _, err := snapshot.Load(distroName, name); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] would delete snapshot '%s'\n", name)
+		return nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Delete snapshot '%s' of '%s'", name, distroName),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Deletion cancelled")
+		return nil
+	}
+	if err := snapshot.Delete(distroName, name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Deleted snapshot '%s'\n", name)
+	return nil
+}
+
+func runSnapshotPrune(cmd *cobra.Command, args []string) error {
+	distroName := args[0]
+	if snapshotPruneKeep < 0 {
+		return fmt.Errorf("invalid --keep %d (must be 0 or more)", snapshotPruneKeep)
+	}
+
+	snapshots, err := snapshot.List(distroName)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) <= snapshotPruneKeep {
+		fmt.Printf("Nothing to prune: '%s' has %d snapshot(s)\n", distroName, len(snapshots))
+		return nil
+	}
+
+	stale := snapshots[snapshotPruneKeep:]
+	if dryRun {
+		for _, s := range stale {
+			fmt.Printf("[dry-run] would delete snapshot '%s'\n", s.Name)
+		}
+		return nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Delete %d snapshot(s) of '%s', keeping the %d newest", len(stale), distroName, snapshotPruneKeep),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Prune cancelled")
+		return nil
+	}
+
+	deleted, err := snapshot.Prune(distroName, snapshotPruneKeep)
+	for _, s := range deleted {
+		fmt.Printf("✓ Deleted snapshot '%s'\n", s.Name)
+	}
+	return err
+}
+
+// completeSnapshots completes a distribution name, then its snapshot names
+func completeSnapshots(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeInstalledDistros(cmd, args, toComplete)
+	case 1:
+		snapshots, err := snapshot.List(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, 0, len(snapshots))
+		for _, s := range snapshots {
+			names = append(names, s.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
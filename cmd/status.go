@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/metadata"
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+var statusOutput string
+
+// distroStatus is one row of the status command
+type distroStatus struct {
+	wsl.InstalledDistro `yaml:",inline"`
+
+	Runtime       *wsl.RuntimeStatus `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+	LastStartedAt *time.Time         `json:"last_started_at,omitempty" yaml:"last_started_at,omitempty"`
+	Error         string             `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status [distro-name]",
+	Short: "Show IP address, memory usage, and processes of distributions",
+	Long: `Show the runtime status of one or all installed distributions. Running
+distributions report their WSL2 IP address, memory usage, and process count.
+Stopped distributions are not started; they show when autowsl last entered or
+ran a command in them, if known.
+
+Examples:
+  # Status of every installed distribution
+  autowsl status
+
+  # Status of a single distribution
+  autowsl status ubuntu-2204
+
+  # Machine-readable output
+  autowsl status -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "table", "Output format: table, json, or yaml")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if statusOutput != "table" && statusOutput != "json" && statusOutput != "yaml" {
+		return fmt.Errorf("invalid --output %q (must be table, json, or yaml)", statusOutput)
+	}
+
+	distros, err := wsl.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+	if len(args) > 0 {
+		var selected []wsl.InstalledDistro
+		for _, d := range distros {
+			if d.Name == args[0] {
+				selected = append(selected, d)
+			}
+		}
+		if len(selected) == 0 {
+			return distroNotFoundError(args[0])
+		}
+		distros = selected
+	}
+
+	statuses := make([]distroStatus, 0, len(distros))
+	for _, d := range distros {
+		statuses = append(statuses, collectStatus(d))
+	}
+
+	switch statusOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(statuses); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No WSL distributions installed.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATE\tIP\tMEMORY\tPROCESSES\tLAST STARTED")
+	for _, s := range statuses {
+		ip, memory, procs, started := "-", "-", "-", "-"
+		if s.Runtime != nil {
+			if s.Runtime.IPv4 != "" {
+				ip = s.Runtime.IPv4
+			}
+			memory = fmt.Sprintf("%d / %d MB", s.Runtime.MemoryUsedMB, s.Runtime.MemoryTotalMB)
+			procs = fmt.Sprintf("%d", s.Runtime.ProcessCount)
+		}
+		if s.LastStartedAt != nil {
+			started = s.LastStartedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.State, ip, memory, procs, started)
+	}
+	w.Flush()
+
+	for _, s := range statuses {
+		if s.Error != "" {
+			fmt.Fprintf(os.Stderr, "⚠ %s: %s\n", s.Name, s.Error)
+		}
+	}
+	return nil
+}
+
+// collectStatus gathers the status of d without starting it
+func collectStatus(d wsl.InstalledDistro) distroStatus {
+	s := distroStatus{InstalledDistro: d}
+
+	if strings.EqualFold(d.State, "Running") {
+		rt, err := wsl.GetRuntimeStatus(d.Name)
+		if err != nil {
+			s.Error = strings.SplitN(err.Error(), "\n", 2)[0]
+		} else {
+			s.Runtime = rt
+		}
+		return s
+	}
+
+	if path, err := wsl.GetInstallPath(d.Name); err == nil {
+		if m, err := metadata.Read(path); err == nil && !m.LastStartedAt.IsZero() {
+			started := m.LastStartedAt
+			s.LastStartedAt = &started
+		}
+	}
+	return s
+}
+
+// recordDistroStart remembers when autowsl last started a distribution. It is
+// best effort: failing to write metadata never blocks entering a distro.
+func recordDistroStart(distroName string) {
+	if dryRun {
+		return
+	}
+	if path, err := wsl.GetInstallPath(distroName); err == nil {
+		_ = metadata.RecordStart(path)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var updateSecurityOnly bool
+
+var updateCmd = &cobra.Command{
+	Use:   "update <distro-name> [package...]",
+	Short: "Upgrade the packages inside a distribution",
+	Long: `Upgrade the packages inside a distribution with its native package manager
+(apt, dnf, yum, zypper, pacman, or apk). Pass package names to upgrade only those.
+
+Examples:
+  # Upgrade everything
+  autowsl update ubuntu-2204-lts
+
+  # Upgrade selected packages only
+  autowsl update ubuntu-2204-lts git curl
+
+  # Apply security updates only
+  autowsl update fedora-40 --security-only`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runUpdate,
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&updateSecurityOnly, "security-only", false, "Apply security updates only (apt, dnf, yum, zypper)")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	distroName, packages := args[0], args[1:]
+
+	exists, err := wsl.IsDistroInstalled(distroName)
+	if err != nil {
+		return fmt.Errorf("failed to check distribution: %w", err)
+	}
+	if !exists && !dryRun {
+		return distroNotFoundError(distroName)
+	}
+
+	switch {
+	case updateSecurityOnly:
+		fmt.Printf("\nApplying security updates in '%s'...\n", distroName)
+	case len(packages) > 0:
+		fmt.Printf("\nUpgrading %s in '%s'...\n", strings.Join(packages, ", "), distroName)
+	default:
+		fmt.Printf("\nUpgrading all packages in '%s'...\n", distroName)
+	}
+	fmt.Println(strings.Repeat("-", 60))
+
+	if err := ansible.UpdatePackages(ansible.UpdateOptions{
+		DistroName:   distroName,
+		Packages:     packages,
+		SecurityOnly: updateSecurityOnly,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("✓ '%s' is up to date\n", distroName)
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/playbooks"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <playbook>...",
+	Short: "Check the structure of playbooks without running them",
+	Long: `Check that playbooks are well-formed before provisioning with them: each file
+must be valid YAML containing a list of plays, every play needs a hosts key,
+and tasks must be lists. Ansible is not needed; use 'provision --syntax-check'
+for a full Ansible syntax check inside a distribution.
+
+Playbooks are given as files or aliases from the playbooks directory.
+
+Examples:
+  autowsl validate ./setup.yml
+
+  autowsl validate dev-tools ./site.yml`,
+	Args:              cobra.MinimumNArgs(1),
+	SilenceUsage:      true,
+	RunE:              runValidate,
+	ValidArgsFunction: completePlaybookAliases,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	cwd, _ := os.Getwd()
+	resolver := playbooks.NewResolver(tempDirPath(), cwd)
+	resolver.AliasDir = playbooksDirPath()
+
+	failed := 0
+	for _, input := range args {
+		paths, err := resolver.Resolve(input)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", input, err)
+			failed++
+			continue
+		}
+		for _, path := range paths {
+			if err := playbooks.ValidatePlaybook(path); err != nil {
+				fmt.Printf("✗ %v\n", err)
+				failed++
+				continue
+			}
+			fmt.Printf("✓ %s\n", path)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d playbook(s) failed validation", failed)
+	}
+	return nil
+}
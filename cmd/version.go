@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var versionExtended bool
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the autowsl version",
+	Long: `Print the autowsl version. With --extended the Go runtime, Windows build, and the
+versions of WSL, its kernel and WSLg are printed as well, which is useful
+for bug reports.
+
+Examples:
+  autowsl version
+  autowsl version --extended`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionExtended, "extended", false, "Also print Windows and WSL component versions")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Printf("autowsl %s\n", Version)
+	if !versionExtended {
+		return nil
+	}
+
+	fmt.Printf("Go:             %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if build, err := system.GetWindowsBuildNumber(); err == nil {
+		fmt.Printf("Windows build:  %d\n", build)
+	} else {
+		fmt.Printf("Windows build:  unknown (%v)\n", err)
+	}
+
+	info, err := wsl.GetWSLVersion()
+	if err != nil {
+		fmt.Println("WSL:            unavailable (wsl --version and wsl --status failed)")
+		return nil
+	}
+	fmt.Printf("WSL:            %s\n", orDash(info.WSL))
+	fmt.Printf("Kernel:         %s\n", orDash(info.Kernel))
+	fmt.Printf("WSLg:           %s\n", orDash(info.WSLg))
+	if info.SystemDistro != "" {
+		fmt.Printf("System distro:  %s\n", info.SystemDistro)
+	}
+	return nil
+}
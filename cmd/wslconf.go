@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+var (
+	wslConfSystemd           bool
+	wslConfHostname          string
+	wslConfDefaultUser       string
+	wslConfAutomount         bool
+	wslConfAppendWindowsPath bool
+	wslConfSet               []string
+)
+
+// wslConfFlagKeys maps the convenience flags of 'config wsl' to wsl.conf keys
+var wslConfFlagKeys = [][2]string{
+	{"systemd", "boot.systemd"},
+	{"hostname", "network.hostname"},
+	{"default-user", "user.default"},
+	{"automount", "automount.enabled"},
+	{"append-windows-path", "interop.appendWindowsPath"},
+}
+
+var configWSLCmd = &cobra.Command{
+	Use:   "wsl [distro-name]",
+	Short: "Show or change /etc/wsl.conf inside a distribution",
+	Long: `Show or change /etc/wsl.conf inside a distribution. Without flags the current
+settings are printed. Keys not covered by a flag can be set with --set section.key=value.
+Changes take effect after the distribution is restarted (wsl --terminate <name>).
+
+Examples:
+  # Show the current wsl.conf
+  autowsl config wsl ubuntu-2204
+
+  # Enable systemd
+  autowsl config wsl ubuntu-2204 --systemd true
+
+  # Set the hostname and the default user
+  autowsl config wsl ubuntu-2204 --hostname devbox --default-user dev
+
+  # Set any other key
+  autowsl config wsl ubuntu-2204 --set network.generateResolvConf=false`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigWSL,
+}
+
+func init() {
+	configCmd.AddCommand(configWSLCmd)
+	configWSLCmd.Flags().BoolVar(&wslConfSystemd, "systemd", false, "Enable or disable systemd ([boot] systemd)")
+	configWSLCmd.Flags().StringVar(&wslConfHostname, "hostname", "", "Hostname of the distribution ([network] hostname)")
+	configWSLCmd.Flags().StringVar(&wslConfDefaultUser, "default-user", "", "User to log in as ([user] default)")
+	configWSLCmd.Flags().BoolVar(&wslConfAutomount, "automount", true, "Mount Windows drives under /mnt ([automount] enabled)")
+	configWSLCmd.Flags().BoolVar(&wslConfAppendWindowsPath, "append-windows-path", true, "Add Windows PATH entries to $PATH ([interop] appendWindowsPath)")
+	configWSLCmd.Flags().StringArrayVar(&wslConfSet, "set", nil, "Set any key as section.key=value (repeatable)")
+	configWSLCmd.ValidArgsFunction = completeInstalledDistros
+}
+
+func runConfigWSL(cmd *cobra.Command, args []string) error {
+	var distroName string
+	if len(args) > 0 {
+		distroName = args[0]
+		exists, err := wsl.IsDistroInstalled(distroName)
+		if err != nil {
+			return fmt.Errorf("failed to check distribution: %w", err)
+		}
+		if !exists && !dryRun {
+			return distroNotFoundError(distroName)
+		}
+	} else {
+		var err error
+		distroName, err = selectInstalledDistroInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	conf, err := wsl.ReadConf(distroName)
+	if err != nil {
+		return err
+	}
+
+	// Collect the requested changes, flags first, then --set in order
+	var changes [][2]string
+	for _, fk := range wslConfFlagKeys {
+		if f := cmd.Flags().Lookup(fk[0]); f != nil && f.Changed {
+			changes = append(changes, [2]string{fk[1], f.Value.String()})
+		}
+	}
+	for _, kv := range wslConfSet {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid --set %q (expected section.key=value)", kv)
+		}
+		changes = append(changes, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("/etc/wsl.conf in '%s':\n\n", distroName)
+		if text := conf.String(); text != "" {
+			fmt.Print(text)
+		} else {
+			fmt.Println("(empty)")
+		}
+		return nil
+	}
+
+	for _, change := range changes {
+		if err := conf.Set(change[0], change[1]); err != nil {
+			return err
+		}
+		fmt.Printf("  %s = %s\n", change[0], change[1])
+	}
+
+	if err := wsl.WriteConf(distroName, conf); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✓ Updated /etc/wsl.conf in '%s'\n", distroName)
+	fmt.Printf("Restart the distribution to apply: wsl --terminate %s\n", distroName)
+	return nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/yuanjua/autowsl/internal/system"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wslconfig"
+)
+
+var wslconfigCmd = &cobra.Command{
+	Use:   "wslconfig",
+	Short: "Manage global WSL2 settings in .wslconfig",
+	Long: `Manage %USERPROFILE%\.wslconfig, which sets the memory, processors, swap, and
+networking of the WSL2 virtual machine for all distributions. The [wsl2] and
+[experimental] sections are supported. Changes take effect after WSL is shut
+down with 'wsl --shutdown'.
+
+Examples:
+  # Show the current settings
+  autowsl wslconfig show
+
+  # Limit WSL2 to 8 GB of memory and 4 processors
+  autowsl wslconfig set wsl2.memory 8GB
+  autowsl wslconfig set wsl2.processors 4
+
+  # Enable an experimental feature
+  autowsl wslconfig set experimental.autoMemoryReclaim gradual
+
+  # Remove .wslconfig and go back to the WSL defaults
+  autowsl wslconfig reset`,
+}
+
+var wslconfigShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the current .wslconfig settings",
+	Args:  cobra.NoArgs,
+	RunE:  runWSLConfigShow,
+}
+
+var wslconfigSetCmd = &cobra.Command{
+	Use:   "set <section.key> <value>",
+	Short: "Set a value in .wslconfig",
+	Long: `Set a value in .wslconfig. Known keys are validated: sizes such as memory and
+swap accept values like 8GB or 4096MB, and booleans accept true or false.
+
+Examples:
+  autowsl wslconfig set wsl2.memory 4096MB
+  autowsl wslconfig set wsl2.localhostForwarding true`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWSLConfigSet,
+}
+
+var wslconfigResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Remove .wslconfig so WSL uses its defaults",
+	Args:  cobra.NoArgs,
+	RunE:  runWSLConfigReset,
+}
+
+func init() {
+	rootCmd.AddCommand(wslconfigCmd)
+	wslconfigCmd.AddCommand(wslconfigShowCmd)
+	wslconfigCmd.AddCommand(wslconfigSetCmd)
+	wslconfigCmd.AddCommand(wslconfigResetCmd)
+}
+
+func runWSLConfigShow(cmd *cobra.Command, args []string) error {
+	path, err := wslconfig.Path()
+	if err != nil {
+		return err
+	}
+	conf, err := wslconfig.LoadFrom(path)
+	if err != nil {
+		return err
+	}
+
+	entries := conf.Entries()
+	fmt.Printf("%s\n\n", path)
+	if len(entries) == 0 {
+		fmt.Println("No settings found; WSL uses its defaults.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SECTION\tKEY\tVALUE")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Section, e.Key, e.Value)
+	}
+	return w.Flush()
+}
+
+func runWSLConfigSet(cmd *cobra.Command, args []string) error {
+	path, err := wslconfig.Path()
+	if err != nil {
+		return err
+	}
+	conf, err := wslconfig.LoadFrom(path)
+	if err != nil {
+		return err
+	}
+	if err := conf.Set(args[0], args[1]); err != nil {
+		return err
+	}
+
+	value, _ := conf.Get(args[0])
+	if feature := wslConfigFeature(args[0], value); feature != "" {
+		if err := system.CheckFeature(feature); err != nil {
+			return fmt.Errorf("cannot set %s = %s: %w", args[0], value, err)
+		}
+	}
+	if dryRun {
+		fmt.Printf("[dry-run] would set %s = %s in %s\n", args[0], value, path)
+		return nil
+	}
+	if err := conf.SaveTo(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Set %s = %s in %s\n", args[0], value, path)
+	printShutdownReminder()
+	return nil
+}
+
+// wslConfigFeature returns the Windows feature a setting needs, or "" if it
+// works on every build that runs WSL 2
+func wslConfigFeature(key, value string) string {
+	switch {
+	case strings.EqualFold(key, "wsl2.networkingMode") && strings.EqualFold(value, "mirrored"):
+		return system.FeatureMirroredNetworking
+	case strings.EqualFold(key, "experimental.sparseVhd") && strings.EqualFold(value, "true"):
+		return system.FeatureSparseVHD
+	}
+	return ""
+}
+
+func runWSLConfigReset(cmd *cobra.Command, args []string) error {
+	path, err := wslconfig.Path()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("No .wslconfig at %s; WSL already uses its defaults.\n", path)
+		return nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Remove %s and restore the WSL defaults", path),
+		IsConfirm: true,
+	}
+	if _, err := ui.Prompt(prompt); err != nil {
+		fmt.Println("Reset cancelled")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] would remove %s\n", path)
+		return nil
+	}
+	if _, err := wslconfig.Reset(); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Removed %s\n", path)
+	printShutdownReminder()
+	return nil
+}
+
+// printShutdownReminder tells the user how to apply .wslconfig changes
+func printShutdownReminder() {
+	fmt.Println("→ Run 'wsl.exe --shutdown' for the change to take effect (this stops all running distributions).")
+}
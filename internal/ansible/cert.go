@@ -0,0 +1,124 @@
+package ansible
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuanjua/autowsl/internal/runner"
+)
+
+// certFilePattern matches characters that are not safe in a certificate file name
+var certFilePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// CertificateTarget returns the trust anchor directory and the command that
+// rebuilds the trust store for the named package manager.
+func CertificateTarget(pmName string) (dir, updateCmd string, err error) {
+	for _, pm := range supportedPMs {
+		if pm.name == pmName {
+			return pm.caCertDir, pm.caUpdateCmd, nil
+		}
+	}
+	return "", "", fmt.Errorf("unsupported package manager '%s'", pmName)
+}
+
+// CertificatePEM reads a PEM or DER encoded certificate and returns it as PEM.
+// Every certificate in a PEM bundle must parse.
+func CertificatePEM(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		cert, err := x509.ParseCertificate(data)
+		if err != nil {
+			return nil, fmt.Errorf("not a PEM or DER certificate: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), nil
+	}
+
+	var out bytes.Buffer
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("invalid certificate in PEM data: %w", err)
+		}
+		_ = pem.Encode(&out, block)
+	}
+	if out.Len() == 0 {
+		return nil, fmt.Errorf("no CERTIFICATE block found in PEM data")
+	}
+	return out.Bytes(), nil
+}
+
+// ImportCertificate adds a CA certificate from the Windows filesystem to the
+// trust store of a distribution. Returns the path it was installed to.
+func ImportCertificate(distroName, certPath string) (string, error) {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read certificate '%s': %w", certPath, err)
+	}
+	pemData, err := CertificatePEM(data)
+	if err != nil {
+		return "", fmt.Errorf("'%s': %w", certPath, err)
+	}
+
+	pm, err := detectPackageManager(distroName)
+	if err != nil {
+		return "", err
+	}
+
+	// update-ca-certificates only picks up files ending in .crt
+	base := strings.TrimSuffix(filepath.Base(certPath), filepath.Ext(certPath))
+	wslPath := pm.caCertDir + "/autowsl-" + certFilePattern.ReplaceAllString(base, "_") + ".crt"
+
+	installCmd := fmt.Sprintf("sudo mkdir -p '%s' && sudo tee '%s' > /dev/null && sudo chmod 644 '%s' && sudo %s",
+		pm.caCertDir, wslPath, wslPath, pm.caUpdateCmd)
+	if dryRun {
+		fmt.Printf("[dry-run] %s < %s\n", runner.FormatCommand(wslExe, "-d", distroName, "sh", "-c", installCmd), certPath)
+		return wslPath, nil
+	}
+
+	if output, err := wslCombinedOutput(distroName, installCmd, pemData); err != nil {
+		return "", fmt.Errorf("failed to install certificate in '%s': %s: %w", distroName, strings.TrimSpace(output), err)
+	}
+	return wslPath, nil
+}
+
+// ListCertificates returns the custom CA certificates in the trust anchor
+// directory of a distribution, sorted by path.
+func ListCertificates(distroName string) ([]string, error) {
+	pm, err := detectPackageManager(distroName)
+	if err != nil {
+		return nil, err
+	}
+
+	listCmd := fmt.Sprintf("find '%s' -type f 2>/dev/null || true", pm.caCertDir)
+	if dryRun {
+		return nil, runWslCommand(distroName, listCmd)
+	}
+
+	var out bytes.Buffer
+	if err := runWslCommandTo(distroName, listCmd, &out); err != nil {
+		return nil, fmt.Errorf("failed to list certificates in '%s': %w", distroName, err)
+	}
+
+	var certs []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			certs = append(certs, line)
+		}
+	}
+	sort.Strings(certs)
+	return certs, nil
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package ansible
+
+import "os"
+
+// supportsColor reports whether f is a terminal that renders ANSI colors
+func supportsColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
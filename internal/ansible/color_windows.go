@@ -0,0 +1,30 @@
+//go:build windows
+
+package ansible
+
+import (
+	"os"
+	"syscall"
+)
+
+// enableVirtualTerminalProcessing makes the console interpret ANSI escapes
+const enableVirtualTerminalProcessing = 0x0004
+
+// supportsColor reports whether f is a console that renders ANSI colors.
+// Virtual terminal processing is switched on if needed.
+func supportsColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	handle := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+	ret, _, _ := proc.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	return ret != 0
+}
@@ -1,12 +1,22 @@
 package ansible
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/runner"
 )
 
 // packageManager contains information about available package managers.
@@ -15,6 +25,12 @@ type packageManager struct {
 	checkCmd               string
 	installCmd             string // %s will be replaced with the package name
 	updateCmd              string
+	upgradeCmd             string   // Upgrades every installed package
+	upgradePackagesCmd     string   // Upgrades only the listed packages; %s is replaced with them
+	securityUpgradeCmd     string   // Applies security updates only
+	caCertDir              string   // Directory for extra trusted CA certificates
+	caUpdateCmd            string   // Rebuilds the trust store after adding certificates
+	pipPackage             string   // Package that provides pip3
 	preInstallSteps        []string // Commands to run before installing ANY package
 	ansiblePostInstallCmds []string // Specific commands to run AFTER installing Ansible
 	isAnsibleCore          bool     // True if the package manager installs ansible-core instead of ansible
@@ -22,10 +38,25 @@ type packageManager struct {
 }
 
 var (
+	// dryRun prints WSL commands instead of executing them.
+	dryRun bool
+
+	// wslExe is the wsl.exe binary WSL commands are run with.
+	wslExe = runner.DefaultWSLPath
+
+	// wslRunner runs WSL commands in place of os/exec when set (see SetRunner).
+	wslRunner runner.Runner
+
+	// commandTimeout limits each command run in a distribution; 0 means no limit.
+	commandTimeout time.Duration
+
 	// memoizedPMs stores the detected package manager for each distro to avoid repeated detection.
 	memoizedPMs = make(map[string]*packageManager)
 	pmMutex     sync.Mutex
 
+	// memoizedAnsibleVersions stores the ansible-playbook version of each distro; guarded by pmMutex.
+	memoizedAnsibleVersions = make(map[string]string)
+
 	// supportedPMs is a list of package managers the tool knows how to use.
 	supportedPMs = []packageManager{
 		{
@@ -37,22 +68,41 @@ var (
 				// This is required for Ansible's `apt` module to function correctly.
 				"sudo apt-get install -y python3-apt",
 			},
-			description: "Ubuntu/Debian/Kali",
+			upgradeCmd:         "sudo apt-get update && sudo apt-get upgrade -y",
+			upgradePackagesCmd: "sudo apt-get update && sudo apt-get install -y --only-upgrade %s",
+			// apt has no security-only switch; unattended-upgrades applies only the security pocket by default
+			securityUpgradeCmd: "sudo apt-get update && sudo apt-get install -y unattended-upgrades && sudo unattended-upgrade -v",
+			caCertDir:          "/usr/local/share/ca-certificates",
+			caUpdateCmd:        "update-ca-certificates",
+			pipPackage:         "python3-pip",
+			description:        "Ubuntu/Debian/Kali",
 		},
 		{
-			name:            "dnf",
-			checkCmd:        "command -v dnf",
-			installCmd:      "sudo dnf install -y %s",
-			preInstallSteps: []string{"sudo dnf install -y oracle-epel-release-el9 || true"}, // For Oracle/RHEL to get ansible
-			isAnsibleCore:   true,
-			description:     "Fedora/Oracle Linux/RHEL 8+",
+			name:               "dnf",
+			checkCmd:           "command -v dnf",
+			installCmd:         "sudo dnf install -y %s",
+			preInstallSteps:    []string{"sudo dnf install -y oracle-epel-release-el9 || true"}, // For Oracle/RHEL to get ansible
+			isAnsibleCore:      true,
+			upgradeCmd:         "sudo dnf upgrade -y",
+			upgradePackagesCmd: "sudo dnf upgrade -y %s",
+			securityUpgradeCmd: "sudo dnf upgrade -y --security",
+			caCertDir:          "/etc/pki/ca-trust/source/anchors",
+			caUpdateCmd:        "update-ca-trust",
+			pipPackage:         "python3-pip",
+			description:        "Fedora/Oracle Linux/RHEL 8+",
 		},
 		{
-			name:            "yum",
-			checkCmd:        "command -v yum",
-			installCmd:      "sudo yum install -y %s",
-			preInstallSteps: []string{"sudo yum install -y epel-release || true"},
-			description:     "RHEL/CentOS/Oracle Linux 7",
+			name:               "yum",
+			checkCmd:           "command -v yum",
+			installCmd:         "sudo yum install -y %s",
+			preInstallSteps:    []string{"sudo yum install -y epel-release || true"},
+			upgradeCmd:         "sudo yum update -y",
+			upgradePackagesCmd: "sudo yum update -y %s",
+			securityUpgradeCmd: "sudo yum update -y --security",
+			caCertDir:          "/etc/pki/ca-trust/source/anchors",
+			caUpdateCmd:        "update-ca-trust",
+			pipPackage:         "python3-pip",
+			description:        "RHEL/CentOS/Oracle Linux 7",
 		},
 		{
 			name:       "zypper",
@@ -64,19 +114,35 @@ var (
 				// This is required for Ansible's `zypper` module (install without sudo for user).
 				"ansible-galaxy collection install community.general --force",
 			},
-			description: "openSUSE",
+			upgradeCmd:         "sudo zypper --non-interactive update -y",
+			upgradePackagesCmd: "sudo zypper --non-interactive update -y %s",
+			securityUpgradeCmd: "sudo zypper --non-interactive patch --category security",
+			caCertDir:          "/etc/pki/trust/anchors",
+			caUpdateCmd:        "update-ca-certificates",
+			pipPackage:         "python3-pip",
+			description:        "openSUSE",
 		},
 		{
-			name:        "pacman",
-			checkCmd:    "command -v pacman",
-			installCmd:  "sudo pacman -S --noconfirm %s",
+			name:       "pacman",
+			checkCmd:   "command -v pacman",
+			installCmd: "sudo pacman -S --noconfirm %s",
+			// Arch doesn't support partial upgrades or security-only updates
+			upgradeCmd:  "sudo pacman -Syu --noconfirm",
+			caCertDir:   "/etc/ca-certificates/trust-source/anchors",
+			caUpdateCmd: "trust extract-compat",
+			pipPackage:  "python-pip",
 			description: "Arch Linux",
 		},
 		{
-			name:        "apk",
-			checkCmd:    "command -v apk",
-			installCmd:  "sudo apk add %s",
-			description: "Alpine Linux",
+			name:               "apk",
+			checkCmd:           "command -v apk",
+			installCmd:         "sudo apk add %s",
+			upgradeCmd:         "sudo apk update && sudo apk upgrade",
+			upgradePackagesCmd: "sudo apk update && sudo apk upgrade %s",
+			caCertDir:          "/usr/local/share/ca-certificates",
+			caUpdateCmd:        "update-ca-certificates",
+			pipPackage:         "py3-pip",
+			description:        "Alpine Linux",
 		},
 	}
 )
@@ -86,51 +152,245 @@ type PlaybookOptions struct {
 	DistroName   string
 	PlaybookPath string
 	Tags         []string
-	Verbose      bool
-	ExtraVars    map[string]string
+	SkipTags     []string
+	TagsFile     string // YAML file whose tags are merged into Tags
+	SkipTagsFile string // YAML file whose tags are merged into SkipTags
+	Limit        string // Host pattern for --limit; only localhost or all match in local mode
+	Forks        int    // --forks; 0 keeps the Ansible default. Local mode has a single host, so it rarely matters
+
+	Connection    string // Ansible connection type; empty means local, which configures the distribution itself
+	InventoryPath string // Windows path to an inventory file, required for connections other than local
+
+	RequiredAnsibleVersion string // Minimum ansible-playbook version, e.g. 2.14; empty skips the check
+	ForceReinstallAnsible  bool   // Install Ansible again even if it is present, e.g. to upgrade it
+	Verbose                bool
+	ExtraVars              map[string]any // Passed as one JSON --extra-vars argument
+	ExtraVarsFile          string         // Windows path to a JSON or YAML file passed as --extra-vars @file
+	AnsibleCfg             string         // Windows path to an ansible.cfg used through ANSIBLE_CONFIG
+
+	VaultPasswordFile string // Windows path to a vault password file
+	AskVaultPass      bool   // Prompt for the vault password (requires a TTY)
+	VaultIDs          []VaultID
+
+	SkipGalaxyInstall     bool   // Don't install collections from a requirements.yml next to the playbook
+	GalaxyCollectionsFile string // Windows path to a requirements file installed with ansible-galaxy collection install
+	GalaxyRolesFile       string // Windows path to a requirements file installed with ansible-galaxy role install
+
+	BecomePasswordFile string // Windows path to a file holding the sudo password
+
+	SyntaxCheck bool // Only parse the playbook with --syntax-check; no tasks run
+	CheckMode   bool // Simulate changes with --check without applying them
+	DiffMode    bool // Show file changes with --diff
+
+	PrePlaybookCmds  []string // Shell commands run in the distribution before the playbook; a failure aborts
+	PostPlaybookCmds []string // Shell commands run after the playbook; failures only warn unless StrictPostCmds
+	StrictPostCmds   bool     // Fail the run when a post-playbook command fails
+
+	OutputLog    string    // File that also receives the playbook output; appended to if it exists
+	Output       io.Writer // Receives all output instead of the terminal; stdin is detached
+	AnsibleReady bool      // Skip the Ansible install check because the caller already ran it
+}
+
+// SetDryRun enables or disables dry-run mode for playbook execution.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// SetWSLPath sets the wsl.exe binary used to run commands in distributions.
+// An empty path uses wsl.exe from PATH.
+func SetWSLPath(path string) {
+	if path == "" {
+		path = runner.DefaultWSLPath
+	}
+	wslExe = path
+}
+
+// SetRunner makes WSL commands go through r instead of being run directly,
+// e.g. with a mock runner in tests. Output is no longer streamed. nil restores
+// direct execution.
+func SetRunner(r runner.Runner) {
+	wslRunner = r
+}
+
+// SetTimeout sets how long a command run in a distribution may take before it
+// is killed. 0 means no limit.
+func SetTimeout(d time.Duration) {
+	commandTimeout = d
+}
+
+// wslCommand builds a POSIX sh command for the distribution that is killed
+// once the timeout set with SetTimeout expires. cancel must be called when done.
+func wslCommand(distroName, command string) (cmd *exec.Cmd, ctx context.Context, cancel context.CancelFunc) {
+	if commandTimeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), commandTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	return exec.CommandContext(ctx, wslExe, "-d", distroName, "sh", "-c", command), ctx, cancel
+}
+
+// wslSucceeds reports whether command exits successfully in the distribution
+func wslSucceeds(distroName, command string) bool {
+	if wslRunner != nil {
+		_, _, err := wslRunner.Run(wslExe, "-d", distroName, "sh", "-c", command)
+		return err == nil
+	}
+	cmd, _, cancel := wslCommand(distroName, command)
+	defer cancel()
+	return cmd.Run() == nil
+}
+
+// wslCombinedOutput runs command in the distribution with stdin as its input
+// and returns stdout and stderr combined
+func wslCombinedOutput(distroName, command string, stdin []byte) (string, error) {
+	if wslRunner != nil {
+		stdout, stderr, err := wslRunner.RunWithInput(wslExe, string(stdin), "-d", distroName, "sh", "-c", command)
+		return stdout + stderr, err
+	}
+	cmd, ctx, cancel := wslCommand(distroName, command)
+	defer cancel()
+	cmd.Stdin = bytes.NewReader(stdin)
+	output, err := cmd.CombinedOutput()
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return string(output), fmt.Errorf("command '%s' timed out after %s: %w", command, commandTimeout, context.DeadlineExceeded)
+	}
+	return string(output), err
 }
 
 // runWslCommand executes a command within a specified WSL distribution and streams its output.
 func runWslCommand(distroName, command string) error {
+	return runWslCommandTo(distroName, command, nil)
+}
+
+// runWslCommandTo is runWslCommand with stdout and stderr sent to out. A nil
+// out attaches the command to the terminal, including stdin.
+func runWslCommandTo(distroName, command string, out io.Writer) error {
+	return runWslCommandLogged(distroName, command, nil, out, nil)
+}
+
+// runWslCommandLogged is runWslCommandTo that sets envVars for the command and
+// also copies stdout and stderr to log when it is not nil.
+func runWslCommandLogged(distroName, command string, envVars map[string]string, out, log io.Writer) error {
+	command = PrefixEnv(envVars, command)
+	if dryRun {
+		if out == nil {
+			out = os.Stdout
+		}
+		fmt.Fprintln(out, "[dry-run] "+runner.FormatCommand(wslExe, "-d", distroName, "sh", "-c", command))
+		return nil
+	}
+
+	if wslRunner != nil {
+		stdout, stderr, err := wslRunner.Run(wslExe, "-d", distroName, "sh", "-c", command)
+		if out == nil {
+			out = os.Stdout
+		}
+		if log != nil {
+			out = io.MultiWriter(out, log)
+		}
+		fmt.Fprint(out, stdout+stderr)
+		if err != nil {
+			return fmt.Errorf("command '%s' failed: %w", command, err)
+		}
+		return nil
+	}
+
 	// Use POSIX sh to avoid reliance on bash (e.g., Alpine images)
-	cmd := exec.Command("wsl.exe", "-d", distroName, "sh", "-c", command)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+	cmd, ctx, cancel := wslCommand(distroName, command)
+	defer cancel()
+	stdout, stderr := out, out
+	if out == nil {
+		stdout, stderr = os.Stdout, os.Stderr
+		cmd.Stdin = os.Stdin
+	}
+	if log != nil {
+		stdout = io.MultiWriter(stdout, log)
+		stderr = io.MultiWriter(stderr, log)
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("command '%s' timed out after %s: %w", command, commandTimeout, context.DeadlineExceeded)
+		}
 		return fmt.Errorf("command '%s' failed: %w", command, err)
 	}
 	return nil
 }
 
+// PrefixEnv prepends KEY='value' assignments for envVars, sorted by key, to a
+// shell command
+func PrefixEnv(envVars map[string]string, command string) string {
+	if len(envVars) == 0 {
+		return command
+	}
+	keys := make([]string, 0, len(envVars))
+	for k := range envVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s ", k, shellQuote(envVars[k]))
+	}
+	return b.String() + command
+}
+
+// shellQuote wraps s in single quotes for a POSIX shell, escaping any single
+// quotes inside it
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // detectPackageManager identifies the package manager used by the distribution.
 func detectPackageManager(distroName string) (*packageManager, error) {
+	pm, detected, err := findPackageManager(distroName)
+	if err != nil {
+		return nil, err
+	}
+	if detected {
+		fmt.Printf("Detected package manager: %s (%s)\n", pm.name, pm.description)
+	}
+	return pm, nil
+}
+
+// DetectPackageManager returns the name of the package manager used by the
+// distribution (apt, dnf, yum, zypper, pacman, or apk) without printing anything.
+func DetectPackageManager(distroName string) (string, error) {
+	pm, _, err := findPackageManager(distroName)
+	if err != nil {
+		return "", err
+	}
+	return pm.name, nil
+}
+
+// findPackageManager looks up the memoized package manager or probes the
+// distribution for one. detected is true when it was probed by this call.
+func findPackageManager(distroName string) (pm *packageManager, detected bool, err error) {
 	pmMutex.Lock()
 	defer pmMutex.Unlock()
 
 	if pm, ok := memoizedPMs[distroName]; ok {
-		return pm, nil
+		return pm, false, nil
 	}
 
 	for i := range supportedPMs {
 		pm := &supportedPMs[i]
 		// Use sh for robust availability across distros
-		checkPMCmd := exec.Command("wsl.exe", "-d", distroName, "sh", "-c", pm.checkCmd)
-		if checkPMCmd.Run() == nil {
-			fmt.Printf("Detected package manager: %s (%s)\n", pm.name, pm.description)
+		if wslSucceeds(distroName, pm.checkCmd) {
 			memoizedPMs[distroName] = pm
-			return pm, nil
+			return pm, true, nil
 		}
 	}
 
-	return nil, fmt.Errorf("could not detect a supported package manager in distribution '%s'", distroName)
+	return nil, false, fmt.Errorf("could not detect a supported package manager in distribution '%s'", distroName)
 }
 
 // fixKaliRepositories handles the specific GPG key issue in new Kali Linux instances.
 func fixKaliRepositories(distroName string) error {
-	checkKaliCmd := exec.Command("wsl.exe", "-d", distroName, "sh", "-c", "grep -i kali /etc/os-release")
-	if checkKaliCmd.Run() != nil {
+	if !wslSucceeds(distroName, "grep -i kali /etc/os-release") {
 		return nil // Not a Kali distribution, nothing to do.
 	}
 
@@ -201,6 +461,13 @@ func InstallPackage(distroName, packageName string) error {
 		return err // The main install failed, so abort.
 	}
 
+	if packageName == "ansible" {
+		// The Ansible version may have changed
+		pmMutex.Lock()
+		delete(memoizedAnsibleVersions, distroName)
+		pmMutex.Unlock()
+	}
+
 	// Run post-installation steps specifically for Ansible, if defined.
 	if packageName == "ansible" && len(pm.ansiblePostInstallCmds) > 0 {
 		fmt.Printf("Running Ansible post-installation steps for %s...\n", pm.name)
@@ -216,10 +483,19 @@ func InstallPackage(distroName, packageName string) error {
 }
 
 // ensurePackage checks if a command exists and installs the corresponding package if it doesn't.
-func ensurePackage(distroName, commandName, packageName string) error {
+// With force the package is installed even if the command exists.
+func ensurePackage(distroName, commandName, packageName string, force bool) error {
+	if dryRun {
+		if force {
+			fmt.Printf("[dry-run] would reinstall package '%s' in '%s'\n", packageName, distroName)
+			return nil
+		}
+		fmt.Printf("[dry-run] would ensure package '%s' is installed in '%s'\n", packageName, distroName)
+		return nil
+	}
+
 	// Prefer POSIX 'command -v' over external 'which'
-	checkCmd := exec.Command("wsl.exe", "-d", distroName, "sh", "-c", "command -v "+commandName)
-	alreadyInstalled := checkCmd.Run() == nil
+	alreadyInstalled := !force && wslSucceeds(distroName, "command -v "+commandName)
 
 	if alreadyInstalled {
 		fmt.Printf("Package '%s' is already installed.\n", packageName)
@@ -230,9 +506,7 @@ func ensurePackage(distroName, commandName, packageName string) error {
 			if err == nil && len(pm.ansiblePostInstallCmds) > 0 {
 				// Check if community.general collection is installed (for SUSE)
 				if pm.name == "zypper" {
-					checkCollection := exec.Command("wsl.exe", "-d", distroName, "sh", "-c",
-						"ansible-galaxy collection list | grep -q community.general")
-					if checkCollection.Run() != nil {
+					if !wslSucceeds(distroName, "ansible-galaxy collection list | grep -q community.general") {
 						fmt.Println("Ansible collection 'community.general' not found, installing...")
 						for _, step := range pm.ansiblePostInstallCmds {
 							if err := runWslCommand(distroName, step); err != nil {
@@ -259,8 +533,7 @@ func ensurePackage(distroName, commandName, packageName string) error {
 		}
 
 		// Check if it's NOT Kali so we can run a standard update for Debian/Ubuntu.
-		isKaliCmd := exec.Command("wsl.exe", "-d", distroName, "sh", "-c", "grep -i kali /etc/os-release")
-		if isKaliCmd.Run() != nil {
+		if !wslSucceeds(distroName, "grep -i kali /etc/os-release") {
 			// It wasn't Kali, so no update has been run yet.
 			fmt.Println("Running apt-get update...")
 			if err := runWslCommand(distroName, pm.updateCmd); err != nil {
@@ -287,89 +560,623 @@ func ExecutePlaybook(opts PlaybookOptions) error {
 		return fmt.Errorf("playbook file '%s' not found: %w", opts.PlaybookPath, err)
 	}
 
-	fmt.Printf("Playbook: %s\n", filepath.Base(opts.PlaybookPath))
-	fmt.Printf("Target:   %s\n", opts.DistroName)
+	if opts.TagsFile != "" {
+		tags, err := LoadTagsFile(opts.TagsFile)
+		if err != nil {
+			return err
+		}
+		opts.Tags = MergeTags(opts.Tags, tags)
+	}
+	if opts.SkipTagsFile != "" {
+		tags, err := LoadTagsFile(opts.SkipTagsFile)
+		if err != nil {
+			return err
+		}
+		opts.SkipTags = MergeTags(opts.SkipTags, tags)
+	}
+
+	if opts.Forks < 0 {
+		return fmt.Errorf("invalid --forks %d (must be positive)", opts.Forks)
+	}
+	if err := ValidateConnection(opts.Connection, opts.InventoryPath); err != nil {
+		return err
+	}
+	if opts.VaultPasswordFile != "" && opts.AskVaultPass {
+		return fmt.Errorf("--vault-password-file and --ask-vault-pass cannot be used together")
+	}
+	if opts.AskVaultPass && opts.Output != nil {
+		return fmt.Errorf("--ask-vault-pass cannot be used when output is captured (e.g. --parallel); use --vault-password-file")
+	}
+	if opts.AskVaultPass && !isTerminal(os.Stdin) {
+		return fmt.Errorf("--ask-vault-pass requires an interactive terminal; use --vault-password-file when running non-interactively")
+	}
+	if opts.VaultPasswordFile != "" {
+		if _, err := os.Stat(opts.VaultPasswordFile); err != nil {
+			return fmt.Errorf("vault password file '%s' not found: %w", opts.VaultPasswordFile, err)
+		}
+	}
+	if err := validateVaultIDs(opts); err != nil {
+		return err
+	}
+	if opts.ExtraVarsFile != "" {
+		if err := ValidateExtraVarsFile(opts.ExtraVarsFile); err != nil {
+			return err
+		}
+	}
+	if opts.AnsibleCfg != "" {
+		if _, err := os.Stat(opts.AnsibleCfg); err != nil {
+			return fmt.Errorf("ansible.cfg '%s' not found: %w", opts.AnsibleCfg, err)
+		}
+	}
+	for _, requirements := range []string{opts.GalaxyCollectionsFile, opts.GalaxyRolesFile} {
+		if requirements == "" {
+			continue
+		}
+		if _, err := os.Stat(requirements); err != nil {
+			return fmt.Errorf("galaxy requirements file '%s' not found: %w", requirements, err)
+		}
+	}
+	if opts.RequiredAnsibleVersion != "" {
+		if _, err := splitVersion(opts.RequiredAnsibleVersion); err != nil {
+			return fmt.Errorf("invalid required Ansible version: %w", err)
+		}
+	}
+	if opts.BecomePasswordFile != "" {
+		if _, err := os.Stat(opts.BecomePasswordFile); err != nil {
+			return fmt.Errorf("become password file '%s' not found: %w", opts.BecomePasswordFile, err)
+		}
+	} else if !dryRun && !opts.SyntaxCheck && isLocalConnection(opts.Connection) {
+		// Without a password ansible-playbook would block forever on the sudo prompt
+		ok, err := checkPasswordlessSudo(opts.DistroName)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("sudo in '%s' requires a password; pass it with --become-password-file or enable passwordless sudo for the default user", opts.DistroName)
+		}
+	}
+
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	// The output log is a convenience: failing to open it must not stop the run
+	var logFile io.Writer
+	if opts.OutputLog != "" && !dryRun {
+		f, err := openOutputLog(opts)
+		if err != nil {
+			fmt.Fprintf(out, "⚠ Warning: %v; continuing without an output log\n", err)
+		} else {
+			defer f.Close()
+			logFile = f
+			out = io.MultiWriter(out, f)
+		}
+	}
+
+	// Files copied into WSL get a per-playbook suffix so parallel runs don't collide
+	suffix := wslFileSuffix(opts.PlaybookPath)
+
+	fmt.Fprintf(out, "Playbook: %s\n", filepath.Base(opts.PlaybookPath))
+	fmt.Fprintf(out, "Target:   %s\n", opts.DistroName)
 	if len(opts.Tags) > 0 {
-		fmt.Printf("Tags:     %s\n", strings.Join(opts.Tags, ", "))
+		fmt.Fprintf(out, "Tags:     %s\n", strings.Join(opts.Tags, ", "))
+	}
+	if len(opts.SkipTags) > 0 {
+		fmt.Fprintf(out, "Skip:     %s\n", strings.Join(opts.SkipTags, ", "))
 	}
-	fmt.Println()
+	if opts.Limit != "" {
+		fmt.Fprintf(out, "Limit:    %s\n", opts.Limit)
+	}
+	fmt.Fprintln(out)
 
-	if err := ensurePackage(opts.DistroName, "ansible-playbook", "ansible"); err != nil {
-		return fmt.Errorf("failed to ensure Ansible is installed: %w", err)
+	if !opts.AnsibleReady {
+		ensure := EnsureAnsible
+		if opts.ForceReinstallAnsible {
+			ensure = ReinstallAnsible
+		}
+		if err := ensure(opts.DistroName); err != nil {
+			return err
+		}
+	}
+	if opts.RequiredAnsibleVersion != "" && !dryRun {
+		if err := checkAnsibleVersion(opts.DistroName, opts.RequiredAnsibleVersion); err != nil {
+			return err
+		}
 	}
 
-	wslPlaybookPath, err := copyPlaybookToWSL(opts.DistroName, opts.PlaybookPath)
+	wslPlaybookPath, err := copyPlaybookToWSL(opts.DistroName, opts.PlaybookPath, "/tmp/autowsl-playbook"+suffix+".yml")
 	if err != nil {
 		return fmt.Errorf("failed to copy playbook to WSL: %w", err)
 	}
 
-	ansibleCmd := buildAnsibleCommand(wslPlaybookPath, opts)
-	fmt.Println("Executing playbook...")
-	fmt.Println(strings.Repeat("-", 60))
+	wslRequirements := ""
+	if !opts.SkipGalaxyInstall {
+		if requirements := findRequirementsFile(opts.PlaybookPath); requirements != "" {
+			fmt.Fprintf(out, "Found %s, installing Galaxy collections first\n", filepath.Base(requirements))
+			wslRequirements, err = copyFileToWSL(opts.DistroName, requirements, "/tmp/autowsl-requirements"+suffix+".yml", "644")
+			if err != nil {
+				return fmt.Errorf("failed to copy requirements file to WSL: %w", err)
+			}
+		}
+	}
 
-	if err := runWslCommand(opts.DistroName, ansibleCmd); err != nil {
-		return fmt.Errorf("playbook '%s' execution failed: %w", filepath.Base(opts.PlaybookPath), err)
+	if opts.VaultPasswordFile != "" {
+		wslVaultPath, err := copyFileToWSL(opts.DistroName, opts.VaultPasswordFile, "/tmp/autowsl-vault-pass"+suffix, "600")
+		if err != nil {
+			return fmt.Errorf("failed to copy vault password file to WSL: %w", err)
+		}
+		defer func() {
+			_ = runWslCommandTo(opts.DistroName, "rm -f "+wslVaultPath, opts.Output)
+		}()
+		opts.VaultPasswordFile = wslVaultPath
+	}
+
+	if len(opts.VaultIDs) > 0 {
+		wslVaultPaths, err := copyVaultIDsToWSL(&opts, suffix)
+		defer func() {
+			if len(wslVaultPaths) > 0 {
+				_ = runWslCommandTo(opts.DistroName, "rm -f '"+strings.Join(wslVaultPaths, "' '")+"'", opts.Output)
+			}
+		}()
+		if err != nil {
+			return err
+		}
 	}
 
-	fmt.Println(strings.Repeat("-", 60))
-	fmt.Println("Playbook execution completed.")
+	if opts.ExtraVarsFile != "" {
+		wslVarsPath, err := copyFileToWSL(opts.DistroName, opts.ExtraVarsFile, "/tmp/autowsl-extravars"+suffix+filepath.Ext(opts.ExtraVarsFile), "600")
+		if err != nil {
+			return fmt.Errorf("failed to copy extra vars file to WSL: %w", err)
+		}
+		defer func() {
+			_ = runWslCommandTo(opts.DistroName, "rm -f "+wslVarsPath, opts.Output)
+		}()
+		opts.ExtraVarsFile = wslVarsPath
+	}
+
+	if opts.InventoryPath != "" {
+		wslInventoryPath, err := copyFileToWSL(opts.DistroName, opts.InventoryPath, "/tmp/autowsl-inventory"+suffix+filepath.Ext(opts.InventoryPath), "600")
+		if err != nil {
+			return fmt.Errorf("failed to copy inventory to WSL: %w", err)
+		}
+		defer func() {
+			_ = runWslCommandTo(opts.DistroName, "rm -f "+wslInventoryPath, opts.Output)
+		}()
+		opts.InventoryPath = wslInventoryPath
+	}
+
+	if opts.BecomePasswordFile != "" {
+		wslBecomePath, err := copyFileToWSL(opts.DistroName, opts.BecomePasswordFile, "/tmp/autowsl-become-pass"+suffix, "600")
+		if err != nil {
+			return fmt.Errorf("failed to copy become password file to WSL: %w", err)
+		}
+		defer func() {
+			_ = runWslCommandTo(opts.DistroName, "rm -f "+wslBecomePath, opts.Output)
+		}()
+		opts.BecomePasswordFile = wslBecomePath
+	}
+
+	var envVars map[string]string
+	if opts.AnsibleCfg != "" {
+		wslCfgPath, err := copyFileToWSL(opts.DistroName, opts.AnsibleCfg, "/tmp/autowsl-ansible"+suffix+".cfg", "644")
+		if err != nil {
+			return fmt.Errorf("failed to copy ansible.cfg to WSL: %w", err)
+		}
+		defer func() {
+			_ = runWslCommandTo(opts.DistroName, "rm -f "+wslCfgPath, opts.Output)
+		}()
+		envVars = map[string]string{"ANSIBLE_CONFIG": wslCfgPath}
+	}
+
+	commands := BuildPlaybookCommands(wslPlaybookPath, wslRequirements, opts)
+	galaxyCmds, ansibleCmd := commands[:len(commands)-1], commands[len(commands)-1]
+
+	for _, galaxyCmd := range galaxyCmds {
+		if err := runWslCommandLogged(opts.DistroName, galaxyCmd, envVars, opts.Output, logFile); err != nil {
+			return fmt.Errorf("failed to install Galaxy requirements: %w", err)
+		}
+	}
+
+	galaxy := galaxyRun{envVars: envVars, out: opts.Output, log: logFile}
+	if opts.GalaxyCollectionsFile != "" {
+		fmt.Fprintf(out, "Installing Galaxy collections from %s\n", filepath.Base(opts.GalaxyCollectionsFile))
+		if err := galaxyInstall(opts.DistroName, opts.GalaxyCollectionsFile, "/tmp/autowsl-galaxy-collections"+suffix+".yml", BuildGalaxyCommand, galaxy); err != nil {
+			return err
+		}
+	}
+	if opts.GalaxyRolesFile != "" {
+		fmt.Fprintf(out, "Installing Galaxy roles from %s\n", filepath.Base(opts.GalaxyRolesFile))
+		if err := galaxyInstall(opts.DistroName, opts.GalaxyRolesFile, "/tmp/autowsl-galaxy-roles"+suffix+".yml", BuildGalaxyRolesCommand, galaxy); err != nil {
+			return err
+		}
+	}
+
+	if opts.SyntaxCheck {
+		fmt.Fprintln(out, "Checking playbook syntax...")
+		if err := runWslCommandLogged(opts.DistroName, ansibleCmd, envVars, opts.Output, logFile); err != nil {
+			return fmt.Errorf("playbook '%s' failed syntax check: %w", filepath.Base(opts.PlaybookPath), err)
+		}
+		fmt.Fprintln(out, "Syntax OK.")
+		return nil
+	}
+
+	// Check mode promises no changes, so the ad-hoc commands are skipped
+	runCustomCmds := !opts.CheckMode
+	if opts.CheckMode && len(opts.PrePlaybookCmds)+len(opts.PostPlaybookCmds) > 0 {
+		fmt.Fprintln(out, "Skipping pre/post-playbook commands in check mode")
+	}
+
+	if runCustomCmds {
+		for _, preCmd := range opts.PrePlaybookCmds {
+			fmt.Fprintf(out, "Running pre-playbook command: %s\n", preCmd)
+			if err := runWslCommandLogged(opts.DistroName, preCmd, nil, opts.Output, logFile); err != nil {
+				return fmt.Errorf("pre-playbook command '%s' failed: %w", preCmd, err)
+			}
+		}
+	}
+
+	fmt.Fprintln(out, "Executing playbook...")
+	if opts.CheckMode {
+		fmt.Fprintln(out, "⚠ DRY RUN – no changes will be made")
+	}
+	fmt.Fprintln(out, strings.Repeat("-", 60))
+
+	playbookErr := runWslCommandLogged(opts.DistroName, ansibleCmd, envVars, opts.Output, logFile)
+
+	fmt.Fprintln(out, strings.Repeat("-", 60))
+
+	// Post commands run whatever the outcome, e.g. to collect logs
+	var postErr error
+	if runCustomCmds {
+		postErr = runPostPlaybookCmds(opts, out, logFile)
+	}
+
+	if playbookErr != nil {
+		return fmt.Errorf("playbook '%s' execution failed: %w", filepath.Base(opts.PlaybookPath), playbookErr)
+	}
+	if postErr != nil && opts.StrictPostCmds {
+		return postErr
+	}
+
+	fmt.Fprintln(out, "Playbook execution completed.")
+	return nil
+}
+
+// runPostPlaybookCmds runs every post-playbook command, warning about each
+// failure, and returns the failures joined together
+func runPostPlaybookCmds(opts PlaybookOptions, out, logFile io.Writer) error {
+	var errs []error
+	for _, postCmd := range opts.PostPlaybookCmds {
+		fmt.Fprintf(out, "Running post-playbook command: %s\n", postCmd)
+		if err := runWslCommandLogged(opts.DistroName, postCmd, nil, opts.Output, logFile); err != nil {
+			fmt.Fprintf(out, "⚠ Warning: post-playbook command '%s' failed: %v\n", postCmd, err)
+			errs = append(errs, fmt.Errorf("post-playbook command '%s' failed: %w", postCmd, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// openOutputLog opens opts.OutputLog for appending and writes a header for this
+// run. Relative paths are resolved against the current directory.
+func openOutputLog(opts PlaybookOptions) (*os.File, error) {
+	path, err := filepath.Abs(opts.OutputLog)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output log path '%s': %w", opts.OutputLog, err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open output log: %w", err)
+	}
+	fmt.Fprintln(f, OutputLogHeader(opts, time.Now()))
+	return f, nil
+}
+
+// OutputLogHeader is the line written to the output log before each playbook run
+func OutputLogHeader(opts PlaybookOptions, at time.Time) string {
+	tags := "all"
+	if len(opts.Tags) > 0 {
+		tags = strings.Join(opts.Tags, ",")
+	}
+	return fmt.Sprintf("=== autowsl playbook=%s distro=%s time=%s tags=%s ===",
+		filepath.Base(opts.PlaybookPath), opts.DistroName, at.Format(time.RFC3339), tags)
+}
+
+// Validate checks a playbook's syntax inside the distribution without running
+// any tasks. It takes the same options as ExecutePlaybook.
+func Validate(opts PlaybookOptions) error {
+	opts.SyntaxCheck = true
+	return ExecutePlaybook(opts)
+}
+
+// EnsureAnsible installs Ansible in the distribution if it is missing.
+func EnsureAnsible(distroName string) error {
+	if err := ensurePackage(distroName, "ansible-playbook", "ansible", false); err != nil {
+		return fmt.Errorf("failed to ensure Ansible is installed: %w", err)
+	}
+	return nil
+}
+
+// ReinstallAnsible installs Ansible in the distribution even if it is
+// present, upgrading it where the package manager has a newer version, and
+// runs the post-install steps again.
+func ReinstallAnsible(distroName string) error {
+	if err := ensurePackage(distroName, "ansible-playbook", "ansible", true); err != nil {
+		return fmt.Errorf("failed to reinstall Ansible: %w", err)
+	}
+	return nil
+}
+
+// wslConfSystemdCheck exits successfully when /etc/wsl.conf sets systemd=true
+// in its [boot] section.
+const wslConfSystemdCheck = `awk -F= '/^[[:space:]]*\[/ { boot = ($0 ~ /^[[:space:]]*\[boot\]/) } boot && $1 ~ /^[[:space:]]*systemd[[:space:]]*$/ { v = $2 } END { gsub(/[[:space:]]/, "", v); exit tolower(v) != "true" }' /etc/wsl.conf 2>/dev/null`
+
+// wslConfSystemdUpdate rewrites /etc/wsl.conf with systemd=true in its [boot]
+// section, replacing an existing systemd setting and keeping everything else.
+const wslConfSystemdUpdate = `f=/etc/wsl.conf; tmp=/tmp/autowsl-wsl.conf; ` +
+	`{ if [ -f "$f" ]; then awk '/^[[:space:]]*\[/ { if (boot && !done) { print "systemd=true"; done = 1 } boot = ($0 ~ /^[[:space:]]*\[boot\]/) } boot && /^[[:space:]]*systemd[[:space:]]*=/ { if (!done) { print "systemd=true"; done = 1 } next } { print } END { if (!done) { if (!boot) print "[boot]"; print "systemd=true" } }' "$f"; else printf '[boot]\nsystemd=true\n'; fi; } > "$tmp" && ` +
+	`if [ "$(id -u)" = 0 ]; then cp "$tmp" "$f"; else sudo cp "$tmp" "$f"; fi && rm -f "$tmp"`
+
+// SystemdEnabled reports whether /etc/wsl.conf in the distribution enables systemd.
+func SystemdEnabled(distroName string) bool {
+	return wslSucceeds(distroName, wslConfSystemdCheck)
+}
+
+// EnsureSystemd sets systemd=true in the [boot] section of the distribution's
+// /etc/wsl.conf if it is not already enabled. The setting only takes effect
+// once the distribution is restarted.
+func EnsureSystemd(distroName string) error {
+	if SystemdEnabled(distroName) {
+		fmt.Printf("✓ systemd is already enabled in '%s'\n", distroName)
+		return nil
+	}
+	fmt.Printf("→ Enabling systemd in /etc/wsl.conf of '%s'...\n", distroName)
+	if err := runWslCommand(distroName, wslConfSystemdUpdate); err != nil {
+		return fmt.Errorf("failed to enable systemd in '%s': %w", distroName, err)
+	}
+	fmt.Printf("✓ systemd enabled; '%s' must be restarted for it to take effect\n", distroName)
 	return nil
 }
 
+// wslFileSuffix returns a short suffix derived from a playbook path
+func wslFileSuffix(playbookPath string) string {
+	h := fnv.New32a()
+	h.Write([]byte(playbookPath))
+	return fmt.Sprintf("-%08x", h.Sum32())
+}
+
 // copyPlaybookToWSL copies a playbook from Windows to the WSL filesystem.
-func copyPlaybookToWSL(distroName, windowsPlaybookPath string) (string, error) {
-	wslPlaybookPath := "/tmp/autowsl-playbook.yml"
-	content, err := os.ReadFile(windowsPlaybookPath)
+func copyPlaybookToWSL(distroName, windowsPlaybookPath, wslPath string) (string, error) {
+	return copyFileToWSL(distroName, windowsPlaybookPath, wslPath, "644")
+}
+
+// copyFileToWSL copies a file from Windows to the given path in the WSL filesystem.
+func copyFileToWSL(distroName, windowsPath, wslPath, mode string) (string, error) {
+	content, err := os.ReadFile(windowsPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read playbook '%s': %w", windowsPlaybookPath, err)
+		return "", fmt.Errorf("failed to read '%s': %w", windowsPath, err)
+	}
+	if err := writeToWSL(distroName, content, wslPath, mode, windowsPath); err != nil {
+		return "", err
+	}
+	return wslPath, nil
+}
+
+// writeToWSL writes content to wslPath inside the distribution with the given
+// file mode. source names the content in dry-run output and errors.
+func writeToWSL(distroName string, content []byte, wslPath, mode, source string) error {
+	// Create with restrictive permissions first so secrets are never world-readable
+	writeCmdStr := fmt.Sprintf("umask 077 && cat > '%s' && chmod %s '%s'", wslPath, mode, wslPath)
+	if dryRun {
+		fmt.Printf("[dry-run] %s < %s\n", runner.FormatCommand(wslExe, "-d", distroName, "sh", "-c", writeCmdStr), source)
+		return nil
+	}
+	if output, err := wslCombinedOutput(distroName, writeCmdStr, content); err != nil {
+		return fmt.Errorf("failed to copy '%s' to WSL filesystem: %s: %w", filepath.Base(source), output, err)
+	}
+	return nil
+}
+
+// isLocalConnection reports whether playbooks configure the distribution itself
+func isLocalConnection(connection string) bool {
+	return connection == "" || connection == "local"
+}
+
+// ValidateConnection checks a connection type and inventory: local runs
+// target the distribution and take no inventory, any other connection
+// (e.g. ssh to remote hosts) needs one
+func ValidateConnection(connection, inventoryPath string) error {
+	if strings.ContainsAny(connection, " \t'\"`$;&|<>\\") {
+		return fmt.Errorf("invalid connection type %q", connection)
+	}
+	if isLocalConnection(connection) {
+		if inventoryPath != "" {
+			return fmt.Errorf("--inventory requires a --connection other than local")
+		}
+		return nil
+	}
+	if inventoryPath == "" {
+		return fmt.Errorf("--connection %s requires an --inventory listing the hosts", connection)
 	}
+	if _, err := os.Stat(inventoryPath); err != nil {
+		return fmt.Errorf("inventory '%s' not found: %w", inventoryPath, err)
+	}
+	return nil
+}
 
-	writeCmdStr := fmt.Sprintf("cat > '%s' && chmod 644 '%s'", wslPlaybookPath, wslPlaybookPath)
-	writeCmd := exec.Command("wsl.exe", "-d", distroName, "sh", "-c", writeCmdStr)
-	writeCmd.Stdin = strings.NewReader(string(content))
+// checkPasswordlessSudo reports whether the default user of a distribution can
+// use sudo without a password. Root is always allowed, even without sudo installed.
+func checkPasswordlessSudo(distroName string) (bool, error) {
+	if _, err := wslCombinedOutput(distroName, `[ "$(id -u)" = 0 ] || sudo -n true`, nil); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check sudo in '%s': %w", distroName, err)
+	}
+	return true, nil
+}
 
-	if output, err := writeCmd.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("failed to copy playbook to WSL filesystem: %s: %w", string(output), err)
+// isTerminal reports whether f is attached to an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// findRequirementsFile returns the Galaxy requirements file that sits next to
+// a playbook, or "" if there is none
+func findRequirementsFile(playbookPath string) string {
+	dir := filepath.Dir(playbookPath)
+	for _, name := range []string{"requirements.yml", "requirements.yaml"} {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
 	}
+	return ""
+}
+
+// BuildGalaxyCommand constructs the command that installs the collections
+// listed in a requirements file.
+func BuildGalaxyCommand(requirementsPath string) string {
+	return fmt.Sprintf("ansible-galaxy collection install -r '%s'", requirementsPath)
+}
 
-	return wslPlaybookPath, nil
+// BuildPlaybookCommands returns the commands ExecutePlaybook runs, in order:
+// the Galaxy install (when a requirements file is given and not skipped)
+// followed by the ansible-playbook invocation.
+func BuildPlaybookCommands(playbookPath, requirementsPath string, opts PlaybookOptions) []string {
+	var commands []string
+	if requirementsPath != "" && !opts.SkipGalaxyInstall {
+		commands = append(commands, BuildGalaxyCommand(requirementsPath))
+	}
+	return append(commands, BuildAnsibleCommand(playbookPath, opts))
 }
 
-// buildAnsibleCommand constructs the full ansible-playbook command string.
-func buildAnsibleCommand(playbookPath string, opts PlaybookOptions) string {
+// BuildAnsibleCommand constructs the full ansible-playbook command string.
+func BuildAnsibleCommand(playbookPath string, opts PlaybookOptions) string {
 	var cmd strings.Builder
-	cmd.WriteString(fmt.Sprintf("ansible-playbook %s --connection=local -i localhost,", playbookPath))
+	if isLocalConnection(opts.Connection) {
+		cmd.WriteString(fmt.Sprintf("ansible-playbook %s --connection=local -i localhost,", playbookPath))
+	} else {
+		cmd.WriteString(fmt.Sprintf("ansible-playbook %s --connection=%s -i %s", playbookPath, opts.Connection, shellQuote(opts.InventoryPath)))
+	}
 
 	if len(opts.Tags) > 0 {
-		cmd.WriteString(fmt.Sprintf(" --tags %s", strings.Join(opts.Tags, ",")))
+		cmd.WriteString(" --tags " + shellQuote(strings.Join(opts.Tags, ",")))
+	}
+
+	if len(opts.SkipTags) > 0 {
+		cmd.WriteString(" --skip-tags " + shellQuote(strings.Join(opts.SkipTags, ",")))
+	}
+
+	if opts.Limit != "" {
+		cmd.WriteString(" --limit " + shellQuote(opts.Limit))
+	}
+
+	if opts.Forks > 0 {
+		cmd.WriteString(fmt.Sprintf(" --forks %d", opts.Forks))
 	}
 
 	if opts.Verbose {
 		cmd.WriteString(" -vvv")
 	}
 
+	if opts.SyntaxCheck {
+		cmd.WriteString(" --syntax-check")
+	}
+
+	if opts.CheckMode {
+		cmd.WriteString(" --check")
+	}
+
+	if opts.DiffMode {
+		cmd.WriteString(" --diff")
+	}
+
+	if opts.VaultPasswordFile != "" {
+		cmd.WriteString(" --vault-password-file " + shellQuote(opts.VaultPasswordFile))
+	} else if opts.AskVaultPass {
+		cmd.WriteString(" --ask-vault-pass")
+	}
+
+	for _, id := range opts.VaultIDs {
+		cmd.WriteString(" --vault-id " + shellQuote(id.String()))
+	}
+
+	if opts.BecomePasswordFile != "" {
+		cmd.WriteString(" --become-password-file " + shellQuote(opts.BecomePasswordFile))
+	}
+
+	// Inline extra vars come last so they override the file
+	if opts.ExtraVarsFile != "" {
+		cmd.WriteString(" --extra-vars " + shellQuote("@"+opts.ExtraVarsFile))
+	}
+
+	// JSON keeps values with spaces and structured values from @file intact,
+	// where key=value pairs would be split on whitespace by Ansible
 	if len(opts.ExtraVars) > 0 {
-		var vars []string
-		for k, v := range opts.ExtraVars {
-			vars = append(vars, fmt.Sprintf("%s=%s", k, v))
+		if data, err := json.Marshal(opts.ExtraVars); err == nil {
+			cmd.WriteString(" --extra-vars " + shellQuote(string(data)))
 		}
-		// Use single quotes to handle spaces and other special characters in values.
-		cmd.WriteString(fmt.Sprintf(" --extra-vars '%s'", strings.Join(vars, " ")))
 	}
 
 	return cmd.String()
 }
 
+// CloneOptions holds options for cloning a git repository into a distribution.
+type CloneOptions struct {
+	RepoURL    string
+	DestDir    string
+	SSHKeyPath string // Windows path to a private key for SSH repository URLs
+	Branch     string // Branch or tag to check out instead of the default branch
+}
+
+// BuildCloneCommand constructs the git clone command. wslKeyPath is the
+// location of the SSH key inside WSL, or empty to use git's defaults.
+func BuildCloneCommand(opts CloneOptions, wslKeyPath string) string {
+	var cmd strings.Builder
+	if wslKeyPath != "" {
+		// git runs GIT_SSH_COMMAND through the shell, so the key path is quoted twice
+		sshCommand := "ssh -i " + shellQuote(wslKeyPath) + " -o StrictHostKeyChecking=no"
+		cmd.WriteString("GIT_SSH_COMMAND=" + shellQuote(sshCommand) + " ")
+	}
+	cmd.WriteString("git clone")
+	if opts.Branch != "" {
+		cmd.WriteString(" --branch " + shellQuote(opts.Branch))
+	}
+	cmd.WriteString(" " + shellQuote(opts.RepoURL) + " " + shellQuote(opts.DestDir))
+	return cmd.String()
+}
+
 // CloneGitRepo clones a git repository into a specified directory in the WSL distribution.
-func CloneGitRepo(distroName, repoURL, destDir string) error {
-	fmt.Printf("Cloning repository: %s\n", repoURL)
-	if err := ensurePackage(distroName, "git", "git"); err != nil {
+func CloneGitRepo(distroName string, opts CloneOptions) error {
+	fmt.Printf("Cloning repository: %s\n", opts.RepoURL)
+	if err := ensurePackage(distroName, "git", "git", false); err != nil {
 		return fmt.Errorf("failed to ensure git is installed: %w", err)
 	}
 
-	cloneCmdStr := fmt.Sprintf("git clone %s %s", repoURL, destDir)
-	if err := runWslCommand(distroName, cloneCmdStr); err != nil {
-		return fmt.Errorf("failed to clone repository '%s': %w", repoURL, err)
+	wslKeyPath := ""
+	if opts.SSHKeyPath != "" {
+		if _, err := os.Stat(opts.SSHKeyPath); err != nil {
+			return fmt.Errorf("SSH key '%s' not found: %w", opts.SSHKeyPath, err)
+		}
+		// ssh refuses keys that other users can read, hence mode 600
+		keyPath, err := copyFileToWSL(distroName, opts.SSHKeyPath, "/tmp/autowsl-ssh-key", "600")
+		if err != nil {
+			return fmt.Errorf("failed to copy SSH key to WSL: %w", err)
+		}
+		defer func() {
+			_ = runWslCommand(distroName, "rm -f "+keyPath)
+		}()
+		wslKeyPath = keyPath
+	}
+
+	if err := runWslCommand(distroName, BuildCloneCommand(opts, wslKeyPath)); err != nil {
+		return fmt.Errorf("failed to clone repository '%s': %w", opts.RepoURL, err)
 	}
 
 	fmt.Println("Repository cloned successfully.")
@@ -0,0 +1,27 @@
+package ansible
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateExtraVarsFile checks that path holds a JSON or YAML mapping that
+// Ansible can load with --extra-vars @file. JSON is valid YAML, so one
+// parser covers both.
+func ValidateExtraVarsFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read extra vars file '%s': %w", path, err)
+	}
+
+	var vars map[string]interface{}
+	if err := yaml.Unmarshal(data, &vars); err != nil {
+		return fmt.Errorf("extra vars file '%s' is not a valid JSON or YAML mapping: %w", path, err)
+	}
+	if vars == nil {
+		return fmt.Errorf("extra vars file '%s' is empty", path)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package ansible
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// BuildGalaxyRolesCommand constructs the command that installs the roles
+// listed in a requirements file.
+func BuildGalaxyRolesCommand(requirementsPath string) string {
+	return fmt.Sprintf("ansible-galaxy role install -r '%s'", requirementsPath)
+}
+
+// InstallCollections installs the Galaxy collections listed in a requirements
+// file on the Windows side into a distribution.
+func InstallCollections(distroName, requirementsPath string) error {
+	return galaxyInstall(distroName, requirementsPath, "/tmp/autowsl-galaxy-collections.yml", BuildGalaxyCommand, galaxyRun{})
+}
+
+// InstallRoles installs the Galaxy roles listed in a requirements file on the
+// Windows side into a distribution.
+func InstallRoles(distroName, requirementsPath string) error {
+	return galaxyInstall(distroName, requirementsPath, "/tmp/autowsl-galaxy-roles.yml", BuildGalaxyRolesCommand, galaxyRun{})
+}
+
+// galaxyRun is where ExecutePlaybook sends the output of a Galaxy install;
+// the zero value attaches it to the terminal
+type galaxyRun struct {
+	envVars map[string]string
+	out     io.Writer
+	log     io.Writer
+}
+
+// galaxyInstall copies a requirements file to wslPath, runs the command built
+// for it, and removes the copy again
+func galaxyInstall(distroName, requirementsPath, wslPath string, build func(string) string, run galaxyRun) error {
+	if _, err := os.Stat(requirementsPath); err != nil {
+		return fmt.Errorf("galaxy requirements file '%s' not found: %w", requirementsPath, err)
+	}
+	if _, err := copyPlaybookToWSL(distroName, requirementsPath, wslPath); err != nil {
+		return fmt.Errorf("failed to copy '%s' to WSL: %w", filepath.Base(requirementsPath), err)
+	}
+	defer func() {
+		_ = runWslCommandTo(distroName, "rm -f "+wslPath, run.out)
+	}()
+
+	if err := runWslCommandLogged(distroName, build(wslPath), run.envVars, run.out, run.log); err != nil {
+		return fmt.Errorf("failed to install Galaxy requirements from '%s': %w", filepath.Base(requirementsPath), err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package ansible
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pipPackagePattern matches pip requirement specifiers such as requests,
+// boto3==1.34.0 or docker[tls]>=7
+var pipPackagePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._\-\[\],<>=!~]*$`)
+
+// ValidatePipPackages checks that every package is a plain pip requirement
+// specifier that is safe to pass to the shell
+func ValidatePipPackages(packages []string) error {
+	for _, p := range packages {
+		if !pipPackagePattern.MatchString(p) {
+			return fmt.Errorf("invalid pip package '%s'", p)
+		}
+	}
+	return nil
+}
+
+// BuildPipInstallCommand constructs the command that installs Python packages
+// system-wide. PIP_BREAK_SYSTEM_PACKAGES lets pip install into distributions
+// that mark the system Python as externally managed (PEP 668); older pip
+// versions ignore it.
+func BuildPipInstallCommand(packages []string) string {
+	return fmt.Sprintf("sudo PIP_BREAK_SYSTEM_PACKAGES=1 pip3 install '%s'", strings.Join(packages, "' '"))
+}
+
+// PipInstallPackages installs Python packages with pip3 in a distribution,
+// installing pip itself first if it is missing. The package that provides
+// pip3 differs between distributions (python3-pip, python-pip, py3-pip).
+func PipInstallPackages(distroName string, packages []string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	if err := ValidatePipPackages(packages); err != nil {
+		return err
+	}
+
+	pm, err := detectPackageManager(distroName)
+	if err != nil {
+		return err
+	}
+	if pm.pipPackage == "" {
+		return fmt.Errorf("no pip package known for package manager '%s'", pm.name)
+	}
+	if err := ensurePackage(distroName, "pip3", pm.pipPackage, false); err != nil {
+		return fmt.Errorf("failed to ensure pip is installed: %w", err)
+	}
+
+	fmt.Printf("Installing Python packages: %s\n", strings.Join(packages, ", "))
+	if err := runWslCommand(distroName, BuildPipInstallCommand(packages)); err != nil {
+		return fmt.Errorf("failed to install Python packages: %w", err)
+	}
+	return nil
+}
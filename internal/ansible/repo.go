@@ -0,0 +1,68 @@
+package ansible
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// CommonPlaybookNames are the playbook names preferred in the root of a
+// cloned repository, in order
+var CommonPlaybookNames = []string{"site.yml", "main.yml", "playbook.yml", "default.yml"}
+
+// ListRepoPlaybooks returns the YAML files of a repository cloned into dir
+// inside a distribution, relative to dir. See ParseRepoPlaybooks for the order.
+func ListRepoPlaybooks(distroName, dir string) ([]string, error) {
+	listCmd := fmt.Sprintf("cd '%s' && find . -path ./.git -prune -o -type f \\( -name '*.yml' -o -name '*.yaml' \\) -print", dir)
+	if dryRun {
+		return nil, runWslCommand(distroName, listCmd)
+	}
+
+	var out bytes.Buffer
+	if err := runWslCommandTo(distroName, listCmd, &out); err != nil {
+		return nil, fmt.Errorf("failed to list playbooks in '%s': %w", dir, err)
+	}
+	return ParseRepoPlaybooks(out.String()), nil
+}
+
+// ParseRepoPlaybooks parses find output into relative paths. Root-level
+// CommonPlaybookNames come first in their preferred order, then the rest
+// sorted by path.
+func ParseRepoPlaybooks(output string) []string {
+	var common, others []string
+	for _, line := range strings.Split(output, "\n") {
+		p := strings.TrimPrefix(strings.TrimSpace(line), "./")
+		if p == "" {
+			continue
+		}
+		if commonPlaybookRank(p) >= 0 {
+			common = append(common, p)
+		} else {
+			others = append(others, p)
+		}
+	}
+	sort.Slice(common, func(i, j int) bool { return commonPlaybookRank(common[i]) < commonPlaybookRank(common[j]) })
+	sort.Strings(others)
+	return append(common, others...)
+}
+
+// commonPlaybookRank returns the index of p in CommonPlaybookNames, or -1
+func commonPlaybookRank(p string) int {
+	for i, name := range CommonPlaybookNames {
+		if p == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ValidateRepoPlaybookPath checks that p is a relative path inside a repository
+func ValidateRepoPlaybookPath(p string) error {
+	clean := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+	if p == "" || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || strings.Contains(p, "'") {
+		return fmt.Errorf("invalid repository playbook path '%s' (must be relative to the repository root)", p)
+	}
+	return nil
+}
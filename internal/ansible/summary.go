@@ -1,7 +1,9 @@
 package ansible
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 )
@@ -14,6 +16,33 @@ type ExecutionResult struct {
 	Error        error
 }
 
+// ANSI escapes used to highlight failed playbooks in the summary
+const (
+	colorRed   = "\033[31m"
+	colorReset = "\033[0m"
+)
+
+// SummarySchemaVersion is the version of the JSON written by ToJSON. It is
+// bumped whenever fields are renamed or removed.
+const SummarySchemaVersion = 1
+
+// summaryJSON is the JSON form of an ExecutionSummary
+type summaryJSON struct {
+	Version int          `json:"version"`
+	Total   int          `json:"total"`
+	Success int          `json:"success"`
+	Failed  int          `json:"failed"`
+	Results []resultJSON `json:"results"`
+}
+
+// resultJSON is the JSON form of an ExecutionResult
+type resultJSON struct {
+	Playbook        string  `json:"playbook"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
 // ExecutionSummary holds multiple execution results
 type ExecutionSummary struct {
 	Results []ExecutionResult
@@ -34,6 +63,17 @@ func (s *ExecutionSummary) HasFailures() bool {
 	return false
 }
 
+// FailedPlaybooks returns the names of the playbooks that failed, in run order
+func (s *ExecutionSummary) FailedPlaybooks() []string {
+	var names []string
+	for _, r := range s.Results {
+		if r.Status == "failed" {
+			names = append(names, r.PlaybookName)
+		}
+	}
+	return names
+}
+
 // SuccessCount returns the number of successful executions
 func (s *ExecutionSummary) SuccessCount() int {
 	count := 0
@@ -57,6 +97,7 @@ func (s *ExecutionSummary) Print() {
 	fmt.Printf("%-40s %-10s %-15s\n", "PLAYBOOK", "STATUS", "DURATION")
 	fmt.Println(strings.Repeat("-", 70))
 
+	color := supportsColor(os.Stdout)
 	for _, r := range s.Results {
 		status := r.Status
 		if r.Status == "success" {
@@ -64,7 +105,11 @@ func (s *ExecutionSummary) Print() {
 		} else if r.Status == "failed" {
 			status = "FAILED"
 		}
-		fmt.Printf("%-40s %-10s %-15s\n", r.PlaybookName, status, r.Duration.Round(time.Second))
+		line := fmt.Sprintf("%-40s %-10s %-15s", r.PlaybookName, status, r.Duration.Round(time.Second))
+		if color && r.Status == "failed" {
+			line = colorRed + line + colorReset
+		}
+		fmt.Println(line)
 	}
 
 	fmt.Println(strings.Repeat("=", 70))
@@ -74,3 +119,38 @@ func (s *ExecutionSummary) Print() {
 		len(s.Results)-s.SuccessCount())
 	fmt.Println(strings.Repeat("=", 70))
 }
+
+// ToJSON encodes the summary as versioned JSON for CI systems
+func (s *ExecutionSummary) ToJSON() ([]byte, error) {
+	out := summaryJSON{
+		Version: SummarySchemaVersion,
+		Total:   len(s.Results),
+		Success: s.SuccessCount(),
+		Failed:  len(s.FailedPlaybooks()),
+		Results: make([]resultJSON, 0, len(s.Results)),
+	}
+	for _, r := range s.Results {
+		entry := resultJSON{
+			Playbook:        r.PlaybookName,
+			Status:          r.Status,
+			DurationSeconds: r.Duration.Seconds(),
+		}
+		if r.Error != nil {
+			entry.Error = r.Error.Error()
+		}
+		out.Results = append(out.Results, entry)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// WriteJSON writes the summary as JSON to path
+func (s *ExecutionSummary) WriteJSON(path string) error {
+	data, err := s.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode execution summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write execution summary '%s': %w", path, err)
+	}
+	return nil
+}
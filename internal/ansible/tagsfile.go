@@ -0,0 +1,52 @@
+package ansible
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tagsFile is the format of --tags-file and --skip-tags-file:
+//
+//	tags: [docker, nodejs, python]
+type tagsFile struct {
+	Tags []string `yaml:"tags"`
+}
+
+// LoadTagsFile reads the tag list from a YAML file with a top-level tags key
+func LoadTagsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags file '%s': %w", path, err)
+	}
+
+	var f tagsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("tags file '%s' is not valid YAML (expected tags: [a, b]): %w", path, err)
+	}
+	if len(f.Tags) == 0 {
+		return nil, fmt.Errorf("tags file '%s' has no tags (expected tags: [a, b])", path)
+	}
+	for _, tag := range f.Tags {
+		if strings.TrimSpace(tag) == "" || strings.ContainsAny(tag, ", ") {
+			return nil, fmt.Errorf("invalid tag %q in '%s'", tag, path)
+		}
+	}
+	return f.Tags, nil
+}
+
+// MergeTags appends the tags of extra that are not already in tags, keeping
+// the order of first appearance
+func MergeTags(tags, extra []string) []string {
+	seen := make(map[string]bool, len(tags)+len(extra))
+	var merged []string
+	for _, tag := range append(append([]string{}, tags...), extra...) {
+		if !seen[tag] {
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
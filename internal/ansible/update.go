@@ -0,0 +1,73 @@
+package ansible
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// UpdateOptions holds options for upgrading the packages of a distribution.
+type UpdateOptions struct {
+	DistroName   string
+	Packages     []string // Limit the upgrade to these packages; empty upgrades everything
+	SecurityOnly bool     // Apply security updates only
+}
+
+// packageNamePattern matches package names that are safe to pass to a shell
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+_:@=~-]*$`)
+
+// BuildUpdateCommand returns the upgrade command for the named package manager
+// (apt, dnf, yum, zypper, pacman, or apk).
+func BuildUpdateCommand(pmName string, packages []string, securityOnly bool) (string, error) {
+	var pm *packageManager
+	for i := range supportedPMs {
+		if supportedPMs[i].name == pmName {
+			pm = &supportedPMs[i]
+			break
+		}
+	}
+	if pm == nil {
+		return "", fmt.Errorf("unsupported package manager '%s'", pmName)
+	}
+
+	for _, p := range packages {
+		if !packageNamePattern.MatchString(p) {
+			return "", fmt.Errorf("invalid package name '%s'", p)
+		}
+	}
+
+	switch {
+	case securityOnly && len(packages) > 0:
+		return "", fmt.Errorf("--security-only cannot be combined with a package list")
+	case securityOnly:
+		if pm.securityUpgradeCmd == "" {
+			return "", fmt.Errorf("%s does not support security-only upgrades", pm.name)
+		}
+		return pm.securityUpgradeCmd, nil
+	case len(packages) > 0:
+		if pm.upgradePackagesCmd == "" {
+			return "", fmt.Errorf("%s does not support upgrading individual packages; run a full upgrade instead", pm.name)
+		}
+		return fmt.Sprintf(pm.upgradePackagesCmd, strings.Join(packages, " ")), nil
+	default:
+		return pm.upgradeCmd, nil
+	}
+}
+
+// UpdatePackages upgrades the packages of a distribution using its native package manager.
+func UpdatePackages(opts UpdateOptions) error {
+	pm, err := detectPackageManager(opts.DistroName)
+	if err != nil {
+		return err
+	}
+
+	updateCmd, err := BuildUpdateCommand(pm.name, opts.Packages, opts.SecurityOnly)
+	if err != nil {
+		return err
+	}
+
+	if err := runWslCommand(opts.DistroName, updateCmd); err != nil {
+		return fmt.Errorf("failed to update packages in '%s': %w", opts.DistroName, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package ansible
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// VaultPromptSource is the VaultID source that asks for the password on the
+// terminal instead of reading a file
+const VaultPromptSource = "@prompt"
+
+// VaultID is one Ansible vault identity: a label and where its password
+// comes from
+type VaultID struct {
+	Label  string // Empty for the default identity
+	Source string // Windows path to a password file, or VaultPromptSource
+}
+
+// vaultClientScript is an Ansible vault password client that asks for the
+// password of the vault ID it is called with. Ansible passes --vault-id to
+// scripts whose name ends in -client.
+const vaultClientScript = `#!/usr/bin/env python3
+import argparse
+import getpass
+import sys
+
+parser = argparse.ArgumentParser()
+parser.add_argument("--vault-id", default="default")
+args, _ = parser.parse_known_args()
+sys.stdout.write(getpass.getpass("Vault password (%s): " % args.vault_id) + "\n")
+`
+
+// ParseVaultID parses a --vault-id value: label@source, or just source for
+// the default identity. A source of "prompt" or "@prompt" asks for the
+// password on the terminal.
+func ParseVaultID(value string) (VaultID, error) {
+	id := VaultID{Source: value}
+	if label, source, ok := strings.Cut(value, "@"); ok {
+		id = VaultID{Label: label, Source: source}
+	}
+	if id.Source == "prompt" {
+		id.Source = VaultPromptSource
+	}
+
+	if id.Source == "" {
+		return VaultID{}, fmt.Errorf("invalid vault ID %q (expected label@source)", value)
+	}
+	if strings.ContainsAny(id.Label, "'@ \t") {
+		return VaultID{}, fmt.Errorf("invalid vault ID label %q", id.Label)
+	}
+	if strings.Contains(id.Source, "'") {
+		return VaultID{}, fmt.Errorf("invalid vault ID source %q", id.Source)
+	}
+	return id, nil
+}
+
+// String formats the ID as Ansible's --vault-id argument. Prompt sources use
+// Ansible's own prompt.
+func (v VaultID) String() string {
+	source := v.Source
+	if source == VaultPromptSource {
+		source = "prompt"
+	}
+	if v.Label == "" {
+		return source
+	}
+	return v.Label + "@" + source
+}
+
+// HasVaultPrompt reports whether any of ids asks for its password
+func HasVaultPrompt(ids []VaultID) bool {
+	for _, id := range ids {
+		if id.Source == VaultPromptSource {
+			return true
+		}
+	}
+	return false
+}
+
+// validateVaultIDs checks that password files exist and that prompts can be
+// answered
+func validateVaultIDs(opts PlaybookOptions) error {
+	for _, id := range opts.VaultIDs {
+		if id.Source != VaultPromptSource {
+			if _, err := os.Stat(id.Source); err != nil {
+				return fmt.Errorf("vault password file '%s' for vault ID '%s' not found: %w", id.Source, id.Label, err)
+			}
+			continue
+		}
+		if opts.Output != nil {
+			return fmt.Errorf("vault ID '%s' prompts for a password, which is not possible when output is captured (e.g. --parallel); use a password file", id.Label)
+		}
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("vault ID '%s' prompts for a password and requires an interactive terminal; use a password file when running non-interactively", id.Label)
+		}
+	}
+	return nil
+}
+
+// copyVaultIDsToWSL copies the vault password files into the distribution
+// and points the IDs at the copies. Unless --ask-vault-pass already makes
+// Ansible prompt, prompt sources use a password client script so every ID
+// gets its own labelled prompt. Returns the WSL paths to remove afterwards.
+func copyVaultIDsToWSL(opts *PlaybookOptions, suffix string) ([]string, error) {
+	var paths []string
+	ids := make([]VaultID, len(opts.VaultIDs))
+	copy(ids, opts.VaultIDs)
+
+	clientPath := ""
+	for i, id := range ids {
+		if id.Source == VaultPromptSource {
+			if opts.AskVaultPass {
+				continue
+			}
+			if clientPath == "" {
+				path := "/tmp/autowsl-vault" + suffix + "-client.py"
+				if err := writeToWSL(opts.DistroName, []byte(vaultClientScript), path, "700", "vault password client"); err != nil {
+					return paths, err
+				}
+				clientPath = path
+				paths = append(paths, path)
+			}
+			ids[i].Source = clientPath
+			continue
+		}
+
+		path, err := copyFileToWSL(opts.DistroName, id.Source, fmt.Sprintf("/tmp/autowsl-vault-id-%d%s", i, suffix), "600")
+		if err != nil {
+			return paths, fmt.Errorf("failed to copy vault password file to WSL: %w", err)
+		}
+		ids[i].Source = path
+		paths = append(paths, path)
+	}
+
+	opts.VaultIDs = ids
+	return paths, nil
+}
@@ -0,0 +1,102 @@
+package ansible
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches the first dotted version number, e.g. 2.14.3
+var versionPattern = regexp.MustCompile(`\d+(\.\d+){1,2}`)
+
+// ParseAnsibleVersion extracts the version from the first line of
+// `ansible-playbook --version`, which reads "ansible-playbook [core 2.14.3]"
+// on current releases and "ansible-playbook 2.9.6" on older ones.
+func ParseAnsibleVersion(output string) (string, error) {
+	first, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	version := versionPattern.FindString(first)
+	if version == "" {
+		return "", fmt.Errorf("could not find a version in %q", strings.TrimSpace(first))
+	}
+	return version, nil
+}
+
+// CompareVersions compares two dotted versions such as "2.14" and "2.14.3".
+// Missing parts count as zero and a leading "v" is ignored. It returns -1, 0,
+// or 1 like strings.Compare.
+func CompareVersions(a, b string) (int, error) {
+	pa, err := splitVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := splitVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < 3; i++ {
+		switch {
+		case pa[i] < pb[i]:
+			return -1, nil
+		case pa[i] > pb[i]:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// splitVersion parses "major[.minor[.patch]]"
+func splitVersion(v string) ([3]int, error) {
+	var parts [3]int
+	fields := strings.Split(strings.TrimPrefix(strings.TrimSpace(v), "v"), ".")
+	if len(fields) > 3 {
+		return parts, fmt.Errorf("invalid version '%s' (expected major.minor.patch)", v)
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, fmt.Errorf("invalid version '%s' (expected major.minor.patch)", v)
+		}
+		parts[i] = n
+	}
+	return parts, nil
+}
+
+// ansibleVersion returns the ansible-playbook version in the distribution,
+// detecting it once per distro.
+func ansibleVersion(distroName string) (string, error) {
+	pmMutex.Lock()
+	defer pmMutex.Unlock()
+
+	if v, ok := memoizedAnsibleVersions[distroName]; ok {
+		return v, nil
+	}
+
+	var out bytes.Buffer
+	if err := runWslCommandTo(distroName, "ansible-playbook --version", &out); err != nil {
+		return "", fmt.Errorf("failed to get the Ansible version in '%s': %w", distroName, err)
+	}
+	version, err := ParseAnsibleVersion(out.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to get the Ansible version in '%s': %w", distroName, err)
+	}
+	memoizedAnsibleVersions[distroName] = version
+	return version, nil
+}
+
+// checkAnsibleVersion fails when the distribution's Ansible is older than required
+func checkAnsibleVersion(distroName, required string) error {
+	version, err := ansibleVersion(distroName)
+	if err != nil {
+		return err
+	}
+	cmp, err := CompareVersions(version, required)
+	if err != nil {
+		return err
+	}
+	if cmp < 0 {
+		return fmt.Errorf("installed Ansible %s in '%s' is older than the required %s; upgrade it, e.g. with 'autowsl update %s ansible'", version, distroName, required, distroName)
+	}
+	return nil
+}
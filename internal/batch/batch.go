@@ -0,0 +1,107 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/yuanjua/autowsl/internal/wsl"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWSLVersion is used for entries without wsl_version
+const DefaultWSLVersion = 2
+
+// Entry is one distribution to install and provision
+type Entry struct {
+	Version    string   `yaml:"version" json:"version"` // Catalog version, e.g. "Ubuntu 22.04 LTS"
+	Name       string   `yaml:"name" json:"name"`
+	Path       string   `yaml:"path,omitempty" json:"path,omitempty"` // Empty uses the default install root
+	WSLVersion int      `yaml:"wsl_version,omitempty" json:"wsl_version,omitempty"`
+	Playbooks  []string `yaml:"playbooks,omitempty" json:"playbooks,omitempty"`
+}
+
+// Status values of an entry compared against the installed distributions
+const (
+	StatusOK              = "ok"
+	StatusMissing         = "missing"
+	StatusVersionMismatch = "version mismatch"
+)
+
+// Installed is what Compare needs to know about an installed distribution
+type Installed struct {
+	State         string // Running or Stopped
+	DistroVersion string // From .autowsl.json; empty when unknown
+}
+
+// EntryStatus is an entry of a spec and its state on this machine
+type EntryStatus struct {
+	Entry  `yaml:",inline"`
+	State  string `yaml:"state" json:"state"` // Empty when not installed
+	Status string `yaml:"status" json:"status"`
+}
+
+// Load reads and validates a spec file
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec '%s': %w", path, err)
+	}
+	entries, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec '%s': %w", path, err)
+	}
+	return entries, nil
+}
+
+// Parse decodes a spec, a YAML list of entries, validates it and fills in
+// defaults
+func Parse(data []byte) ([]Entry, error) {
+	var entries []Entry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no distributions defined")
+	}
+
+	seen := make(map[string]bool)
+	for i := range entries {
+		e := &entries[i]
+		if e.Version == "" {
+			return nil, fmt.Errorf("entry %d: version is required", i+1)
+		}
+		if err := wsl.ValidateDistroName(e.Name); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("entry %d: duplicate name '%s'", i+1, e.Name)
+		}
+		seen[e.Name] = true
+
+		if e.WSLVersion == 0 {
+			e.WSLVersion = DefaultWSLVersion
+		}
+		if e.WSLVersion != 1 && e.WSLVersion != 2 {
+			return nil, fmt.Errorf("entry %d: invalid wsl_version %d (must be 1 or 2)", i+1, e.WSLVersion)
+		}
+	}
+	return entries, nil
+}
+
+// Compare reports the status of each entry given the installed
+// distributions, keyed by name
+func Compare(entries []Entry, installed map[string]Installed) []EntryStatus {
+	statuses := make([]EntryStatus, 0, len(entries))
+	for _, e := range entries {
+		s := EntryStatus{Entry: e, Status: StatusMissing}
+		if d, ok := installed[e.Name]; ok {
+			s.State = d.State
+			s.Status = StatusOK
+			if d.DistroVersion != "" && d.DistroVersion != e.Version {
+				s.Status = StatusVersionMismatch
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
@@ -3,12 +3,63 @@ package checksum
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
+// ErrMismatch is returned when data does not match the expected checksum
+var ErrMismatch = errors.New("checksum mismatch")
+
+// ChecksumReader computes the SHA256 checksum of the data read through it
+type ChecksumReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+// NewChecksumReader wraps r so the checksum is available from Digest once
+// everything has been read
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{r: r, h: sha256.New()}
+}
+
+// Read reads from the underlying reader and hashes the bytes returned
+func (c *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.h.Write(p[:n])
+	return n, err
+}
+
+// Digest returns the hex SHA256 checksum of the data read so far
+func (c *ChecksumReader) Digest() string {
+	return hex.EncodeToString(c.h.Sum(nil))
+}
+
+// VerifyStream reads r to EOF and checks the data against the expected
+// SHA256 checksum. Wrap r in an io.TeeReader to hash data while it is
+// written elsewhere, e.g. during a download.
+func VerifyStream(r io.Reader, expectedSHA256 string) error {
+	if expectedSHA256 == "" {
+		return fmt.Errorf("no checksum provided for verification")
+	}
+
+	cr := NewChecksumReader(r)
+	if _, err := io.Copy(io.Discard, cr); err != nil {
+		return fmt.Errorf("failed to compute checksum: %w", err)
+	}
+
+	actual := cr.Digest()
+	expected := strings.ToLower(strings.TrimSpace(expectedSHA256))
+	if actual != expected {
+		return fmt.Errorf("%w: got %s, expected %s", ErrMismatch, actual, expected)
+	}
+	return nil
+}
+
 // VerifyFile checks if a file matches the expected SHA256 checksum
 func VerifyFile(path, expectedSHA256 string) error {
 	if expectedSHA256 == "" {
@@ -21,19 +72,7 @@ func VerifyFile(path, expectedSHA256 string) error {
 	}
 	defer f.Close()
 
-	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return fmt.Errorf("failed to compute checksum: %w", err)
-	}
-
-	actual := hex.EncodeToString(h.Sum(nil))
-	expected := strings.ToLower(strings.TrimSpace(expectedSHA256))
-
-	if actual != expected {
-		return fmt.Errorf("checksum mismatch: got %s, expected %s", actual, expected)
-	}
-
-	return nil
+	return VerifyStream(f, expectedSHA256)
 }
 
 // ComputeFile computes the SHA256 checksum of a file
@@ -51,3 +90,22 @@ func ComputeFile(path string) (string, error) {
 
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
+
+// SidecarPath returns the path of the .sha256 file written next to path
+func SidecarPath(path string) string {
+	return path + ".sha256"
+}
+
+// WriteSidecar computes the SHA256 checksum of a file and writes it to a
+// .sha256 file next to it in sha256sum format. Returns the checksum.
+func WriteSidecar(path string) (string, error) {
+	sum, err := ComputeFile(path)
+	if err != nil {
+		return "", err
+	}
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+	if err := os.WriteFile(SidecarPath(path), []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("failed to write checksum file: %w", err)
+	}
+	return sum, nil
+}
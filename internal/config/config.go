@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/yuanjua/autowsl/internal/hooks"
+)
+
+// Config holds user defaults loaded from the autowsl config file
+type Config struct {
+	DefaultWSLVersion  int    `mapstructure:"default_wsl_version"`
+	DefaultInstallPath string `mapstructure:"default_install_path"` // Root directory for new distributions
+	PlaybooksDir       string `mapstructure:"playbooks_dir"`        // Directory searched for playbook aliases
+	KeepTar            bool   `mapstructure:"keep_tar"`
+	MaxRetries         int    `mapstructure:"max_retries"`
+	TempDir            string `mapstructure:"temp_dir"`
+	HistoryLimit       int    `mapstructure:"history_limit"` // Entries kept in ~/.autowsl/history.json
+	NoCleanup          bool   `mapstructure:"no_cleanup"`    // Keep the temp directory after a run
+	CatalogURL         string `mapstructure:"catalog_url"`   // Remote JSON catalog merged into the built-in one
+
+	Hooks hooks.Hooks `mapstructure:"hooks"` // Windows-side commands run around provision; edit the file to change
+}
+
+// keyKinds lists the supported config keys and the type of their values
+var keyKinds = map[string]string{
+	"default_wsl_version":  "int",
+	"default_install_path": "string",
+	"playbooks_dir":        "string",
+	"keep_tar":             "bool",
+	"max_retries":          "int",
+	"temp_dir":             "string",
+	"history_limit":        "int",
+	"no_cleanup":           "bool",
+	"catalog_url":          "string",
+}
+
+// EnvPrefix prefixes the environment variables that override config keys,
+// e.g. AUTOWSL_MAX_RETRIES for max_retries
+const EnvPrefix = "AUTOWSL"
+
+// envAliases lists shorter environment variable names accepted for some keys
+var envAliases = map[string][]string{
+	"default_wsl_version":  {"AUTOWSL_WSL_VERSION"},
+	"default_install_path": {"AUTOWSL_INSTALL_PATH"},
+}
+
+// Path returns the location of the config file:
+// %APPDATA%\autowsl\config.yml on Windows, ~/.autowsl.yml elsewhere
+func Path() string {
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "autowsl", "config.yml")
+		}
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".autowsl.yml")
+}
+
+// Keys returns the supported config keys in sorted order
+func Keys() []string {
+	keys := make([]string, 0, len(keyKinds))
+	for k := range keyKinds {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvNames returns the environment variables that override a key, the
+// canonical AUTOWSL_<KEY> name first
+func EnvNames(key string) []string {
+	return append([]string{EnvPrefix + "_" + strings.ToUpper(key)}, envAliases[key]...)
+}
+
+// Init loads the config file into the global Viper instance, replacing what
+// an earlier call loaded. Environment variables override the file. A missing
+// config file is not an error.
+func Init() error {
+	viper.Reset()
+	viper.SetConfigFile(Path())
+	viper.SetConfigType("yaml")
+	viper.SetDefault("default_wsl_version", 2)
+	viper.SetDefault("max_retries", 3)
+	viper.SetDefault("history_limit", 1000)
+
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+	for key := range keyKinds {
+		if err := viper.BindEnv(append([]string{key}, EnvNames(key)...)...); err != nil {
+			return fmt.Errorf("failed to bind environment for '%s': %w", key, err)
+		}
+	}
+
+	if _, err := os.Stat(Path()); os.IsNotExist(err) {
+		return nil
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file '%s': %w", Path(), err)
+	}
+	return nil
+}
+
+// Get returns the loaded configuration
+func Get() Config {
+	var cfg Config
+	_ = viper.Unmarshal(&cfg)
+	return cfg
+}
+
+// GetString returns the loaded value of a key as a string
+func GetString(key string) string {
+	return viper.GetString(key)
+}
+
+// IsSet reports whether a key was set in the config file or the environment
+func IsSet(key string) bool {
+	if viper.InConfig(key) {
+		return true
+	}
+	for _, name := range EnvNames(key) {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetValue reads a single key directly from the config file
+func GetValue(key string) (string, error) {
+	if _, ok := keyKinds[key]; !ok {
+		return "", fmt.Errorf("unknown config key '%s' (valid keys: %v)", key, Keys())
+	}
+
+	v, err := readFile()
+	if err != nil {
+		return "", err
+	}
+	if !v.IsSet(key) {
+		return "", fmt.Errorf("config key '%s' is not set", key)
+	}
+	return v.GetString(key), nil
+}
+
+// SetValue writes a single key directly to the config file
+func SetValue(key, value string) error {
+	kind, ok := keyKinds[key]
+	if !ok {
+		return fmt.Errorf("unknown config key '%s' (valid keys: %v)", key, Keys())
+	}
+
+	v, err := readFile()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for '%s': %s (expected a number)", key, value)
+		}
+		v.Set(key, n)
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for '%s': %s (expected true or false)", key, value)
+		}
+		v.Set(key, b)
+	default:
+		v.Set(key, value)
+	}
+
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write config file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// readFile loads only the config file, without defaults, into a fresh Viper instance
+func readFile() (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(Path())
+	v.SetConfigType("yaml")
+	if _, err := os.Stat(Path()); os.IsNotExist(err) {
+		return v, nil
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file '%s': %w", Path(), err)
+	}
+	return v, nil
+}
@@ -4,11 +4,26 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 //go:embed distros-winget.json
 var distrosJSON []byte
 
+var (
+	// externalCatalog holds entries loaded with LoadCatalog
+	externalCatalog []Distro
+	// remoteCatalog holds entries set with SetRemoteCatalog
+	remoteCatalog []Distro
+	// replaceEmbedded makes GetAllDistros ignore the embedded catalog
+	replaceEmbedded bool
+)
+
 // Distro represents a WSL distribution
 type Distro struct {
 	Group        string `json:"group"`
@@ -22,19 +37,199 @@ type Distro struct {
 // DistroList represents the JSON structure
 type DistroList struct {
 	Distributions []Distro `json:"distributions"`
+
+	Source string `json:"source,omitempty"` // URL a cached remote catalog was downloaded from
 }
 
-// GetAllDistros returns all available WSL distributions from embedded JSON
+// GetAllDistros returns all available WSL distributions from embedded JSON,
+// merged with a remote catalog and an external catalog loaded with
+// LoadCatalog, in that order of priority. A replacing external catalog
+// hides both the embedded and the remote entries.
 func GetAllDistros() []Distro {
-	var distroList DistroList
+	var distros []Distro
 
-	if err := json.Unmarshal(distrosJSON, &distroList); err != nil {
-		// Fallback to empty list if JSON parsing fails
-		fmt.Printf("Warning: Failed to parse distros.json: %v\n", err)
+	if !replaceEmbedded {
+		var distroList DistroList
+		if err := json.Unmarshal(distrosJSON, &distroList); err != nil {
+			// Fallback to empty list if JSON parsing fails
+			fmt.Printf("Warning: Failed to parse distros.json: %v\n", err)
+		} else {
+			distros = distroList.Distributions
+		}
+		distros = mergeCatalog(distros, remoteCatalog)
+	}
+
+	distros = mergeCatalog(distros, externalCatalog)
+	if distros == nil {
 		return []Distro{}
 	}
+	return distros
+}
+
+// mergeCatalog adds entries to distros; entries override existing ones with
+// the same version name
+func mergeCatalog(distros, entries []Distro) []Distro {
+	if len(entries) == 0 {
+		return distros
+	}
+
+	index := make(map[string]int, len(distros))
+	for i, d := range distros {
+		index[d.Version] = i
+	}
+	for _, d := range entries {
+		if i, ok := index[d.Version]; ok {
+			distros[i] = d
+		} else {
+			index[d.Version] = len(distros)
+			distros = append(distros, d)
+		}
+	}
+	return distros
+}
+
+// LoadCatalog loads an external catalog file with the same schema as the
+// embedded distros-winget.json. Its entries are merged into the embedded
+// catalog, or replace it entirely when replace is true.
+func LoadCatalog(path string, replace bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog '%s': %w", path, err)
+	}
 
-	return distroList.Distributions
+	distros, err := parseCatalog(data, path)
+	if err != nil {
+		return err
+	}
+
+	externalCatalog = distros
+	replaceEmbedded = replace
+	return nil
+}
+
+// parseCatalog parses and checks catalog JSON; source names it in errors
+func parseCatalog(data []byte, source string) ([]Distro, error) {
+	var distroList DistroList
+	if err := json.Unmarshal(data, &distroList); err != nil {
+		return nil, fmt.Errorf("invalid catalog '%s': %w", source, err)
+	}
+	if len(distroList.Distributions) == 0 {
+		return nil, fmt.Errorf("invalid catalog '%s': no entries in \"distributions\"", source)
+	}
+
+	for i, d := range distroList.Distributions {
+		if d.Group == "" {
+			return nil, fmt.Errorf("invalid catalog '%s': entry %d is missing \"group\"", source, i+1)
+		}
+		if d.Version == "" {
+			return nil, fmt.Errorf("invalid catalog '%s': entry %d (%s) is missing \"version\"", source, i+1, d.Group)
+		}
+		if d.PackageID == "" && d.URL == "" {
+			return nil, fmt.Errorf("invalid catalog '%s': entry %d (%s) needs a \"packageId\" or \"url\"", source, i+1, d.Version)
+		}
+	}
+	return distroList.Distributions, nil
+}
+
+// RemoteCatalogFile is the name of the cached remote catalog in its cache directory
+const RemoteCatalogFile = "distros-remote.json"
+
+// DefaultCacheDir returns where the remote catalog is cached: ~/.autowsl
+func DefaultCacheDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".autowsl")
+}
+
+// remoteCatalogClient downloads remote catalogs
+var remoteCatalogClient = &http.Client{Timeout: 30 * time.Second}
+
+// FetchRemoteCatalog returns the entries of the JSON catalog at url, which
+// uses the schema of the embedded catalog. A copy in cacheDir younger than
+// maxAge that was downloaded from the same url is used instead of
+// downloading; maxAge 0 always downloads. A new download replaces the cached
+// copy only when it is a valid catalog.
+func FetchRemoteCatalog(url string, cacheDir string, maxAge time.Duration) ([]Distro, error) {
+	cachePath := filepath.Join(cacheDir, RemoteCatalogFile)
+	if info, err := os.Stat(cachePath); err == nil && maxAge > 0 && time.Since(info.ModTime()) < maxAge {
+		if distros, ok := readCachedCatalog(cachePath, url); ok {
+			return distros, nil
+		}
+	}
+
+	resp, err := remoteCatalogClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download catalog '%s': %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download catalog '%s': HTTP %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download catalog '%s': %w", url, err)
+	}
+
+	distros, err := parseCatalog(data, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create catalog cache directory: %w", err)
+	}
+	data, err = json.MarshalIndent(DistroList{Distributions: distros, Source: url}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to cache catalog: %w", err)
+	}
+	tmpPath := cachePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to cache catalog: %w", err)
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to cache catalog: %w", err)
+	}
+	return distros, nil
+}
+
+// CachedRemoteCatalog returns the catalog FetchRemoteCatalog cached in
+// cacheDir for url regardless of its age, and when it was downloaded
+func CachedRemoteCatalog(url, cacheDir string) ([]Distro, time.Time, bool) {
+	cachePath := filepath.Join(cacheDir, RemoteCatalogFile)
+	info, err := os.Stat(cachePath)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	distros, ok := readCachedCatalog(cachePath, url)
+	return distros, info.ModTime(), ok
+}
+
+// readCachedCatalog returns the cached catalog at path if it is valid and was
+// downloaded from url
+func readCachedCatalog(path, url string) ([]Distro, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var distroList DistroList
+	if err := json.Unmarshal(data, &distroList); err != nil || distroList.Source != url {
+		return nil, false
+	}
+	distros, err := parseCatalog(data, path)
+	return distros, err == nil
+}
+
+// SetRemoteCatalog merges entries from FetchRemoteCatalog into the catalog;
+// they override embedded entries with the same version name
+func SetRemoteCatalog(distros []Distro) {
+	remoteCatalog = distros
+}
+
+// ResetCatalog discards any remote or external catalog and restores the embedded one
+func ResetCatalog() {
+	externalCatalog = nil
+	remoteCatalog = nil
+	replaceEmbedded = false
 }
 
 // FindDistroByVersion finds a distribution by its version name
@@ -50,6 +245,58 @@ func FindDistroByVersion(version string) (*Distro, error) {
 	return nil, fmt.Errorf("distribution '%s' not found", version)
 }
 
+// FindDistroByVersionFuzzy returns the distributions whose version name
+// contains version, ignoring case
+func FindDistroByVersionFuzzy(version string) ([]Distro, error) {
+	query := strings.ToLower(strings.TrimSpace(version))
+	if query == "" {
+		return nil, fmt.Errorf("empty distribution version")
+	}
+
+	var result []Distro
+	for _, d := range GetAllDistros() {
+		if strings.Contains(strings.ToLower(d.Version), query) {
+			result = append(result, d)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no distribution matches '%s'", version)
+	}
+	return result, nil
+}
+
+// FindDistroByPackageID finds a distribution by its winget package ID, ignoring case
+func FindDistroByPackageID(packageID string) (*Distro, error) {
+	for _, d := range GetAllDistros() {
+		if d.PackageID != "" && strings.EqualFold(d.PackageID, packageID) {
+			return &d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("package ID '%s' not found", packageID)
+}
+
+// Search returns the distributions whose group, version, or package ID
+// contains query (case-insensitive). A non-empty group limits the results to
+// that group.
+func Search(query, group string) []Distro {
+	query = strings.ToLower(query)
+	var result []Distro
+
+	for _, d := range GetAllDistros() {
+		if group != "" && !strings.EqualFold(d.Group, group) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(d.Group), query) ||
+			strings.Contains(strings.ToLower(d.Version), query) ||
+			strings.Contains(strings.ToLower(d.PackageID), query) {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
+
 // GetDistrosByGroup returns all distributions in a specific group
 func GetDistrosByGroup(group string) []Distro {
 	distros := GetAllDistros()
@@ -0,0 +1,32 @@
+package distro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetGroups returns the distribution groups of the catalog (Ubuntu, Debian,
+// ...) in the order they first appear
+func GetGroups() []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, d := range GetAllDistros() {
+		if d.Group == "" || seen[d.Group] {
+			continue
+		}
+		seen[d.Group] = true
+		groups = append(groups, d.Group)
+	}
+	return groups
+}
+
+// FindGroup returns the catalog group whose name matches name, ignoring case
+func FindGroup(name string) (string, error) {
+	groups := GetGroups()
+	for _, g := range groups {
+		if strings.EqualFold(g, name) {
+			return g, nil
+		}
+	}
+	return "", fmt.Errorf("distribution group '%s' not found (available: %s)", name, strings.Join(groups, ", "))
+}
@@ -0,0 +1,71 @@
+package distro
+
+import (
+	"sort"
+	"strings"
+)
+
+// AutoCorrectScore is the fuzzy score above which a single match is assumed to
+// be what the user meant
+const AutoCorrectScore = 85
+
+// Match is a catalog entry and how closely it matches a query (0-100)
+type Match struct {
+	Distro Distro
+	Score  int
+}
+
+// ClosestMatches returns up to n catalog entries ranked by fuzzy similarity of
+// their version name or package ID to query, best first
+func ClosestMatches(query string, n int) []Match {
+	var matches []Match
+	for _, d := range GetAllDistros() {
+		score := fuzzyScore(query, d.Version)
+		if s := fuzzyScore(query, d.PackageID); s > score {
+			score = s
+		}
+		matches = append(matches, Match{Distro: d, Score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// fuzzyScore rates how similar query is to candidate from 0 (nothing in
+// common) to 100 (equal ignoring case), based on the Levenshtein distance
+func fuzzyScore(query, candidate string) int {
+	a := []rune(strings.ToLower(strings.TrimSpace(query)))
+	b := []rune(strings.ToLower(candidate))
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 100 - levenshtein(a, b)*100/longest
+}
+
+// levenshtein returns the number of single-rune edits that turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -1,12 +1,15 @@
 package downloader
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/yuanjua/autowsl/internal/checksum"
 	"github.com/yuanjua/autowsl/internal/distro"
@@ -14,18 +17,29 @@ import (
 
 // Downloader handles downloading WSL distributions
 type Downloader struct {
+	RetryConfig
 	client         *http.Client
 	VerifyChecksum bool // Whether to verify checksums (default: warn if mismatch)
+
+	progressOut io.Writer
 }
 
 // New creates a new Downloader instance
 func New() *Downloader {
 	return &Downloader{
+		RetryConfig:    DefaultRetryConfig(),
 		client:         &http.Client{},
 		VerifyChecksum: false, // Default to warn-only mode
+		progressOut:    os.Stdout,
 	}
 }
 
+// SetProgressOutput sets where download progress is printed; io.Discard
+// hides it
+func (d *Downloader) SetProgressOutput(w io.Writer) {
+	d.progressOut = w
+}
+
 // Download downloads a distribution to the current directory
 func (d *Downloader) Download(dist distro.Distro) error {
 	// Get the filename from the URL
@@ -41,7 +55,7 @@ func (d *Downloader) Download(dist distro.Distro) error {
 
 	fmt.Printf("Downloading to: %s\n", filepath)
 
-	return d.downloadToFile(dist.URL, filepath)
+	return d.downloadToFile(dist.URL, filepath, "")
 }
 
 // DownloadToDir downloads a distribution to a specific directory and returns the file path
@@ -56,77 +70,169 @@ func (d *Downloader) DownloadToDir(dist distro.Distro, dir string) (string, erro
 
 	filepath := filepath.Join(dir, filename)
 
-	// Always download fresh - remove any existing file first
-	if _, err := os.Stat(filepath); err == nil {
-		os.Remove(filepath)
-	}
-
-	if err := d.downloadToFile(dist.URL, filepath); err != nil {
-		return "", err
-	}
-
-	// Verify checksum if provided
-	if dist.SHA256 != "" {
-		fmt.Println("Verifying checksum...")
-		if err := checksum.VerifyFile(filepath, dist.SHA256); err != nil {
-			if d.VerifyChecksum {
-				// Strict mode: fail on mismatch
-				os.Remove(filepath)
-				return "", fmt.Errorf("checksum verification failed: %w", err)
-			} else {
-				// Warn mode: continue but alert user
-				fmt.Printf("Warning: %v\n", err)
-				fmt.Println("Continuing anyway (use --verify-checksum to enforce)")
-			}
-		} else {
-			fmt.Println("Checksum verified successfully")
+	// An existing file is treated as a partial download and resumed if possible.
+	// A known checksum is verified while the data is written.
+	err := d.downloadToFile(dist.URL, filepath, dist.SHA256)
+	switch {
+	case errors.Is(err, checksum.ErrMismatch):
+		if d.VerifyChecksum {
+			// Strict mode: fail on mismatch
+			os.Remove(filepath)
+			return "", fmt.Errorf("checksum verification failed: %w", err)
 		}
-	} else {
+		// Warn mode: continue but alert user
+		fmt.Printf("Warning: %v\n", err)
+		fmt.Println("Continuing anyway (use --verify-checksum to enforce)")
+	case err != nil:
+		return "", err
+	case dist.SHA256 != "":
+		fmt.Println("Checksum verified successfully")
+	default:
 		fmt.Println("Warning: No checksum available for this distribution")
 	}
 
 	return filepath, nil
 }
 
-// downloadToFile downloads from URL to a specific file path
-func (d *Downloader) downloadToFile(url, filepath string) error {
+// downloadToFile downloads from URL to a specific file path, resuming a
+// partial file when the server supports range requests. When expectedSHA256
+// is set the file is hashed as it is written and a mismatch returns an error
+// wrapping checksum.ErrMismatch.
+func (d *Downloader) downloadToFile(url, filepath, expectedSHA256 string) error {
+	offset, remoteSize := d.resumeOffset(url, filepath)
+	if offset > 0 && offset == remoteSize {
+		fmt.Println("File already fully downloaded, skipping")
+		if expectedSHA256 != "" {
+			return checksum.VerifyFile(filepath, expectedSHA256)
+		}
+		return nil
+	}
 
-	// Create the file
-	out, err := os.Create(filepath)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create file '%s': %w", filepath, err)
+		return fmt.Errorf("failed to create request for '%s': %w", url, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
-	defer out.Close()
 
 	// Send GET request
-	resp, err := d.client.Get(url)
+	resp, err := DoWithRetry(d.client, req, d.RetryConfig)
 	if err != nil {
 		return fmt.Errorf("failed to download from '%s': %w", url, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		fmt.Printf("Resuming download at %.2f MB\n", float64(offset)/1024/1024)
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file doesn't match what the server has; start over
+		fmt.Println("Warning: server rejected resume request, restarting download")
+		resp.Body.Close()
+		os.Remove(filepath)
+		return d.downloadToFile(url, filepath, expectedSHA256)
+	case resp.StatusCode == http.StatusOK:
+		if offset > 0 {
+			fmt.Println("Warning: server ignored resume request, restarting download")
+		}
+		offset = 0
+	default:
 		return fmt.Errorf("failed to download from '%s': HTTP %s", url, resp.Status)
 	}
 
+	// Open the file, appending when resuming
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	out, err := os.OpenFile(filepath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file '%s': %w", filepath, err)
+	}
+	defer out.Close()
+
 	// Get the total size for progress
 	totalSize := resp.ContentLength
+	if totalSize > 0 {
+		totalSize += offset
+	}
 
 	// Create progress writer
 	counter := &ProgressWriter{
-		Total:  totalSize,
-		Writer: out,
+		Total:      totalSize,
+		Downloaded: offset,
+		LastPrint:  offset,
+		Writer:     out,
+		Output:     d.progressOut,
 	}
 
-	// Copy the data with progress
-	_, err = io.Copy(counter, resp.Body)
-	if err != nil {
+	if expectedSHA256 == "" {
+		// Copy the data with progress
+		if _, err := io.Copy(counter, resp.Body); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		counter.finish()
+		return nil
+	}
+
+	// Hash the data on its way to the file; a resumed download first hashes
+	// the bytes already on disk
+	var stream io.Reader = io.TeeReader(resp.Body, counter)
+	if offset > 0 {
+		partial, err := os.Open(filepath)
+		if err != nil {
+			return fmt.Errorf("failed to read partial download: %w", err)
+		}
+		defer partial.Close()
+		stream = io.MultiReader(io.LimitReader(partial, offset), stream)
+	}
+	err = checksum.VerifyStream(stream, expectedSHA256)
+	counter.finish()
+	if err != nil && !errors.Is(err, checksum.ErrMismatch) {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	return err
+}
 
-	fmt.Println() // New line after progress
+// resumeOffset returns the number of bytes already present in a partial
+// download that can be resumed (0 if the download must start from scratch)
+// along with the remote file size, if known. Partial files that cannot be
+// resumed are removed.
+func (d *Downloader) resumeOffset(url, filepath string) (int64, int64) {
+	info, err := os.Stat(filepath)
+	if err != nil || info.Size() == 0 {
+		return 0, 0
+	}
+	size := info.Size()
 
-	return nil
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		os.Remove(filepath)
+		return 0, 0
+	}
+	resp, err := DoWithRetry(d.client, req, d.RetryConfig)
+	if err != nil {
+		fmt.Printf("Warning: could not check resume support (%v), restarting download\n", err)
+		os.Remove(filepath)
+		return 0, 0
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Accept-Ranges") != "bytes" {
+		fmt.Println("Warning: server does not support resuming downloads, restarting download")
+		os.Remove(filepath)
+		return 0, 0
+	}
+
+	if resp.ContentLength > 0 && size > resp.ContentLength {
+		// Larger than the remote file means the partial is corrupt
+		fmt.Println("Warning: partial download is larger than the remote file, restarting download")
+		os.Remove(filepath)
+		return 0, 0
+	}
+
+	return size, resp.ContentLength
 }
 
 // getFilename extracts filename from URL
@@ -140,7 +246,11 @@ func (d *Downloader) getFilename(url string) string {
 		return fmt.Sprintf("%s.appxbundle", name)
 	}
 
-	// For direct URLs, extract the filename
+	// For direct URLs, extract the filename (without a query string, as
+	// signed URLs on internal servers often have one)
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
 	parts := strings.Split(url, "/")
 	filename := parts[len(parts)-1]
 
@@ -152,29 +262,55 @@ func (d *Downloader) getFilename(url string) string {
 	return filename
 }
 
-// ProgressWriter tracks download progress
+// speedWindow is how far back ProgressWriter looks when computing the current speed
+const speedWindow = 5 * time.Second
+
+// progressSample records how many bytes had been written at a point in time
+type progressSample struct {
+	bytes int64
+	at    time.Time
+}
+
+// ProgressWriter tracks download progress. It is safe for concurrent use.
 type ProgressWriter struct {
 	Total        int64
 	Downloaded   int64
 	Writer       io.Writer
 	LastPrint    int64
 	PrintEveryMB int64
+	Output       io.Writer // Where progress is printed (nil = os.Stdout)
+	Silent       bool      // Don't print progress at all, e.g. with --quiet
+
+	mu        sync.Mutex
+	startTime time.Time
+	samples   []progressSample // rolling window for instantaneous speed
 }
 
 func (pw *ProgressWriter) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	now := time.Now()
+	if pw.startTime.IsZero() {
+		// Bytes from a resumed download don't count towards the speed
+		pw.startTime = now
+		pw.samples = append(pw.samples, progressSample{bytes: pw.Downloaded, at: now})
+	}
+
 	n, err := pw.Writer.Write(p)
 	if err != nil {
 		return n, err
 	}
 
 	pw.Downloaded += int64(n)
+	pw.addSample(now)
 
 	// Print progress every MB or at the end
 	if pw.PrintEveryMB == 0 {
 		pw.PrintEveryMB = 1024 * 1024 // 1 MB
 	}
 
-	if pw.Downloaded-pw.LastPrint >= pw.PrintEveryMB || pw.Downloaded == pw.Total {
+	if !pw.Silent && (pw.Downloaded-pw.LastPrint >= pw.PrintEveryMB || pw.Downloaded == pw.Total) {
 		pw.printProgress()
 		pw.LastPrint = pw.Downloaded
 	}
@@ -182,14 +318,81 @@ func (pw *ProgressWriter) Write(p []byte) (int, error) {
 	return n, nil
 }
 
+// addSample records the current byte count and drops samples older than speedWindow
+func (pw *ProgressWriter) addSample(now time.Time) {
+	pw.samples = append(pw.samples, progressSample{bytes: pw.Downloaded, at: now})
+
+	cutoff := now.Add(-speedWindow)
+	drop := 0
+	// Always keep at least two samples so there is something to measure against
+	for drop < len(pw.samples)-2 && pw.samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	pw.samples = pw.samples[drop:]
+}
+
+// speed returns the transfer rate in bytes per second over the rolling window
+func (pw *ProgressWriter) speed() float64 {
+	if len(pw.samples) < 2 {
+		return 0
+	}
+	first, last := pw.samples[0], pw.samples[len(pw.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(last.bytes-first.bytes) / elapsed
+}
+
+// SetOutput sets where progress is printed
+func (pw *ProgressWriter) SetOutput(w io.Writer) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.Output = w
+}
+
+// output returns the writer progress is printed to
+func (pw *ProgressWriter) output() io.Writer {
+	if pw.Output == nil {
+		return os.Stdout
+	}
+	return pw.Output
+}
+
 func (pw *ProgressWriter) printProgress() {
-	if pw.Total > 0 {
-		percentage := float64(pw.Downloaded) / float64(pw.Total) * 100
-		downloadedMB := float64(pw.Downloaded) / 1024 / 1024
-		totalMB := float64(pw.Total) / 1024 / 1024
-		fmt.Printf("\rProgress: %.2f%% (%.2f MB / %.2f MB)", percentage, downloadedMB, totalMB)
+	// Trailing spaces clear leftovers when the line gets shorter
+	fmt.Fprintf(pw.output(), "\r%s    ", FormatProgress(pw.Downloaded, pw.Total, pw.speed()))
+}
+
+// finish ends the progress line once the download is complete
+func (pw *ProgressWriter) finish() {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if !pw.Silent {
+		fmt.Fprintln(pw.output())
+	}
+}
+
+// FormatProgress renders a progress line. Percentage and ETA are omitted when
+// total is unknown (<= 0), and speed is omitted until it can be measured.
+func FormatProgress(downloaded, total int64, bytesPerSec float64) string {
+	const mb = 1024 * 1024
+	var line string
+	if total > 0 {
+		percentage := float64(downloaded) / float64(total) * 100
+		line = fmt.Sprintf("Progress: %.1f%% (%.0f MB / %.0f MB)", percentage, float64(downloaded)/mb, float64(total)/mb)
 	} else {
-		downloadedMB := float64(pw.Downloaded) / 1024 / 1024
-		fmt.Printf("\rDownloaded: %.2f MB", downloadedMB)
+		line = fmt.Sprintf("Downloaded: %.0f MB", float64(downloaded)/mb)
+	}
+
+	if bytesPerSec <= 0 {
+		return line
+	}
+	line += fmt.Sprintf(" @ %.1f MB/s", bytesPerSec/mb)
+
+	if total > 0 && downloaded < total {
+		eta := time.Duration(float64(total-downloaded) / bytesPerSec * float64(time.Second))
+		line += fmt.Sprintf(" ETA %s", eta.Round(time.Second))
 	}
+	return line
 }
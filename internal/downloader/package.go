@@ -0,0 +1,122 @@
+package downloader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/yuanjua/autowsl/internal/distro"
+)
+
+// PackageType describes what a downloaded file contains
+type PackageType string
+
+const (
+	PackageUnknown PackageType = ""
+	PackageAppx    PackageType = "appx" // .appx/.appxbundle that still needs extracting
+	PackageTar     PackageType = "tar"  // rootfs tarball that can be imported directly
+)
+
+// appxContentTypes and tarContentTypes map Content-Type headers to package types.
+// Generic types such as application/octet-stream are left to the file extension.
+var (
+	appxContentTypes = []string{"application/zip", "application/x-zip-compressed", "application/vnd.ms-appx", "application/appx", "application/appxbundle", "application/msix", "application/msixbundle"}
+	tarContentTypes  = []string{"application/x-tar", "application/gzip", "application/x-gzip", "application/x-gtar", "application/x-xz", "application/x-compressed-tar"}
+)
+
+// DetectPackageType decides whether a file is an appx package or a rootfs
+// tarball. A specific Content-Type wins over the file extension.
+func DetectPackageType(filename, contentType string) PackageType {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		for _, t := range appxContentTypes {
+			if mediaType == t {
+				return PackageAppx
+			}
+		}
+		for _, t := range tarContentTypes {
+			if mediaType == t {
+				return PackageTar
+			}
+		}
+	}
+
+	name := strings.ToLower(filename)
+	for _, ext := range []string{".appx", ".appxbundle", ".msix", ".msixbundle", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			return PackageAppx
+		}
+	}
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".tar.xz", ".txz"} {
+		if strings.HasSuffix(name, ext) {
+			return PackageTar
+		}
+	}
+	return PackageUnknown
+}
+
+// sniffPackageType looks at a file's magic bytes: zip for appx packages,
+// gzip/xz/ustar for tarballs
+func sniffPackageType(path string) PackageType {
+	f, err := os.Open(path)
+	if err != nil {
+		return PackageUnknown
+	}
+	defer f.Close()
+
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(f, header)
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return PackageAppx
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}), bytes.HasPrefix(header, []byte("\xfd7zXZ\x00")):
+		return PackageTar
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return PackageTar
+	}
+	return PackageUnknown
+}
+
+// contentType asks the server for a URL's Content-Type without downloading it
+func (d *Downloader) contentType(url string) string {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return ""
+	}
+	resp, err := DoWithRetry(d.client, req, d.RetryConfig)
+	if err != nil {
+		return ""
+	}
+	resp.Body.Close()
+	return resp.Header.Get("Content-Type")
+}
+
+// DownloadURL downloads an arbitrary package URL into dir and reports whether
+// it is an appx package or a rootfs tarball. The type comes from the
+// Content-Type header and file extension, falling back to the file contents.
+func (d *Downloader) DownloadURL(url, dir string) (string, PackageType, error) {
+	// Use the raw URL name: getFilename assumes .appxbundle when there is no extension
+	base := url
+	if i := strings.IndexAny(base, "?#"); i >= 0 {
+		base = base[:i]
+	}
+	kind := DetectPackageType(base[strings.LastIndex(base, "/")+1:], d.contentType(url))
+
+	path, err := d.DownloadToDir(distro.Distro{Version: url, URL: url}, dir)
+	if err != nil {
+		return "", PackageUnknown, err
+	}
+
+	if kind == PackageUnknown {
+		kind = sniffPackageType(path)
+	}
+	if kind == PackageUnknown {
+		return path, kind, fmt.Errorf("cannot tell whether '%s' is an appx package or a rootfs tarball", url)
+	}
+	return path, kind, nil
+}
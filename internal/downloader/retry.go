@@ -0,0 +1,75 @@
+package downloader
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RetryConfig controls how transient HTTP failures are retried
+type RetryConfig struct {
+	MaxAttempts  int           // Total attempts including the first one (<= 1 disables retries)
+	InitialDelay time.Duration // Delay before the first retry, doubled on each attempt
+	MaxDelay     time.Duration // Upper bound for the delay between attempts
+}
+
+// DefaultRetryConfig returns the retry settings used when none are specified
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:  4,
+		InitialDelay: time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+}
+
+// DoWithRetry sends an HTTP request, retrying on network errors and 5xx
+// responses with exponential backoff. 4xx responses are returned immediately
+// since retrying them will not help.
+func DoWithRetry(client *http.Client, req *http.Request, cfg RetryConfig) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return retryWithBackoff(cfg, func() (*http.Response, error) {
+		return client.Do(req.Clone(req.Context()))
+	})
+}
+
+// retryWithBackoff calls send until it succeeds, returns a non-retryable
+// response, or the configured attempts are exhausted
+func retryWithBackoff(cfg RetryConfig, send func() (*http.Response, error)) (*http.Response, error) {
+	attempts := cfg.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := cfg.InitialDelay
+
+	var resp *http.Response
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		resp, err = send()
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		// Retryable failure: discard this response and wait before trying again
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = "HTTP " + resp.Status
+			resp.Body.Close()
+		}
+		fmt.Printf("Warning: request failed (%s), retrying in %s (attempt %d/%d)\n", reason, delay, attempt+1, attempts)
+
+		time.Sleep(delay)
+		delay *= 2
+		if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+	}
+
+	return resp, err
+}
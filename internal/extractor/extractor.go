@@ -4,23 +4,32 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/yuanjua/autowsl/internal/system"
 )
 
-// ExtractAppx extracts the root filesystem tar file from an Appx/AppxBundle package
-func ExtractAppx(appxPath, outputDir string) (string, error) {
+// ExtractAppx extracts the root filesystem tar file from an Appx/AppxBundle
+// package. MSIX packages and bundles share the format and are handled the same way.
+func ExtractAppx(appxPath, outputDir string, opts ExtractOptions) (string, error) {
 	// Ensure output directory exists
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Detect host architecture
-	hostArch := system.GetHostArchitecture()
-	fmt.Printf("Host architecture: %s\n", hostArch)
+	// Detect host architecture unless it is overridden
+	hostArch := opts.ForceArch
+	if hostArch == "" {
+		hostArch = system.GetHostArchitecture()
+		fmt.Printf("Host architecture: %s\n", hostArch)
+	} else {
+		fmt.Printf("Target architecture: %s (forced)\n", hostArch)
+	}
 
 	// Open the appx file as a zip archive
 	reader, err := zip.OpenReader(appxPath)
@@ -36,7 +45,7 @@ func ExtractAppx(appxPath, outputDir string) (string, error) {
 		lowerName := strings.ToLower(file.Name)
 
 		// Skip incompatible architectures
-		if system.ShouldSkipArchitecture(lowerName) {
+		if system.ShouldSkipArchitectureFor(hostArch, lowerName) {
 			continue
 		}
 
@@ -44,7 +53,7 @@ func ExtractAppx(appxPath, outputDir string) (string, error) {
 			// Extract this file
 			extractedPath := filepath.Join(outputDir, filepath.Base(file.Name))
 
-			if err := extractFile(file, extractedPath); err != nil {
+			if err := extractFile(file, extractedPath, opts.Progress); err != nil {
 				return "", fmt.Errorf("failed to extract tar file: %w", err)
 			}
 
@@ -53,21 +62,21 @@ func ExtractAppx(appxPath, outputDir string) (string, error) {
 		}
 	}
 
-	// If we didn't find install.tar.gz, look for .appx files inside (AppxBundle case)
+	// If we didn't find install.tar.gz, look for .appx or .msix files inside (bundle case)
 	if tarFilePath == "" {
-		// Collect all .appx files and prioritize matching architecture
+		// Collect all nested packages and prioritize matching architecture
 		var matchingAppx, genericAppx *zip.File
-		preferredSuffix := system.GetPreferredArchitectureSuffix()
+		preferredSuffix := system.PreferredArchitectureSuffix(hostArch)
 
 		for _, file := range reader.File {
 			lowerName := strings.ToLower(file.Name)
 
-			if !strings.HasSuffix(lowerName, ".appx") {
+			if !strings.HasSuffix(lowerName, ".appx") && !strings.HasSuffix(lowerName, ".msix") {
 				continue
 			}
 
 			// Skip incompatible architectures
-			if system.ShouldSkipArchitecture(lowerName) {
+			if system.ShouldSkipArchitectureFor(hostArch, lowerName) {
 				fmt.Printf("   Skipping incompatible: %s\n", file.Name)
 				continue
 			}
@@ -98,12 +107,12 @@ func ExtractAppx(appxPath, outputDir string) (string, error) {
 			// Extract the nested appx
 			nestedAppxPath := filepath.Join(outputDir, filepath.Base(selectedAppx.Name))
 
-			if err := extractFile(selectedAppx, nestedAppxPath); err != nil {
+			if err := extractFile(selectedAppx, nestedAppxPath, opts.Progress); err != nil {
 				return "", fmt.Errorf("failed to extract nested appx: %w", err)
 			}
 
 			// Recursively extract from the nested appx
-			tarFilePath, err = ExtractAppx(nestedAppxPath, outputDir)
+			tarFilePath, err = ExtractAppx(nestedAppxPath, outputDir, opts)
 			if err != nil {
 				return "", err
 			}
@@ -120,8 +129,9 @@ func ExtractAppx(appxPath, outputDir string) (string, error) {
 	return tarFilePath, nil
 }
 
-// extractFile extracts a single file from a zip archive
-func extractFile(zipFile *zip.File, destPath string) error {
+// extractFile extracts a single file from a zip archive, reporting progress
+// to progress when it is not nil
+func extractFile(zipFile *zip.File, destPath string, progress ProgressCallback) error {
 	// Open the file in the zip archive
 	rc, err := zipFile.Open()
 	if err != nil {
@@ -137,11 +147,116 @@ func extractFile(zipFile *zip.File, destPath string) error {
 	defer destFile.Close()
 
 	// Copy the contents
-	_, err = io.Copy(destFile, rc)
+	_, err = io.Copy(newProgressWriter(destFile, int64(zipFile.UncompressedSize64), progress), rc)
 	return err
 }
 
+// dryRun skips file-system changes made by CleanupTempDir.
+var dryRun bool
+
+// SetDryRun enables or disables dry-run mode for cleanup.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
 // CleanupTempDir removes the temporary extraction directory
 func CleanupTempDir(dir string) error {
+	if dryRun {
+		fmt.Printf("[dry-run] would remove %s\n", dir)
+		return nil
+	}
 	return os.RemoveAll(dir)
 }
+
+// TempDirName is the scratch directory name used for downloads and exports
+const TempDirName = ".autowsl_tmp"
+
+// LockFileName marks a temp directory as in use by a running import
+const LockFileName = ".autowsl.lock"
+
+// staleLockAge is how old a lockfile may get before it is treated as left
+// behind by a process that was killed
+const staleLockAge = 24 * time.Hour
+
+// AcquireLock marks dir as in use. The returned function removes the lock.
+func AcquireLock(dir string) (func(), error) {
+	if dryRun {
+		return func() {}, nil
+	}
+	lockPath := filepath.Join(dir, LockFileName)
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return nil, fmt.Errorf("failed to create lockfile: %w", err)
+	}
+	return func() { _ = os.Remove(lockPath) }, nil
+}
+
+// IsLocked reports whether dir holds a lockfile from a running import
+func IsLocked(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, LockFileName))
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) < staleLockAge
+}
+
+// FindTempDirs returns the .autowsl_tmp directories directly under root, or
+// anywhere below it when recursive is set. Unreadable subdirectories are skipped.
+func FindTempDirs(root string, recursive bool) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access '%s': %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("'%s' is not a directory", root)
+	}
+
+	if !recursive {
+		candidate := filepath.Join(root, TempDirName)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return []string{candidate}, nil
+		}
+		return nil, nil
+	}
+
+	var dirs []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			// Permission problems in one subtree shouldn't stop the scan
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && d.Name() == TempDirName {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan '%s': %w", root, err)
+	}
+	return dirs, nil
+}
+
+// DirSize returns the total size of the regular files below dir
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,59 @@
+package extractor
+
+import (
+	"io"
+	"sync"
+
+	"github.com/yuanjua/autowsl/internal/system"
+)
+
+// progressInterval is how many bytes are extracted between progress reports
+const progressInterval = 1024 * 1024
+
+// ProgressCallback receives the bytes extracted so far and the size of the
+// file being extracted
+type ProgressCallback func(bytesExtracted, totalBytes int64)
+
+// ExtractOptions controls ExtractAppx
+type ExtractOptions struct {
+	// Progress is called about every MB and once when a file is complete.
+	// Nil extracts silently.
+	Progress ProgressCallback
+
+	// ForceArch selects packages for this architecture instead of the
+	// host's. A non-native rootfs may not boot under WSL.
+	ForceArch system.HostArchitecture
+}
+
+// progressWriter counts the bytes written through it and reports them to a
+// callback. It is safe for concurrent use.
+type progressWriter struct {
+	w        io.Writer
+	total    int64
+	callback ProgressCallback
+
+	mu         sync.Mutex
+	written    int64
+	lastReport int64
+}
+
+// newProgressWriter wraps w, or returns it unchanged when callback is nil
+func newProgressWriter(w io.Writer, total int64, callback ProgressCallback) io.Writer {
+	if callback == nil {
+		return w
+	}
+	return &progressWriter{w: w, total: total, callback: callback}
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.written += int64(n)
+	if pw.written-pw.lastReport >= progressInterval || pw.written == pw.total {
+		pw.lastReport = pw.written
+		pw.callback(pw.written, pw.total)
+	}
+	return n, err
+}
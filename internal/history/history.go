@@ -0,0 +1,188 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries is the number of entries kept when no limit is configured
+const DefaultMaxEntries = 1000
+
+// MaxEntries caps the history file; the oldest entries are dropped first
+var MaxEntries = DefaultMaxEntries
+
+// mu serializes writes from playbooks running in parallel
+var mu sync.Mutex
+
+// Entry records a single playbook execution
+type Entry struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Distro    string        `json:"distro"`
+	Playbook  string        `json:"playbook"`
+	Tags      []string      `json:"tags,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Path returns the location of the history file: ~/.autowsl/history.json
+func Path() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".autowsl", "history.json")
+}
+
+// Append adds an entry to the history file
+func Append(entry Entry) error {
+	return AppendTo(Path(), entry, MaxEntries)
+}
+
+// Load reads all entries from the history file, oldest first
+func Load() ([]Entry, error) {
+	return LoadFrom(Path())
+}
+
+// AppendTo adds an entry to the history file at path, keeping at most
+// maxEntries entries. A maxEntries <= 0 uses DefaultMaxEntries.
+func AppendTo(path string, entry Entry, maxEntries int) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	entries, err := LoadFrom(path)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+	return writeFile(path, entries)
+}
+
+// Prune trims the history file (uses Path)
+func Prune(maxEntries int, before time.Time) (int, error) {
+	return PruneFile(Path(), maxEntries, before)
+}
+
+// PruneFile removes entries older than before (unless it is zero) from the
+// history file at path, then keeps only the newest maxEntries (unless it is
+// 0 or less). It returns the number of entries removed.
+func PruneFile(path string, maxEntries int, before time.Time) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entries, err := LoadFrom(path)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := entries
+	if !before.IsZero() {
+		kept = nil
+		for _, e := range entries {
+			if !e.Timestamp.Before(before) {
+				kept = append(kept, e)
+			}
+		}
+	}
+	if maxEntries > 0 && len(kept) > maxEntries {
+		kept = kept[len(kept)-maxEntries:]
+	}
+
+	removed := len(entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	if kept == nil {
+		kept = []Entry{}
+	}
+	if err := writeFile(path, kept); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// writeFile replaces the history file at path with entries
+func writeFile(path string, entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	// Write to a temp file first so an interrupted write can't corrupt the log
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// LoadFrom reads all entries from the history file at path. A missing file
+// yields no entries.
+func LoadFrom(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file '%s': %w", path, err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse history file '%s': %w", path, err)
+	}
+	return entries, nil
+}
+
+// Filter returns the entries matching distro (empty matches all) and, when
+// failedOnly is set, only failed runs. The last n matches are kept when n > 0.
+func Filter(entries []Entry, distro string, failedOnly bool, n int) []Entry {
+	var matched []Entry
+	for _, e := range entries {
+		if distro != "" && e.Distro != distro {
+			continue
+		}
+		if failedOnly && e.Success {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	if n > 0 && len(matched) > n {
+		matched = matched[len(matched)-n:]
+	}
+	return matched
+}
+
+// ParseAge parses an age such as 30d, 12h or 1h30m. A d suffix counts whole
+// days; anything else is a Go duration.
+func ParseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q (use e.g. 30d or 12h)", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d or 12h)", s)
+	}
+	return d, nil
+}
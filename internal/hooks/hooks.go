@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yuanjua/autowsl/internal/runner"
+)
+
+// Status values passed to hooks in AUTOWSL_STATUS
+const (
+	StatusPending = "pending" // Pre-provision: nothing has run yet
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// Hooks are Windows-side commands run around provisioning. Each command is
+// run by PowerShell.
+type Hooks struct {
+	PreProvision  []string `mapstructure:"pre_provision"`
+	PostProvision []string `mapstructure:"post_provision"`
+}
+
+// Env is the context hooks receive as environment variables
+type Env struct {
+	Distro   string
+	Playbook string // Comma-separated playbooks of the run
+	Status   string
+}
+
+// Vars returns the AUTOWSL_* variables for env
+func (e Env) Vars() []string {
+	return []string{
+		"AUTOWSL_DISTRO=" + e.Distro,
+		"AUTOWSL_PLAYBOOK=" + e.Playbook,
+		"AUTOWSL_STATUS=" + e.Status,
+	}
+}
+
+// dryRun prints hook commands instead of running them
+var dryRun bool
+
+// SetDryRun enables or disables dry-run mode for hooks
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// Merge returns h with the commands of other appended
+func (h Hooks) Merge(other Hooks) Hooks {
+	return Hooks{
+		PreProvision:  append(append([]string{}, h.PreProvision...), other.PreProvision...),
+		PostProvision: append(append([]string{}, h.PostProvision...), other.PostProvision...),
+	}
+}
+
+// Run executes commands in order with PowerShell and stops at the first failure
+func Run(commands []string, env Env) error {
+	r := &runner.ExecRunner{DryRun: dryRun, Out: os.Stdout, Env: env.Vars()}
+	return RunWith(r, commands)
+}
+
+// RunWith executes commands in order with PowerShell using r and stops at the
+// first failure. Hook output is printed after each command finishes.
+func RunWith(r runner.Runner, commands []string) error {
+	for _, command := range commands {
+		fmt.Printf("→ Hook: %s\n", command)
+		stdout, stderr, err := r.Run("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", command)
+		if out := strings.TrimSpace(stdout); out != "" && !dryRun {
+			fmt.Println(out)
+		}
+		if err != nil {
+			return fmt.Errorf("hook '%s' failed: %w\nOutput: %s", command, err, strings.TrimSpace(stderr))
+		}
+	}
+	return nil
+}
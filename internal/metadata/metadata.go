@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the metadata file autowsl writes into a distribution's install directory
+const FileName = ".autowsl.json"
+
+// Metadata records how a distribution was created and provisioned
+type Metadata struct {
+	InstalledAt    time.Time `json:"installed_at" yaml:"installed_at"`
+	CatalogVersion string    `json:"catalog_version,omitempty" yaml:"catalog_version,omitempty"` // autowsl version whose catalog was used
+	PackageID      string    `json:"package_id,omitempty" yaml:"package_id,omitempty"`
+	DistroGroup    string    `json:"distro_group,omitempty" yaml:"distro_group,omitempty"`
+	DistroVersion  string    `json:"distro_version,omitempty" yaml:"distro_version,omitempty"`
+	Source         string    `json:"source,omitempty" yaml:"source,omitempty"` // Tar file or URL for installs outside the catalog
+	WSLVersion     int       `json:"wsl_version" yaml:"wsl_version"`
+	TargetArch     string    `json:"target_arch,omitempty" yaml:"target_arch,omitempty"` // Package architecture forced with install --arch
+	PlaybooksRun   []string  `json:"playbooks_run,omitempty" yaml:"playbooks_run,omitempty"`
+	Tags           []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
+	LastStartedAt  time.Time `json:"last_started_at,omitempty" yaml:"last_started_at,omitempty"` // Last time autowsl entered or ran a command in it
+}
+
+// Write saves m as .autowsl.json in the install directory dir
+func Write(dir string, m Metadata) error {
+	return WriteFile(filepath.Join(dir, FileName), m)
+}
+
+// BackupPath returns where the metadata of a backup tar is stored, next to the tar
+func BackupPath(backupPath string) string {
+	return backupPath + FileName
+}
+
+// WriteFile saves m to an explicit file path
+func WriteFile(path string, m Metadata) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Read loads .autowsl.json from the install directory dir. The error wraps
+// os.ErrNotExist when the distribution has no metadata.
+func Read(dir string) (Metadata, error) {
+	var m Metadata
+	path := filepath.Join(dir, FileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("failed to read metadata file '%s': %w", path, err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("failed to parse metadata file '%s': %w", path, err)
+	}
+	return m, nil
+}
+
+// AddPlaybooks records playbooks that ran against the distribution in dir,
+// creating the metadata file if the distribution has none yet
+func AddPlaybooks(dir string, playbooks, tags []string) error {
+	m, err := Read(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	m.PlaybooksRun = appendMissing(m.PlaybooksRun, playbooks)
+	m.Tags = appendMissing(m.Tags, tags)
+	return Write(dir, m)
+}
+
+// RecordStart stores the current time as the last start of the distribution
+// in dir, creating the metadata file if needed
+func RecordStart(dir string) error {
+	m, err := Read(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	m.LastStartedAt = time.Now().UTC()
+	return Write(dir, m)
+}
+
+// appendMissing appends the values of add that are not in list yet
+func appendMissing(list, add []string) []string {
+	for _, v := range add {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -1,24 +1,64 @@
 package playbooks
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 )
 
-// ParseExtraVars converts key=val strings into a map
-func ParseExtraVars(kvs []string) (map[string]string, error) {
-	m := make(map[string]string)
+// ParseExtraVars converts key=val strings into a map. Values may be wrapped
+// in single or double quotes, which are stripped, and a value of @file reads
+// the named JSON file and uses its decoded content as the value.
+func ParseExtraVars(kvs []string) (map[string]any, error) {
+	m := make(map[string]any)
 	for _, kv := range kvs {
 		parts := strings.SplitN(kv, "=", 2)
 		if len(parts) != 2 {
 			return nil, fmt.Errorf("invalid extra-vars entry: %s (expected key=val)", kv)
 		}
 		key := strings.TrimSpace(parts[0])
-		val := strings.TrimSpace(parts[1])
+		val := unquoteValue(strings.TrimSpace(parts[1]))
 		if key == "" {
 			return nil, fmt.Errorf("empty key in extra-vars entry: %s", kv)
 		}
+		if strings.HasPrefix(val, "@") {
+			content, err := readJSONValue(val[1:])
+			if err != nil {
+				return nil, fmt.Errorf("extra-vars entry %s: %w", key, err)
+			}
+			m[key] = content
+			continue
+		}
 		m[key] = val
 	}
 	return m, nil
 }
+
+// unquoteValue strips one pair of matching outer single or double quotes
+func unquoteValue(val string) string {
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		return val[1 : len(val)-1]
+	}
+	return val
+}
+
+// readJSONValue reads and decodes a JSON file referenced by an @file value.
+// Numbers are kept as json.Number so large integers survive re-encoding.
+func readJSONValue(path string) (any, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing file name after '@'")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil || dec.More() {
+		return nil, fmt.Errorf("'%s' does not contain valid JSON", path)
+	}
+	return value, nil
+}
@@ -2,24 +2,38 @@ package playbooks
 
 import (
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/downloader"
 )
 
+// DefaultCacheMaxAge is how long a downloaded playbook is reused
+const DefaultCacheMaxAge = time.Hour
+
 // Resolver handles playbook resolution from various input formats
 type Resolver struct {
-	TempDir string
-	FSRoot  string
+	downloader.RetryConfig
+	TempDir  string
+	FSRoot   string
+	AliasDir string // Directory searched for aliases (default: <FSRoot>/playbooks)
+
+	ForceRefresh bool          // Download URLs even if a cached copy exists
+	CacheMaxAge  time.Duration // Cached downloads older than this are fetched again; 0 never expires
 }
 
 // NewResolver creates a new playbook resolver
 func NewResolver(tempDir, fsRoot string) *Resolver {
 	return &Resolver{
-		TempDir: tempDir,
-		FSRoot:  fsRoot,
+		RetryConfig: downloader.DefaultRetryConfig(),
+		TempDir:     tempDir,
+		FSRoot:      fsRoot,
+		CacheMaxAge: DefaultCacheMaxAge,
 	}
 }
 
@@ -60,7 +74,11 @@ func (r *Resolver) Resolve(input string) ([]string, error) {
 	}
 
 	// Try as alias (playbooks/<name>.yml)
-	aliasPath := filepath.Join(r.FSRoot, "playbooks", ensureYmlExt(input))
+	aliasDir := r.AliasDir
+	if aliasDir == "" {
+		aliasDir = filepath.Join(r.FSRoot, "playbooks")
+	}
+	aliasPath := filepath.Join(aliasDir, ensureYmlExt(input))
 	if _, err := os.Stat(aliasPath); err == nil {
 		absPath, _ := filepath.Abs(aliasPath)
 		return []string{absPath}, nil
@@ -90,11 +108,20 @@ func (r *Resolver) ResolveMultiple(inputs []string) ([]string, error) {
 	return results, nil
 }
 
-// downloadPlaybook downloads a playbook from a URL
+// downloadPlaybook downloads a playbook from a URL. A previous download of
+// the same URL in TempDir is reused unless it is stale or ForceRefresh is set.
 func (r *Resolver) downloadPlaybook(url string) (string, error) {
 	playbookFile := filepath.Join(r.TempDir, "autowsl-playbook-"+sanitizeFilename(url)+".yml")
+	if r.isCached(playbookFile) {
+		return playbookFile, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request for '%s': %w", url, err)
+	}
 
-	resp, err := http.Get(url)
+	resp, err := downloader.DoWithRetry(http.DefaultClient, req, r.RetryConfig)
 	if err != nil {
 		return "", fmt.Errorf("failed to download from '%s': %w", url, err)
 	}
@@ -104,20 +131,46 @@ func (r *Resolver) downloadPlaybook(url string) (string, error) {
 		return "", fmt.Errorf("failed to download from '%s': HTTP %s", url, resp.Status)
 	}
 
-	out, err := os.Create(playbookFile)
+	// Write to a temporary file first so an interrupted download is never cached
+	partFile := playbookFile + ".part"
+	out, err := os.Create(partFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to create playbook file '%s': %w", playbookFile, err)
+		return "", fmt.Errorf("failed to create playbook file '%s': %w", partFile, err)
 	}
-	defer out.Close()
 
 	_, err = io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
+		os.Remove(partFile)
 		return "", fmt.Errorf("failed to write playbook file: %w", err)
 	}
+	// Don't cache a download that isn't a playbook, e.g. an HTML error page
+	if err := ValidatePlaybook(partFile); err != nil {
+		os.Remove(partFile)
+		return "", fmt.Errorf("downloaded file from '%s' is not a valid playbook: %w", url, err)
+	}
+	if err := os.Rename(partFile, playbookFile); err != nil {
+		os.Remove(partFile)
+		return "", fmt.Errorf("failed to write playbook file '%s': %w", playbookFile, err)
+	}
 
 	return playbookFile, nil
 }
 
+// isCached reports whether a usable download exists at path
+func (r *Resolver) isCached(path string) bool {
+	if r.ForceRefresh {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return r.CacheMaxAge <= 0 || time.Since(info.ModTime()) < r.CacheMaxAge
+}
+
 func isURL(str string) bool {
 	return strings.HasPrefix(str, "http://") || strings.HasPrefix(str, "https://")
 }
@@ -129,8 +182,9 @@ func ensureYmlExt(name string) string {
 	return name + ".yml"
 }
 
+// sanitizeFilename returns a deterministic file name for a URL: a readable
+// prefix plus a hash of the whole URL, so URLs sharing a prefix don't collide
 func sanitizeFilename(url string) string {
-	// Simple hash-like identifier from URL
 	safe := strings.ReplaceAll(url, "://", "-")
 	safe = strings.ReplaceAll(safe, "/", "-")
 	safe = strings.ReplaceAll(safe, "?", "-")
@@ -138,7 +192,9 @@ func sanitizeFilename(url string) string {
 	if len(safe) > 40 {
 		safe = safe[:40]
 	}
-	return safe
+	h := fnv.New32a()
+	h.Write([]byte(url))
+	return fmt.Sprintf("%s-%08x", safe, h.Sum32())
 }
 
 // BaseNames extracts base names from file paths
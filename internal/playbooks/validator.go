@@ -0,0 +1,71 @@
+package playbooks
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// playbookSequenceKeys are play keys whose value must be a list
+var playbookSequenceKeys = []string{"pre_tasks", "tasks", "post_tasks", "handlers"}
+
+// ValidatePlaybook checks the structure of a playbook file without running
+// Ansible: the document must be a list of plays, every play needs a hosts key
+// and task lists must be lists. import_playbook entries are accepted as plays.
+func ValidatePlaybook(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read playbook '%s': %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("playbook '%s' is not valid YAML: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return fmt.Errorf("playbook '%s' is empty", path)
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.SequenceNode {
+		return fmt.Errorf("playbook '%s' must be a list of plays (line %d)", path, root.Line)
+	}
+	if len(root.Content) == 0 {
+		return fmt.Errorf("playbook '%s' contains no plays", path)
+	}
+
+	for i, play := range root.Content {
+		if play.Kind != yaml.MappingNode {
+			return fmt.Errorf("playbook '%s': play %d must be a mapping (line %d)", path, i+1, play.Line)
+		}
+		if mappingValue(play, "import_playbook") != nil || mappingValue(play, "ansible.builtin.import_playbook") != nil {
+			continue
+		}
+		if mappingValue(play, "hosts") == nil {
+			return fmt.Errorf("playbook '%s': play %d has no 'hosts' key (line %d)", path, i+1, play.Line)
+		}
+		for _, key := range playbookSequenceKeys {
+			value := mappingValue(play, key)
+			if value != nil && value.Kind != yaml.SequenceNode && !isNull(value) {
+				return fmt.Errorf("playbook '%s': '%s' in play %d must be a list (line %d)", path, key, i+1, value.Line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
@@ -0,0 +1,147 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named set of defaults for install and provision
+type Profile struct {
+	Name                 string            `yaml:"name" json:"name"`
+	DefaultDistroVersion string            `yaml:"default_distro_version,omitempty" json:"default_distro_version,omitempty"` // Catalog version, e.g. "Ubuntu 22.04 LTS"
+	DefaultName          string            `yaml:"default_name,omitempty" json:"default_name,omitempty"`                     // Distribution name to install or provision
+	DefaultPath          string            `yaml:"default_path,omitempty" json:"default_path,omitempty"`
+	Playbooks            []string          `yaml:"playbooks,omitempty" json:"playbooks,omitempty"`
+	Tags                 []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	ExtraVars            map[string]string `yaml:"extra_vars,omitempty" json:"extra_vars,omitempty"`
+	WSLVersion           int               `yaml:"wsl_version,omitempty" json:"wsl_version,omitempty"`
+	KeepTar              bool              `yaml:"keep_tar,omitempty" json:"keep_tar,omitempty"`
+}
+
+// namePattern matches profile names that are safe to use as file names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ErrNotFound is returned when a profile does not exist
+var ErrNotFound = errors.New("profile not found")
+
+// Dir returns the directory profiles are stored in: ~/.autowsl/profiles
+func Dir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".autowsl", "profiles")
+}
+
+// ValidateName checks that a profile name can be stored
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s' (use letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// Save stores a profile, replacing one with the same name
+func Save(p Profile) error {
+	return SaveTo(Dir(), p)
+}
+
+// Load reads a profile by name
+func Load(name string) (Profile, error) {
+	return LoadFrom(Dir(), name)
+}
+
+// List returns all profiles sorted by name
+func List() ([]Profile, error) {
+	return ListFrom(Dir())
+}
+
+// Delete removes a profile by name
+func Delete(name string) error {
+	return DeleteFrom(Dir(), name)
+}
+
+// SaveTo stores a profile as <name>.yml in dir
+func SaveTo(dir string, p Profile) error {
+	if err := ValidateName(p.Name); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode profile '%s': %w", p.Name, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, p.Name+".yml"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile '%s': %w", p.Name, err)
+	}
+	return nil
+}
+
+// LoadFrom reads the profile <name>.yml from dir. The error wraps ErrNotFound
+// when it does not exist.
+func LoadFrom(dir, name string) (Profile, error) {
+	if err := ValidateName(name); err != nil {
+		return Profile{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".yml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Profile{}, fmt.Errorf("%w: '%s'", ErrNotFound, name)
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to read profile '%s': %w", name, err)
+	}
+
+	var p Profile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return Profile{}, fmt.Errorf("failed to parse profile '%s': %w", name, err)
+	}
+	p.Name = name
+	return p, nil
+}
+
+// ListFrom returns all profiles in dir sorted by name. A missing directory
+// yields no profiles.
+func ListFrom(dir string) ([]Profile, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+
+	var profiles []Profile
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yml")
+		if entry.IsDir() || !ok || ValidateName(name) != nil {
+			continue
+		}
+		p, err := LoadFrom(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// DeleteFrom removes the profile <name>.yml from dir
+func DeleteFrom(dir, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, name+".yml")); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: '%s'", ErrNotFound, name)
+		}
+		return fmt.Errorf("failed to delete profile '%s': %w", name, err)
+	}
+	return nil
+}
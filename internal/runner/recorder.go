@@ -0,0 +1,170 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Invocation is one recorded command with its result
+type Invocation struct {
+	Name     string   `json:"name"`
+	Args     []string `json:"args"`
+	Stdin    string   `json:"stdin,omitempty"`
+	Stdout   string   `json:"stdout"`
+	Stderr   string   `json:"stderr"`
+	Error    string   `json:"error,omitempty"`
+	ExitCode int      `json:"exit_code,omitempty"`
+}
+
+// key identifies the command an invocation answers
+func (inv Invocation) key() string {
+	return inv.Name + "\x00" + strings.Join(inv.Args, "\x00") + "\x00" + inv.Stdin
+}
+
+// RecordingRunner wraps another runner (normally an ExecRunner) and records
+// every command it runs, so the session can be replayed with ReplayRunner
+type RecordingRunner struct {
+	Runner Runner
+
+	mu          sync.Mutex
+	invocations []Invocation
+}
+
+// NewRecordingRunner creates a runner that records the commands run by r
+func NewRecordingRunner(r Runner) *RecordingRunner {
+	return &RecordingRunner{Runner: r}
+}
+
+// Run executes a command through the wrapped runner and records it
+func (r *RecordingRunner) Run(name string, args ...string) (string, string, error) {
+	stdout, stderr, err := r.Runner.Run(name, args...)
+	r.record(Invocation{Name: name, Args: args}, stdout, stderr, err)
+	return stdout, stderr, err
+}
+
+// RunWithInput executes a command with stdin through the wrapped runner and records it
+func (r *RecordingRunner) RunWithInput(name string, stdin string, args ...string) (string, string, error) {
+	stdout, stderr, err := r.Runner.RunWithInput(name, stdin, args...)
+	r.record(Invocation{Name: name, Args: args, Stdin: stdin}, stdout, stderr, err)
+	return stdout, stderr, err
+}
+
+func (r *RecordingRunner) record(inv Invocation, stdout, stderr string, err error) {
+	inv.Args = append([]string{}, inv.Args...)
+	inv.Stdout = stdout
+	inv.Stderr = stderr
+	if err != nil {
+		inv.Error = err.Error()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			inv.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.invocations = append(r.invocations, inv)
+}
+
+// Invocations returns the commands recorded so far, in the order they ran
+func (r *RecordingRunner) Invocations() []Invocation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Invocation{}, r.invocations...)
+}
+
+// SaveRecording writes the recorded commands to path as JSON
+func (r *RecordingRunner) SaveRecording(path string) error {
+	data, err := json.MarshalIndent(r.Invocations(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write recording '%s': %w", path, err)
+	}
+	return nil
+}
+
+// ReplayError is returned by ReplayRunner for a recorded command that failed.
+// It carries the recorded message and exit code; it is not an *exec.ExitError.
+type ReplayError struct {
+	Message  string
+	ExitCode int
+}
+
+func (e *ReplayError) Error() string {
+	return e.Message
+}
+
+// ReplayRunner answers commands from a recording made by RecordingRunner.
+// Repeated runs of the same command get the recorded results in order; once
+// they are used up the last one is repeated. Commands that were never
+// recorded fail.
+type ReplayRunner struct {
+	mu        sync.Mutex
+	responses map[string][]Invocation
+	next      map[string]int
+	Calls     []string // Commands run so far, formatted with FormatCommand
+}
+
+// NewReplayRunner creates a runner that replays the given invocations
+func NewReplayRunner(invocations []Invocation) *ReplayRunner {
+	r := &ReplayRunner{
+		responses: make(map[string][]Invocation),
+		next:      make(map[string]int),
+	}
+	for _, inv := range invocations {
+		r.responses[inv.key()] = append(r.responses[inv.key()], inv)
+	}
+	return r
+}
+
+// LoadRecording reads a recording saved by SaveRecording
+func LoadRecording(path string) (*ReplayRunner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording '%s': %w", path, err)
+	}
+	var invocations []Invocation
+	if err := json.Unmarshal(data, &invocations); err != nil {
+		return nil, fmt.Errorf("failed to parse recording '%s': %w", path, err)
+	}
+	return NewReplayRunner(invocations), nil
+}
+
+// Run returns the recorded result of a command
+func (r *ReplayRunner) Run(name string, args ...string) (string, string, error) {
+	return r.replay(Invocation{Name: name, Args: args})
+}
+
+// RunWithInput returns the recorded result of a command run with stdin
+func (r *ReplayRunner) RunWithInput(name string, stdin string, args ...string) (string, string, error) {
+	return r.replay(Invocation{Name: name, Args: args, Stdin: stdin})
+}
+
+func (r *ReplayRunner) replay(inv Invocation) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Calls = append(r.Calls, FormatCommand(inv.Name, inv.Args...))
+	key := inv.key()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		return "", "", fmt.Errorf("no recorded result for: %s", FormatCommand(inv.Name, inv.Args...))
+	}
+
+	i := r.next[key]
+	if i < len(responses)-1 {
+		r.next[key] = i + 1
+	}
+	resp := responses[i]
+	if resp.Error != "" {
+		return resp.Stdout, resp.Stderr, &ReplayError{Message: resp.Error, ExitCode: resp.ExitCode}
+	}
+	return resp.Stdout, resp.Stderr, nil
+}
@@ -3,8 +3,12 @@ package runner
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -14,10 +18,16 @@ type Runner interface {
 	RunWithInput(name string, stdin string, args ...string) (stdout string, stderr string, err error)
 }
 
+// DefaultWSLPath is the WSL binary used unless ExecRunner.WSLPath overrides it
+const DefaultWSLPath = "wsl.exe"
+
 // ExecRunner executes real system commands with timeout support
 type ExecRunner struct {
 	Timeout time.Duration
 	DryRun  bool
+	Out     io.Writer // Where dry-run commands are echoed (nil = not echoed)
+	Env     []string  // Extra KEY=value variables added to the inherited environment
+	WSLPath string    // Binary run in place of "wsl.exe" (empty = wsl.exe)
 }
 
 // NewExecRunner creates a new runner with the given timeout
@@ -25,11 +35,21 @@ func NewExecRunner(timeout time.Duration) *ExecRunner {
 	return &ExecRunner{
 		Timeout: timeout,
 		DryRun:  false,
+		WSLPath: DefaultWSLPath,
+	}
+}
+
+// resolve returns the binary to run for name, applying WSLPath to wsl.exe
+func (r *ExecRunner) resolve(name string) string {
+	if name == DefaultWSLPath && r.WSLPath != "" {
+		return r.WSLPath
 	}
+	return name
 }
 
 // Run executes a command and returns stdout, stderr, and error
 func (r *ExecRunner) Run(name string, args ...string) (string, string, error) {
+	name = r.resolve(name)
 	if r.DryRun {
 		return r.dryRunLog(name, args...), "", nil
 	}
@@ -45,16 +65,18 @@ func (r *ExecRunner) Run(name string, args ...string) (string, string, error) {
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, name, args...)
+	r.applyEnv(cmd)
 	var outB, errB bytes.Buffer
 	cmd.Stdout = &outB
 	cmd.Stderr = &errB
 
 	err := cmd.Run()
-	return outB.String(), errB.String(), err
+	return outB.String(), errB.String(), r.timeoutError(ctx, name, err)
 }
 
 // RunWithInput executes a command with stdin and returns stdout, stderr, and error
 func (r *ExecRunner) RunWithInput(name string, stdin string, args ...string) (string, string, error) {
+	name = r.resolve(name)
 	if r.DryRun {
 		return r.dryRunLog(name, args...), "", nil
 	}
@@ -70,15 +92,50 @@ func (r *ExecRunner) RunWithInput(name string, stdin string, args ...string) (st
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, name, args...)
+	r.applyEnv(cmd)
 	var outB, errB bytes.Buffer
 	cmd.Stdout = &outB
 	cmd.Stderr = &errB
 	cmd.Stdin = bytes.NewBufferString(stdin)
 
 	err := cmd.Run()
-	return outB.String(), errB.String(), err
+	return outB.String(), errB.String(), r.timeoutError(ctx, name, err)
+}
+
+// timeoutError replaces the error of a command killed by the timeout with one
+// that says so and wraps context.DeadlineExceeded
+func (r *ExecRunner) timeoutError(ctx context.Context, name string, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s: %w", name, r.Timeout, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// applyEnv adds r.Env to the environment of cmd
+func (r *ExecRunner) applyEnv(cmd *exec.Cmd) {
+	if len(r.Env) > 0 {
+		cmd.Env = append(os.Environ(), r.Env...)
+	}
 }
 
 func (r *ExecRunner) dryRunLog(name string, args ...string) string {
-	return fmt.Sprintf("[dry-run] %s %v", name, args)
+	line := "[dry-run] " + FormatCommand(name, args...)
+	if r.Out != nil {
+		fmt.Fprintln(r.Out, line)
+	}
+	return line
+}
+
+// FormatCommand renders a command line the way it would be typed in a shell,
+// quoting arguments that contain whitespace or quotes
+func FormatCommand(name string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, name)
+	for _, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n\"'") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
 }
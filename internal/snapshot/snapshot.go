@@ -0,0 +1,208 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Snapshot is an export of a distribution kept in the snapshot directory.
+// It is described by a <name>.json sidecar next to <name>.tar.
+type Snapshot struct {
+	Name      string    `json:"name" yaml:"name"`
+	Distro    string    `json:"distro" yaml:"distro"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	Size      int64     `json:"size" yaml:"size"` // Size of the tar in bytes
+}
+
+// timestampFormat is the time format of generated snapshot names
+const timestampFormat = "20060102-150405"
+
+// namePattern matches snapshot names that are safe to use as file names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ErrNotFound is returned when a snapshot does not exist
+var ErrNotFound = errors.New("snapshot not found")
+
+// Dir returns the directory snapshots are stored in: ~/.autowsl/snapshots
+func Dir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".autowsl", "snapshots")
+}
+
+// ValidateName checks that a snapshot name can be stored
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name '%s' (use letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// NewName returns the generated name of a snapshot taken at t, e.g.
+// Ubuntu-20240131-154500
+func NewName(distro string, t time.Time) string {
+	return distro + "-" + t.Format(timestampFormat)
+}
+
+// TarPath returns the path of a snapshot's tar file under root
+func TarPath(root, distro, name string) string {
+	return filepath.Join(root, distro, name+".tar")
+}
+
+// sidecarPath returns the path of a snapshot's metadata file under root
+func sidecarPath(root, distro, name string) string {
+	return filepath.Join(root, distro, name+".json")
+}
+
+// RestoreName returns the first of <distro>-1, <distro>-2, ... that is not
+// in installed
+func RestoreName(distro string, installed []string) string {
+	taken := make(map[string]bool, len(installed))
+	for _, name := range installed {
+		taken[strings.ToLower(name)] = true
+	}
+	for i := 1; ; i++ {
+		name := distro + "-" + strconv.Itoa(i)
+		if !taken[strings.ToLower(name)] {
+			return name
+		}
+	}
+}
+
+// List returns the snapshots of a distribution, newest first
+func List(distro string) ([]Snapshot, error) {
+	return ListFrom(Dir(), distro)
+}
+
+// Load reads a snapshot of a distribution by name
+func Load(distro, name string) (Snapshot, error) {
+	return LoadFrom(Dir(), distro, name)
+}
+
+// Save writes the metadata of a snapshot whose tar is already in place
+func Save(s Snapshot) error {
+	return SaveTo(Dir(), s)
+}
+
+// Delete removes a snapshot and its metadata
+func Delete(distro, name string) error {
+	return DeleteFrom(Dir(), distro, name)
+}
+
+// Prune deletes all but the keep newest snapshots of a distribution and
+// returns the deleted ones
+func Prune(distro string, keep int) ([]Snapshot, error) {
+	return PruneFrom(Dir(), distro, keep)
+}
+
+// SaveTo writes the <name>.json sidecar of a snapshot under root
+func SaveTo(root string, s Snapshot) error {
+	if err := ValidateName(s.Name); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot '%s': %w", s.Name, err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, s.Distro), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(sidecarPath(root, s.Distro, s.Name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata '%s': %w", s.Name, err)
+	}
+	return nil
+}
+
+// LoadFrom reads a snapshot of a distribution under root. The error wraps
+// ErrNotFound when it does not exist.
+func LoadFrom(root, distro, name string) (Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return Snapshot{}, err
+	}
+	data, err := os.ReadFile(sidecarPath(root, distro, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, fmt.Errorf("%w: '%s' of '%s'", ErrNotFound, name, distro)
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot '%s': %w", name, err)
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse snapshot '%s': %w", name, err)
+	}
+	s.Name, s.Distro = name, distro
+	return s, nil
+}
+
+// ListFrom returns the snapshots of a distribution under root, newest first.
+// A missing directory yields no snapshots.
+func ListFrom(root, distro string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(root, distro))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok || ValidateName(name) != nil {
+			continue
+		}
+		s, err := LoadFrom(root, distro, name)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+// DeleteFrom removes the tar and sidecar of a snapshot under root
+func DeleteFrom(root, distro, name string) error {
+	if _, err := LoadFrom(root, distro, name); err != nil {
+		return err
+	}
+	if err := os.Remove(TarPath(root, distro, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete snapshot '%s': %w", name, err)
+	}
+	if err := os.Remove(sidecarPath(root, distro, name)); err != nil {
+		return fmt.Errorf("failed to delete snapshot metadata '%s': %w", name, err)
+	}
+	return nil
+}
+
+// PruneFrom deletes all but the keep newest snapshots of a distribution under
+// root and returns the deleted ones
+func PruneFrom(root, distro string, keep int) ([]Snapshot, error) {
+	if keep < 0 {
+		return nil, fmt.Errorf("invalid keep count %d (must be 0 or more)", keep)
+	}
+	snapshots, err := ListFrom(root, distro)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) <= keep {
+		return nil, nil
+	}
+
+	var deleted []Snapshot
+	for _, s := range snapshots[keep:] {
+		if err := DeleteFrom(root, distro, s.Name); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, s)
+	}
+	return deleted, nil
+}
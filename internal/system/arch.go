@@ -1,6 +1,7 @@
 package system
 
 import (
+	"fmt"
 	"runtime"
 	"strings"
 )
@@ -55,11 +56,24 @@ func IsCompatibleArchitecture(distroArch string) bool {
 	}
 }
 
+// ParseArchitecture validates an architecture given on the command line.
+// Only x64 and arm64 are accepted since WSL runs on nothing else.
+func ParseArchitecture(s string) (HostArchitecture, error) {
+	switch arch := HostArchitecture(strings.ToLower(strings.TrimSpace(s))); arch {
+	case ArchX64, ArchARM64:
+		return arch, nil
+	}
+	return "", fmt.Errorf("invalid architecture %q (must be x64 or arm64)", s)
+}
+
 // GetPreferredArchitectureSuffix returns the preferred architecture suffix for filtering
 func GetPreferredArchitectureSuffix() string {
-	hostArch := GetHostArchitecture()
+	return PreferredArchitectureSuffix(GetHostArchitecture())
+}
 
-	switch hostArch {
+// PreferredArchitectureSuffix returns the package name suffix preferred for arch
+func PreferredArchitectureSuffix(arch HostArchitecture) string {
+	switch arch {
 	case ArchX64:
 		return "x64"
 	case ArchARM64:
@@ -73,10 +87,15 @@ func GetPreferredArchitectureSuffix() string {
 
 // ShouldSkipArchitecture returns true if the architecture should be skipped
 func ShouldSkipArchitecture(filename string) bool {
-	hostArch := GetHostArchitecture()
+	return ShouldSkipArchitectureFor(GetHostArchitecture(), filename)
+}
+
+// ShouldSkipArchitectureFor returns true if filename is a package for an
+// architecture other than arch
+func ShouldSkipArchitectureFor(arch HostArchitecture, filename string) bool {
 	filenameLower := strings.ToLower(filename)
 
-	switch hostArch {
+	switch arch {
 	case ArchX64:
 		// Skip ARM versions on x64 systems
 		return strings.Contains(filenameLower, "arm64") ||
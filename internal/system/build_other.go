@@ -0,0 +1,10 @@
+//go:build !windows
+
+package system
+
+import "fmt"
+
+// GetWindowsBuildNumber fails outside Windows
+func GetWindowsBuildNumber() (int, error) {
+	return 0, fmt.Errorf("not running on Windows")
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package system
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// GetWindowsBuildNumber reads the Windows build number from the registry
+func GetWindowsBuildNumber() (int, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open Windows version registry key: %w", err)
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue("CurrentBuildNumber")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read Windows build number: %w", err)
+	}
+	build, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Windows build number '%s': %w", value, err)
+	}
+	return build, nil
+}
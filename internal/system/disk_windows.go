@@ -0,0 +1,26 @@
+//go:build windows
+
+package system
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// FreeDiskSpace returns the number of bytes available to the current user on
+// the volume that holds path
+func FreeDiskSpace(path string) (uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, fmt.Errorf("invalid path '%s': %w", path, err)
+	}
+
+	var freeBytes uint64
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+	ret, _, callErr := proc.Call(uintptr(unsafe.Pointer(pathPtr)), uintptr(unsafe.Pointer(&freeBytes)), 0, 0)
+	if ret == 0 {
+		return 0, fmt.Errorf("failed to query free space for '%s': %w", path, callErr)
+	}
+	return freeBytes, nil
+}
@@ -0,0 +1,73 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Features that depend on the Windows build, for IsFeatureAvailable
+const (
+	FeatureWSL2               = "wsl2"
+	FeatureSparseVHD          = "wsl2-sparse-vhd"
+	FeatureMirroredNetworking = "wsl2-mirrored-networking"
+	FeatureVHDExport          = "wsl2-vhd-export"
+)
+
+// windowsFeature is a feature and the first Windows build that supports it
+type windowsFeature struct {
+	minBuild    int
+	description string
+}
+
+var windowsFeatures = map[string]windowsFeature{
+	FeatureWSL2:               {minBuild: 19041, description: "WSL 2"},
+	FeatureSparseVHD:          {minBuild: 22557, description: "Sparse VHDs"},
+	FeatureMirroredNetworking: {minBuild: 22621, description: "Mirrored networking"},
+	FeatureVHDExport:          {minBuild: 22000, description: "Exporting to a VHD (Windows 11)"},
+}
+
+// Features returns the names of all known features, sorted
+func Features() []string {
+	names := make([]string, 0, len(windowsFeatures))
+	for name := range windowsFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MinimumBuild returns the first Windows build that supports feature, or 0
+// for an unknown feature
+func MinimumBuild(feature string) int {
+	return windowsFeatures[feature].minBuild
+}
+
+// IsFeatureAvailable reports whether this Windows build supports feature.
+// It is false for unknown features and when the build can't be determined.
+func IsFeatureAvailable(feature string) bool {
+	build, err := GetWindowsBuildNumber()
+	return err == nil && FeatureSupported(feature, build) == nil
+}
+
+// FeatureSupported returns a descriptive error when build is too old for
+// feature
+func FeatureSupported(feature string, build int) error {
+	f, ok := windowsFeatures[feature]
+	if !ok {
+		return fmt.Errorf("unknown feature '%s'", feature)
+	}
+	if build < f.minBuild {
+		return fmt.Errorf("%s requires Windows build %d or later; this is build %d", f.description, f.minBuild, build)
+	}
+	return nil
+}
+
+// CheckFeature is FeatureSupported for this machine. When the build can't
+// be read the feature is assumed to work and wsl.exe has the final say.
+func CheckFeature(feature string) error {
+	build, err := GetWindowsBuildNumber()
+	if err != nil {
+		return nil
+	}
+	return FeatureSupported(feature, build)
+}
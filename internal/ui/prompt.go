@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// ErrNoDefault is returned in non-interactive mode for prompts that need an answer but have no default
+var ErrNoDefault = errors.New("no default value in non-interactive mode")
+
+// Prompter shows promptui prompts and selections
+type Prompter interface {
+	Prompt(p promptui.Prompt) (string, error)
+	Select(s promptui.Select) (int, string, error)
+}
+
+// InteractivePrompter asks the user on the terminal
+type InteractivePrompter struct{}
+
+// Prompt runs the prompt on the terminal
+func (InteractivePrompter) Prompt(p promptui.Prompt) (string, error) {
+	return p.Run()
+}
+
+// Select runs the selection on the terminal
+func (InteractivePrompter) Select(s promptui.Select) (int, string, error) {
+	return s.Run()
+}
+
+// NonInteractivePrompter answers prompts without a terminal: confirmations are
+// accepted and other prompts take their default value. Prompts without a
+// default and selections fail with ErrNoDefault.
+type NonInteractivePrompter struct{}
+
+// Prompt accepts confirmations and returns the default for everything else
+func (NonInteractivePrompter) Prompt(p promptui.Prompt) (string, error) {
+	if p.IsConfirm {
+		return "y", nil
+	}
+	if p.Default == "" {
+		return "", fmt.Errorf("'%v' %w; pass it as an argument or flag", p.Label, ErrNoDefault)
+	}
+	if p.Validate != nil {
+		if err := p.Validate(p.Default); err != nil {
+			return "", fmt.Errorf("invalid default for '%v': %w", p.Label, err)
+		}
+	}
+	return p.Default, nil
+}
+
+// Select always fails since a selection has no default
+func (NonInteractivePrompter) Select(s promptui.Select) (int, string, error) {
+	return -1, "", fmt.Errorf("'%v' %w; pass it as an argument", s.Label, ErrNoDefault)
+}
+
+// current is the prompter used by Prompt and Select
+var current Prompter = InteractivePrompter{}
+
+// SetPrompter replaces the prompter used by Prompt and Select
+func SetPrompter(p Prompter) {
+	current = p
+}
+
+// Prompt runs a prompt with the current prompter
+func Prompt(p promptui.Prompt) (string, error) {
+	return current.Prompt(p)
+}
+
+// Select runs a selection with the current prompter
+func Select(s promptui.Select) (int, string, error) {
+	return current.Select(s)
+}
+
+// IsInteractive reports whether prompts are answered by the user rather than
+// accepted automatically (--yes)
+func IsInteractive() bool {
+	_, ok := current.(NonInteractivePrompter)
+	return !ok
+}
+
+// ConfirmWord asks the user to type word, e.g. "yes", before a destructive
+// action. Like other confirmations it is accepted in non-interactive mode.
+func ConfirmWord(label, word string) bool {
+	if !IsInteractive() {
+		return true
+	}
+	answer, err := current.Prompt(promptui.Prompt{Label: fmt.Sprintf("%s? Type '%s' to confirm", label, word)})
+	return err == nil && strings.EqualFold(strings.TrimSpace(answer), word)
+}
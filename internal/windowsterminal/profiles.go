@@ -0,0 +1,161 @@
+package windowsterminal
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// linuxIcon is the penguin icon Windows Terminal ships for WSL profiles
+const linuxIcon = "ms-appx:///ProfileIcons/{9acb9455-ca41-5af7-950f-6bca1bc9722f}.png"
+
+// profileNamespace is the UUIDv5 namespace used to derive stable profile GUIDs,
+// so re-running an install for the same name never creates duplicate profiles
+var profileNamespace = [16]byte{
+	0x5d, 0x1f, 0x3c, 0x8a, 0x2e, 0x47, 0x4b, 0x6d,
+	0x9a, 0x0c, 0x71, 0xe4, 0xb3, 0x58, 0x26, 0xf0,
+}
+
+// Profile is a Windows Terminal profile entry for a WSL distribution
+type Profile struct {
+	GUID        string `json:"guid"`
+	Name        string `json:"name"`
+	Commandline string `json:"commandline"`
+	Icon        string `json:"icon,omitempty"`
+	Hidden      bool   `json:"hidden"`
+}
+
+// GenerateProfile builds the Windows Terminal profile for a distribution
+func GenerateProfile(distroName string) (Profile, error) {
+	if strings.TrimSpace(distroName) == "" {
+		return Profile{}, fmt.Errorf("distribution name cannot be empty")
+	}
+
+	return Profile{
+		GUID:        profileGUID(distroName),
+		Name:        distroName,
+		Commandline: fmt.Sprintf("wsl.exe -d %s", distroName),
+		Icon:        linuxIcon,
+	}, nil
+}
+
+// JSON returns the profile as an indented JSON fragment for manual use
+func (p Profile) JSON() (string, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode profile: %w", err)
+	}
+	return string(data), nil
+}
+
+// profileGUID derives a name-based (version 5) UUID in Windows Terminal's braced form
+func profileGUID(name string) string {
+	// Windows Terminal hashes profile names as UTF-16LE
+	var nameBytes []byte
+	for _, u := range utf16.Encode([]rune(name)) {
+		nameBytes = append(nameBytes, byte(u), byte(u>>8))
+	}
+
+	h := sha1.New()
+	h.Write(profileNamespace[:])
+	h.Write(nameBytes)
+	sum := h.Sum(nil)
+
+	sum[6] = (sum[6] & 0x0f) | 0x50 // version 5
+	sum[8] = (sum[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("{%x-%x-%x-%x-%x}", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// FindSettingsFile locates the settings.json of an installed Windows Terminal
+// (stable or preview) under %LOCALAPPDATA%\Packages
+func FindSettingsFile() (string, error) {
+	localAppData := os.Getenv("LOCALAPPDATA")
+	if localAppData == "" {
+		return "", fmt.Errorf("LOCALAPPDATA is not set")
+	}
+
+	pattern := filepath.Join(localAppData, "Packages", "Microsoft.WindowsTerminal*", "LocalState", "settings.json")
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to search for Windows Terminal settings: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("windows terminal settings file not found")
+	}
+
+	// Prefer the stable release over Preview when both are installed
+	for _, m := range matches {
+		if !strings.Contains(m, "Preview") {
+			return m, nil
+		}
+	}
+	return matches[0], nil
+}
+
+// AppendProfile adds the profile to a Windows Terminal settings file. A profile
+// with the same GUID is left untouched. The original file is kept as settings.json.bak.
+func AppendProfile(settingsPath string, p Profile) error {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s': %w", settingsPath, err)
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse '%s' (comments or trailing commas are not supported): %w", settingsPath, err)
+	}
+
+	entry := map[string]interface{}{}
+	raw, _ := json.Marshal(p)
+	_ = json.Unmarshal(raw, &entry)
+
+	// "profiles" is either an object with a "list" array or a bare array
+	switch profiles := settings["profiles"].(type) {
+	case map[string]interface{}:
+		list, _ := profiles["list"].([]interface{})
+		if hasGUID(list, p.GUID) {
+			return nil
+		}
+		profiles["list"] = append(list, entry)
+	case []interface{}:
+		if hasGUID(profiles, p.GUID) {
+			return nil
+		}
+		settings["profiles"] = append(profiles, entry)
+	case nil:
+		settings["profiles"] = map[string]interface{}{"list": []interface{}{entry}}
+	default:
+		return fmt.Errorf("unexpected 'profiles' format in '%s'", settingsPath)
+	}
+
+	out, err := json.MarshalIndent(settings, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	if err := os.WriteFile(settingsPath+".bak", data, 0644); err != nil {
+		return fmt.Errorf("failed to back up '%s': %w", settingsPath, err)
+	}
+	if err := os.WriteFile(settingsPath, out, 0644); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", settingsPath, err)
+	}
+
+	return nil
+}
+
+// hasGUID reports whether a profile list already contains the given GUID
+func hasGUID(list []interface{}, guid string) bool {
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			if g, ok := m["guid"].(string); ok && strings.EqualFold(g, guid) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -56,17 +56,16 @@ func FindWingetDistroByVersion(version string) (*WingetDistro, error) {
 
 // FindWingetDistroByPackageID finds a distribution by its winget package ID
 func FindWingetDistroByPackageID(packageID string) (*WingetDistro, error) {
-	distros := distro.GetAllDistros()
-	for _, d := range distros {
-		if d.PackageID == packageID {
-			return &WingetDistro{
-				Name:         d.Version,
-				Version:      d.Version,
-				PackageID:    d.PackageID,
-				Group:        d.Group,
-				Architecture: d.Architecture,
-			}, nil
-		}
+	d, err := distro.FindDistroByPackageID(packageID)
+	if err != nil {
+		return nil, nil // Not found
 	}
-	return nil, nil // Not found
+
+	return &WingetDistro{
+		Name:         d.Version,
+		Version:      d.Version,
+		PackageID:    d.PackageID,
+		Group:        d.Group,
+		Architecture: d.Architecture,
+	}, nil
 }
@@ -2,22 +2,46 @@ package winget
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuanjua/autowsl/internal/checksum"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/downloader"
+	"github.com/yuanjua/autowsl/internal/runner"
 )
 
-// Manager handles WSL distribution downloads using winget
+// Manager handles WSL distribution downloads using winget, falling back to
+// the catalog's direct download URL when winget is unavailable
 type Manager struct {
 	downloader *WingetDownloader
+	direct     *downloader.Downloader
 	tempDir    string
+	runner     runner.Runner
 }
 
 // NewManager creates a new download manager
 func NewManager(tempDir string) *Manager {
 	return &Manager{
 		downloader: NewWingetDownloader(tempDir),
+		direct:     downloader.New(),
 		tempDir:    tempDir,
+		runner:     runner.NewExecRunner(0),
 	}
 }
 
+// SetRunner makes the manager run winget queries through r, e.g. a mock
+// runner in tests
+func (m *Manager) SetRunner(r runner.Runner) {
+	m.runner = r
+}
+
+// SetProgressOutput sets where direct-download progress is printed
+func (m *Manager) SetProgressOutput(w io.Writer) {
+	m.direct.SetProgressOutput(w)
+}
+
 // DownloadOptions contains options for downloading a distribution
 type DownloadOptions struct {
 	// Either specify a known version or a custom package ID
@@ -26,11 +50,39 @@ type DownloadOptions struct {
 
 	// If true, validate package ID before downloading
 	ValidatePackageID bool
+
+	// Catalog entry whose URL is downloaded directly when winget is not
+	// available, or always with ForceDirect
+	Distro      distro.Distro
+	ForceDirect bool
+
+	// If true, fail when the download does not match Distro.SHA256. Without
+	// a catalog checksum only a warning is printed.
+	VerifyChecksum bool
+
+	// Total attempts of a direct download, counting the first one. 0 keeps
+	// the downloader's default.
+	MaxAttempts int
 }
 
 // Download downloads a WSL distribution
 // Returns the path to the downloaded file
 func (m *Manager) Download(opts DownloadOptions) (string, error) {
+	m.direct.VerifyChecksum = opts.VerifyChecksum
+	if opts.MaxAttempts > 0 {
+		m.direct.MaxAttempts = opts.MaxAttempts
+	}
+	if opts.ForceDirect {
+		return m.downloadDirect(opts.Distro)
+	}
+	if !m.IsWingetAvailable() {
+		if opts.Distro.URL == "" {
+			return "", fmt.Errorf("winget is not available and '%s' has no direct download URL. Install 'App Installer' from the Microsoft Store, or add a url to the catalog entry", downloadName(opts))
+		}
+		fmt.Println("⚠ winget is not available, falling back to a direct download")
+		return m.downloadDirect(opts.Distro)
+	}
+
 	var packageID string
 
 	// Determine package ID
@@ -77,9 +129,50 @@ func (m *Manager) Download(opts DownloadOptions) (string, error) {
 		return "", err
 	}
 
+	if opts.VerifyChecksum {
+		if err := verifyDownload(downloadedFile, opts.Distro); err != nil {
+			return "", err
+		}
+	}
+
 	return downloadedFile, nil
 }
 
+// downloadDirect downloads the package at the distribution's URL into the
+// manager's directory, verifying its checksum when the catalog has one
+func (m *Manager) downloadDirect(d distro.Distro) (string, error) {
+	if d.URL == "" {
+		return "", fmt.Errorf("'%s' has no direct download URL", d.Version)
+	}
+	fmt.Printf("Downloading directly from %s\n", d.URL)
+	return m.direct.DownloadToDir(d, m.tempDir)
+}
+
+// verifyDownload checks a winget download against the catalog checksum. A
+// catalog entry without a checksum only produces a warning.
+func verifyDownload(path string, d distro.Distro) error {
+	if d.SHA256 == "" {
+		fmt.Printf("⚠ No checksum in the catalog for '%s', skipping verification\n", d.Version)
+		return nil
+	}
+	if err := checksum.VerifyFile(path, d.SHA256); err != nil {
+		return fmt.Errorf("checksum verification failed for '%s': %w", filepath.Base(path), err)
+	}
+	fmt.Println("✓ Checksum verified")
+	return nil
+}
+
+// downloadName names the requested distribution in error messages
+func downloadName(opts DownloadOptions) string {
+	switch {
+	case opts.Distro.Version != "":
+		return opts.Distro.Version
+	case opts.Version != "":
+		return opts.Version
+	}
+	return opts.PackageID
+}
+
 // GetCatalog returns the list of known distributions
 func (m *Manager) GetCatalog() []WingetDistro {
 	return GetWingetDistros()
@@ -95,6 +188,93 @@ func (m *Manager) GetWingetVersion() (string, error) {
 	return m.downloader.GetWingetVersion()
 }
 
+// ListInstalledWingetDistros returns the catalog distributions that winget
+// reports as installed, e.g. from the Microsoft Store. Name is the display
+// name winget shows for the package.
+func (m *Manager) ListInstalledWingetDistros() ([]WingetDistro, error) {
+	stdout, stderr, err := m.runner.Run("winget", "list", "--accept-source-agreements")
+	if err != nil {
+		return nil, fmt.Errorf("winget list failed: %w: %s", err, strings.TrimSpace(stderr))
+	}
+
+	var result []WingetDistro
+	for _, pkg := range parseWingetList(stdout) {
+		if !IsWSLPackage(pkg.ID) {
+			continue
+		}
+		d, _ := FindWingetDistroByPackageID(pkg.ID)
+		if d == nil {
+			continue
+		}
+		d.Name = pkg.Name
+		result = append(result, *d)
+	}
+	return result, nil
+}
+
+// parseWingetList parses the table printed by "winget list", slicing rows at
+// the header's column positions because names contain spaces. Progress
+// output before the header is ignored.
+func parseWingetList(output string) []PackageInfo {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i := range lines {
+		// winget redraws its spinner with carriage returns on the header line
+		if idx := strings.LastIndex(lines[i], "\r"); idx >= 0 {
+			lines[i] = lines[i][idx+1:]
+		}
+	}
+
+	var columns []int
+	var packages []PackageInfo
+	for i, line := range lines {
+		if columns == nil {
+			if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "---") {
+				columns = wingetListColumns(line)
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "---") {
+			continue
+		}
+
+		runes := []rune(line)
+		field := func(c int) string {
+			if c >= len(columns) || columns[c] >= len(runes) {
+				return ""
+			}
+			end := len(runes)
+			if c+1 < len(columns) && columns[c+1] < end {
+				end = columns[c+1]
+			}
+			return strings.TrimSpace(string(runes[columns[c]:end]))
+		}
+		pkg := PackageInfo{Name: field(0), ID: field(1), Version: field(2)}
+		if len(columns) > 3 {
+			pkg.Source = field(len(columns) - 1)
+		}
+		if pkg.ID != "" {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// wingetListColumns returns the start of each column in a "winget list"
+// header, or nil if it has fewer than the name, ID and version columns
+func wingetListColumns(header string) []int {
+	runes := []rune(header)
+	var columns []int
+	for i, r := range runes {
+		if r != ' ' && (i == 0 || runes[i-1] == ' ') {
+			columns = append(columns, i)
+		}
+	}
+	if len(columns) < 3 {
+		return nil
+	}
+	return columns
+}
+
 // CleanupDownloadDir removes the temporary download directory
 func (m *Manager) CleanupDownloadDir() error {
 	// Don't cleanup if user might want to keep files
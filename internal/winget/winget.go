@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/yuanjua/autowsl/internal/checksum"
 )
 
 // WingetDownloader handles downloading WSL distributions using winget
@@ -55,6 +57,13 @@ func (w *WingetDownloader) Download(packageID string) (string, error) {
 	}
 
 	fmt.Printf("\nDownload completed: %s\n", filepath.Base(downloadedFile))
+
+	// Record the checksum so the package can be verified later
+	sum, err := checksum.WriteSidecar(downloadedFile)
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("SHA256: %s\n", sum)
 	return downloadedFile, nil
 }
 
@@ -1,11 +1,17 @@
 package wsl
 
-import "github.com/yuanjua/autowsl/internal/runner"
+import (
+	"os"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/runner"
+)
 
 // Client is a wrapper for executing WSL commands.
 // It uses dependency injection to allow for easy testing.
 type Client struct {
 	runner runner.Runner
+	dryRun bool // Skip file-system side effects; the runner decides what running a command means
 }
 
 // NewClient creates a new WSL client with the provided runner.
@@ -13,7 +19,73 @@ func NewClient(r runner.Runner) *Client {
 	return &Client{runner: r}
 }
 
+// dryRun makes DefaultClient print commands instead of executing them.
+var dryRun bool
+
+// SetDryRun enables or disables dry-run mode for clients returned by DefaultClient.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// wslPath overrides the wsl.exe binary of clients returned by DefaultClient.
+var wslPath string
+
+// SetWSLPath sets the wsl.exe binary used by clients returned by DefaultClient.
+// An empty path uses wsl.exe from PATH.
+func SetWSLPath(path string) {
+	wslPath = path
+}
+
+// timeout limits each command run by clients returned by DefaultClient.
+var timeout time.Duration
+
+// SetTimeout sets how long a command run by clients returned by DefaultClient
+// may take before it is killed. 0 means no limit.
+func SetTimeout(d time.Duration) {
+	timeout = d
+}
+
+// defaultRunner replaces the runner of clients returned by DefaultClient when set.
+var defaultRunner runner.Runner
+
+// SetRunner makes clients returned by DefaultClient run commands through r,
+// e.g. a mock runner in tests. nil restores the real runner.
+func SetRunner(r runner.Runner) {
+	defaultRunner = r
+}
+
 // DefaultClient returns a client configured with default settings.
 func DefaultClient() *Client {
-	return NewClient(runner.NewExecRunner(0)) // 0 = no timeout
+	if defaultRunner != nil {
+		return &Client{runner: defaultRunner, dryRun: dryRun}
+	}
+	r := runner.NewExecRunner(timeout) // 0 = no timeout
+	if wslPath != "" {
+		r.WSLPath = wslPath
+	}
+	if dryRun {
+		r.DryRun = true
+		r.Out = os.Stdout
+	}
+	return NewClient(r)
+}
+
+// isDryRun reports whether the client's runner only prints commands, or the
+// client was created by DefaultClient in dry-run mode with a substitute runner.
+// File-system side effects are skipped as well in that case.
+func (c *Client) isDryRun() bool {
+	if c.dryRun {
+		return true
+	}
+	r, ok := c.runner.(*runner.ExecRunner)
+	return ok && r.DryRun
+}
+
+// wslBinary returns the wsl.exe binary configured on the client's runner, for
+// commands that are attached to the terminal instead of run by the runner
+func (c *Client) wslBinary() string {
+	if r, ok := c.runner.(*runner.ExecRunner); ok && r.WSLPath != "" {
+		return r.WSLPath
+	}
+	return runner.DefaultWSLPath
 }
@@ -0,0 +1,133 @@
+package wsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/runner"
+)
+
+// RunCommandOptions controls how a command is executed inside a distribution
+type RunCommandOptions struct {
+	CaptureOutput bool          // Buffer output through the client's runner instead of attaching to the terminal
+	User          string        // Run as this user (e.g. "root"); empty uses the distro default
+	Timeout       time.Duration // 0 = no timeout
+	Stdout        io.Writer     // Destination for captured stdout (default os.Stdout)
+	Stderr        io.Writer     // Destination for captured stderr (default os.Stderr)
+}
+
+// RunCommand executes a command inside a WSL distribution.
+// When the guest command exits non-zero the returned error wraps *exec.ExitError
+// so callers can propagate the exit code.
+func (c *Client) RunCommand(distroName string, args []string, opts RunCommandOptions) error {
+	if distroName == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("command cannot be empty")
+	}
+
+	wslArgs := buildRunArgs(distroName, args, opts.User)
+
+	if opts.CaptureOutput {
+		r := c.runner
+		if opts.Timeout > 0 {
+			// Honour the per-call timeout when running real commands
+			if er, ok := r.(*runner.ExecRunner); ok {
+				withTimeout := *er
+				withTimeout.Timeout = opts.Timeout
+				r = &withTimeout
+			}
+		}
+
+		stdout, stderr, err := r.Run("wsl.exe", wslArgs...)
+
+		outW, errW := opts.Stdout, opts.Stderr
+		if outW == nil {
+			outW = os.Stdout
+		}
+		if errW == nil {
+			errW = os.Stderr
+		}
+		_, _ = io.WriteString(outW, stdout)
+		_, _ = io.WriteString(errW, stderr)
+
+		if err != nil {
+			return fmt.Errorf("command failed in '%s': %w", distroName, err)
+		}
+		return nil
+	}
+
+	if c.isDryRun() {
+		fmt.Println("[dry-run] " + runner.FormatCommand(c.wslBinary(), wslArgs...))
+		return nil
+	}
+
+	// Only real commands can be attached to the terminal; other runners get
+	// the command as is
+	er, ok := c.runner.(*runner.ExecRunner)
+	if !ok {
+		stdout, stderr, err := c.runner.Run("wsl.exe", wslArgs...)
+		_, _ = io.WriteString(os.Stdout, stdout)
+		_, _ = io.WriteString(os.Stderr, stderr)
+		if err != nil {
+			return fmt.Errorf("command failed in '%s': %w", distroName, err)
+		}
+		return nil
+	}
+
+	// Attach the command to the current terminal; without a per-call timeout
+	// the runner's applies
+	if opts.Timeout == 0 {
+		opts.Timeout = er.Timeout
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.wslBinary(), wslArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("command in '%s' timed out after %s: %w", distroName, opts.Timeout, context.DeadlineExceeded)
+		}
+		return fmt.Errorf("command failed in '%s': %w", distroName, err)
+	}
+	return nil
+}
+
+// buildRunArgs builds the wsl.exe arguments for running a command in a distro
+func buildRunArgs(distroName string, args []string, user string) []string {
+	wslArgs := []string{"-d", distroName}
+	if user != "" {
+		wslArgs = append(wslArgs, "-u", user)
+	}
+	wslArgs = append(wslArgs, "--")
+	return append(wslArgs, args...)
+}
+
+// RunCommand executes a command inside a WSL distribution (uses default client)
+func RunCommand(distroName string, args []string, opts RunCommandOptions) error {
+	return DefaultClient().RunCommand(distroName, args, opts)
+}
+
+// WindowsPath returns the \\wsl$ path under which Windows sees a file of a
+// running distribution, e.g. /tmp/site.yml becomes \\wsl$\Ubuntu\tmp\site.yml
+func WindowsPath(distroName, linuxPath string) string {
+	rel := strings.TrimPrefix(linuxPath, "/")
+	return `\\wsl$\` + distroName + `\` + strings.ReplaceAll(rel, "/", `\`)
+}
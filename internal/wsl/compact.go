@@ -0,0 +1,102 @@
+package wsl
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yuanjua/autowsl/internal/system"
+)
+
+// Compact reclaims unused space in a WSL 2 distribution's virtual disk. The
+// distribution is stopped first. On builds that support sparse VHDs the VHD
+// is switched to sparse mode with wsl --manage; otherwise it is compacted
+// with diskpart, which requires an elevated prompt.
+func (c *Client) Compact(name string) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+
+	distros, err := c.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	version := ""
+	for _, d := range distros {
+		if d.Name == name {
+			version = d.Version
+		}
+	}
+	if version == "" && !c.isDryRun() {
+		return fmt.Errorf("distribution '%s' does not exist", name)
+	}
+	if version == "1" {
+		return fmt.Errorf("distribution '%s' uses WSL 1, which has no virtual disk to compact", name)
+	}
+
+	if err := c.Stop(name); err != nil {
+		return err
+	}
+
+	if build, err := c.windowsBuild(); err == nil && system.FeatureSupported(system.FeatureSparseVHD, build) == nil {
+		_, stderr, err := c.runner.Run("wsl.exe", "--manage", name, "--set-sparse", "true")
+		if err != nil {
+			return fmt.Errorf("failed to enable sparse VHD: %w\nOutput: %s", err, stderr)
+		}
+		return nil
+	}
+
+	vhdPath, err := c.VHDPath(name)
+	if err != nil {
+		return err
+	}
+	return c.compactWithDiskpart(vhdPath)
+}
+
+// compactWithDiskpart runs the classic attach/compact/detach diskpart sequence
+func (c *Client) compactWithDiskpart(vhdPath string) error {
+	script := strings.Join([]string{
+		fmt.Sprintf(`select vdisk file="%s"`, vhdPath),
+		"attach vdisk readonly",
+		"compact vdisk",
+		"detach vdisk",
+	}, "\r\n") + "\r\n"
+
+	scriptFile, err := os.CreateTemp("", "autowsl-compact-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create diskpart script: %w", err)
+	}
+	defer os.Remove(scriptFile.Name())
+	if _, err := scriptFile.WriteString(script); err != nil {
+		scriptFile.Close()
+		return fmt.Errorf("failed to write diskpart script: %w", err)
+	}
+	scriptFile.Close()
+
+	output, stderr, err := c.runner.Run("diskpart.exe", "/s", scriptFile.Name())
+	if err != nil {
+		return fmt.Errorf("diskpart failed (run from an elevated prompt): %w\nOutput: %s%s", err, output, stderr)
+	}
+	return nil
+}
+
+// windowsBuild reads the Windows build number from the registry
+func (c *Client) windowsBuild() (int, error) {
+	output, stderr, err := c.runner.Run("reg.exe", "query", `HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion`, "/v", "CurrentBuild")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read Windows build: %w\nOutput: %s", err, stderr)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "CurrentBuild" {
+			return strconv.Atoi(fields[2])
+		}
+	}
+	return 0, fmt.Errorf("windows build not found in registry output")
+}
+
+// Compact reclaims unused VHD space (uses default client)
+func Compact(name string) error {
+	return DefaultClient().Compact(name)
+}
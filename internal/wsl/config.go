@@ -0,0 +1,221 @@
+package wsl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WSLConf is the contents of /etc/wsl.conf inside a distribution. Unset
+// values are left out when the file is written.
+type WSLConf struct {
+	Boot      BootConf
+	Network   NetworkConf
+	Automount AutomountConf
+	Interop   InteropConf
+	User      UserConf
+
+	// Extra holds sections and keys autowsl doesn't model so they survive a rewrite
+	Extra map[string]map[string]string
+}
+
+// BootConf is the [boot] section of wsl.conf
+type BootConf struct {
+	Systemd *bool
+	Command string
+}
+
+// NetworkConf is the [network] section of wsl.conf
+type NetworkConf struct {
+	Hostname           string
+	GenerateHosts      *bool
+	GenerateResolvConf *bool
+}
+
+// AutomountConf is the [automount] section of wsl.conf
+type AutomountConf struct {
+	Enabled    *bool
+	Root       string
+	Options    string
+	MountFsTab *bool
+}
+
+// InteropConf is the [interop] section of wsl.conf
+type InteropConf struct {
+	Enabled           *bool
+	AppendWindowsPath *bool
+}
+
+// UserConf is the [user] section of wsl.conf
+type UserConf struct {
+	Default string
+}
+
+// confField binds a "section.key" name to a string or bool field of WSLConf
+type confField struct {
+	key  string
+	str  func(c *WSLConf) *string
+	flag func(c *WSLConf) **bool
+}
+
+// confFields lists the modelled keys in the order they are written
+var confFields = []confField{
+	{key: "boot.systemd", flag: func(c *WSLConf) **bool { return &c.Boot.Systemd }},
+	{key: "boot.command", str: func(c *WSLConf) *string { return &c.Boot.Command }},
+	{key: "network.hostname", str: func(c *WSLConf) *string { return &c.Network.Hostname }},
+	{key: "network.generateHosts", flag: func(c *WSLConf) **bool { return &c.Network.GenerateHosts }},
+	{key: "network.generateResolvConf", flag: func(c *WSLConf) **bool { return &c.Network.GenerateResolvConf }},
+	{key: "automount.enabled", flag: func(c *WSLConf) **bool { return &c.Automount.Enabled }},
+	{key: "automount.root", str: func(c *WSLConf) *string { return &c.Automount.Root }},
+	{key: "automount.options", str: func(c *WSLConf) *string { return &c.Automount.Options }},
+	{key: "automount.mountFsTab", flag: func(c *WSLConf) **bool { return &c.Automount.MountFsTab }},
+	{key: "interop.enabled", flag: func(c *WSLConf) **bool { return &c.Interop.Enabled }},
+	{key: "interop.appendWindowsPath", flag: func(c *WSLConf) **bool { return &c.Interop.AppendWindowsPath }},
+	{key: "user.default", str: func(c *WSLConf) *string { return &c.User.Default }},
+}
+
+// findConfField looks up a modelled key; wsl.conf keys are case-insensitive
+func findConfField(key string) *confField {
+	for i := range confFields {
+		if strings.EqualFold(confFields[i].key, key) {
+			return &confFields[i]
+		}
+	}
+	return nil
+}
+
+// Set assigns a value by "section.key" name, e.g. "boot.systemd". Keys that
+// are not modelled are stored in Extra.
+func (c *WSLConf) Set(key, value string) error {
+	section, name, ok := strings.Cut(key, ".")
+	if !ok || section == "" || name == "" {
+		return fmt.Errorf("invalid wsl.conf key '%s' (expected section.key)", key)
+	}
+
+	f := findConfField(key)
+	switch {
+	case f == nil:
+		if c.Extra == nil {
+			c.Extra = make(map[string]map[string]string)
+		}
+		if c.Extra[section] == nil {
+			c.Extra[section] = make(map[string]string)
+		}
+		c.Extra[section][name] = value
+	case f.flag != nil:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for '%s': %s (expected true or false)", key, value)
+		}
+		*f.flag(c) = &b
+	default:
+		*f.str(c) = value
+	}
+	return nil
+}
+
+// ParseConf parses wsl.conf INI text
+func ParseConf(text string) (WSLConf, error) {
+	var conf WSLConf
+	section := ""
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || section == "" {
+			return conf, fmt.Errorf("wsl.conf line %d: unexpected %q", i+1, line)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if err := conf.Set(section+"."+strings.TrimSpace(name), value); err != nil {
+			return conf, fmt.Errorf("wsl.conf line %d: %w", i+1, err)
+		}
+	}
+	return conf, nil
+}
+
+// String renders the config as wsl.conf INI text
+func (c WSLConf) String() string {
+	sections := make(map[string][]string)
+	var order []string
+	add := func(section, line string) {
+		if _, ok := sections[section]; !ok {
+			order = append(order, section)
+		}
+		sections[section] = append(sections[section], line)
+	}
+
+	for _, f := range confFields {
+		section, name, _ := strings.Cut(f.key, ".")
+		if f.flag != nil {
+			if v := *f.flag(&c); v != nil {
+				add(section, fmt.Sprintf("%s=%t", name, *v))
+			}
+		} else if v := *f.str(&c); v != "" {
+			add(section, fmt.Sprintf("%s=%s", name, v))
+		}
+	}
+
+	extraSections := make([]string, 0, len(c.Extra))
+	for section := range c.Extra {
+		extraSections = append(extraSections, section)
+	}
+	sort.Strings(extraSections)
+	for _, section := range extraSections {
+		names := make([]string, 0, len(c.Extra[section]))
+		for name := range c.Extra[section] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			add(section, fmt.Sprintf("%s=%s", name, c.Extra[section][name]))
+		}
+	}
+
+	var b strings.Builder
+	for i, section := range order {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", section)
+		for _, line := range sections[section] {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ReadConf reads /etc/wsl.conf from a distribution. A missing file yields an empty config.
+func (c *Client) ReadConf(distroName string) (WSLConf, error) {
+	output, stderr, err := c.runner.Run("wsl.exe", "-d", distroName, "sh", "-c", "cat /etc/wsl.conf 2>/dev/null || true")
+	if err != nil {
+		return WSLConf{}, fmt.Errorf("failed to read wsl.conf in '%s': %w\nOutput: %s", distroName, err, stderr)
+	}
+	return ParseConf(strings.ReplaceAll(output, "\r\n", "\n"))
+}
+
+// WriteConf replaces /etc/wsl.conf in a distribution. Comments in the old file
+// are not kept. Changes apply after the distribution is restarted.
+func (c *Client) WriteConf(distroName string, conf WSLConf) error {
+	_, stderr, err := c.runner.RunWithInput("wsl.exe", conf.String(), "-d", distroName, "-u", "root", "sh", "-c", "cat > /etc/wsl.conf")
+	if err != nil {
+		return fmt.Errorf("failed to write wsl.conf in '%s': %w\nOutput: %s", distroName, err, stderr)
+	}
+	return nil
+}
+
+// ReadConf reads /etc/wsl.conf from a distribution (uses default client)
+func ReadConf(distroName string) (WSLConf, error) {
+	return DefaultClient().ReadConf(distroName)
+}
+
+// WriteConf replaces /etc/wsl.conf in a distribution (uses default client)
+func WriteConf(distroName string, conf WSLConf) error {
+	return DefaultClient().WriteConf(distroName, conf)
+}
@@ -1,9 +1,19 @@
 package wsl
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/downloader"
+	"github.com/yuanjua/autowsl/internal/runner"
 )
 
 // ImportOptions contains options for importing a WSL distribution
@@ -12,6 +22,8 @@ type ImportOptions struct {
 	InstallPath string // Custom installation path
 	TarFilePath string // Path to the tar file
 	Version     int    // WSL version (1 or 2)
+
+	KeepTar bool // ImportFromURL: keep the downloaded tarball after a successful import
 }
 
 // Import imports a WSL distribution from a tar file
@@ -26,15 +38,20 @@ func (c *Client) Import(opts ImportOptions) error {
 	if opts.TarFilePath == "" {
 		return fmt.Errorf("tar file path cannot be empty")
 	}
+	if err := ValidateDistroName(opts.Name); err != nil {
+		return err
+	}
 
 	// Check if tar file exists
-	if _, err := os.Stat(opts.TarFilePath); os.IsNotExist(err) {
+	if _, err := os.Stat(opts.TarFilePath); os.IsNotExist(err) && !c.isDryRun() {
 		return fmt.Errorf("tar file does not exist: %s", opts.TarFilePath)
 	}
 
 	// Create installation directory if it doesn't exist
-	if err := os.MkdirAll(opts.InstallPath, 0755); err != nil {
-		return fmt.Errorf("failed to create installation directory: %w", err)
+	if !c.isDryRun() {
+		if err := os.MkdirAll(opts.InstallPath, 0755); err != nil {
+			return fmt.Errorf("failed to create installation directory: %w", err)
+		}
 	}
 
 	// Check if distro already exists
@@ -72,6 +89,55 @@ func (c *Client) Import(opts ImportOptions) error {
 	return nil
 }
 
+// ImportDownloadDir returns the directory ImportFromURL downloads the tarball
+// for a distribution into. A partial download left there is resumed.
+func ImportDownloadDir(name string) string {
+	return filepath.Join(os.TempDir(), "autowsl-import-"+name)
+}
+
+// ImportFromURL downloads a rootfs tarball from url into ImportDownloadDir and
+// imports it with the given options; opts.TarFilePath is ignored. The download
+// is removed after a successful import unless opts.KeepTar is set.
+func (c *Client) ImportFromURL(url string, opts ImportOptions) error {
+	if url == "" {
+		return fmt.Errorf("download URL cannot be empty")
+	}
+	if err := ValidateDistroName(opts.Name); err != nil {
+		return err
+	}
+
+	// Fail before a potentially large download if the name is taken
+	exists, err := c.IsDistroInstalled(opts.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("distribution '%s' already exists", opts.Name)
+	}
+
+	dir := ImportDownloadDir(opts.Name)
+	if c.isDryRun() {
+		fmt.Printf("[dry-run] would download %s into %s\n", url, dir)
+		opts.TarFilePath = filepath.Join(dir, "install.tar.gz")
+		return c.Import(opts)
+	}
+
+	tarPath, err := downloader.New().DownloadToDir(distro.Distro{Version: opts.Name, URL: url}, dir)
+	if err != nil {
+		return fmt.Errorf("failed to download '%s': %w", url, err)
+	}
+
+	opts.TarFilePath = tarPath
+	if err := c.Import(opts); err != nil {
+		return err
+	}
+
+	if !opts.KeepTar {
+		_ = os.RemoveAll(dir)
+	}
+	return nil
+}
+
 // Unregister removes a WSL distribution
 func (c *Client) Unregister(name string) error {
 	if name == "" {
@@ -83,7 +149,7 @@ func (c *Client) Unregister(name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to check if distro exists: %w", err)
 	}
-	if !exists {
+	if !exists && !c.isDryRun() {
 		return fmt.Errorf("distribution '%s' does not exist", name)
 	}
 
@@ -110,14 +176,16 @@ func (c *Client) Export(name, outputPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to check if distro exists: %w", err)
 	}
-	if !exists {
+	if !exists && !c.isDryRun() {
 		return fmt.Errorf("distribution '%s' does not exist", name)
 	}
 
 	// Create output directory if needed
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	if !c.isDryRun() {
+		outputDir := filepath.Dir(outputPath)
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
 	}
 
 	// Execute wsl --export command
@@ -129,6 +197,492 @@ func (c *Client) Export(name, outputPath string) error {
 	return nil
 }
 
+// ConvertVersion converts a distribution between WSL 1 and WSL 2 with
+// wsl --set-version. Conversion can take minutes, so when running real
+// commands the output is streamed to the terminal instead of buffered.
+func (c *Client) ConvertVersion(name string, targetVersion int) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+	if targetVersion != 1 && targetVersion != 2 {
+		return fmt.Errorf("invalid WSL version %d (must be 1 or 2)", targetVersion)
+	}
+
+	distros, err := c.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	found := false
+	for _, d := range distros {
+		if d.Name == name {
+			found = true
+			if d.Version == strconv.Itoa(targetVersion) {
+				return nil // Already at the target version
+			}
+		}
+	}
+	if !found && !c.isDryRun() {
+		return fmt.Errorf("distribution '%s' does not exist", name)
+	}
+
+	args := []string{"--set-version", name, strconv.Itoa(targetVersion)}
+
+	if r, ok := c.runner.(*runner.ExecRunner); ok && !r.DryRun {
+		cmd := exec.Command(c.wslBinary(), args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to convert distribution to WSL %d: %w", targetVersion, err)
+		}
+		return nil
+	}
+
+	_, stderr, err := c.runner.Run("wsl.exe", args...)
+	if err != nil {
+		return fmt.Errorf("failed to convert distribution to WSL %d: %w\nOutput: %s", targetVersion, err, stderr)
+	}
+	return nil
+}
+
+// Compression formats supported by ExportWithOptions
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionXz   = "xz"
+)
+
+// ExportVHD exports a WSL 2 distribution as a .vhdx disk image with
+// wsl --export --vhd, which needs Windows 11
+func (c *Client) ExportVHD(name, outputPath string) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+	if outputPath == "" {
+		return fmt.Errorf("output path cannot be empty")
+	}
+
+	exists, err := c.IsDistroInstalled(name)
+	if err != nil {
+		return fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	if !exists && !c.isDryRun() {
+		return fmt.Errorf("distribution '%s' does not exist", name)
+	}
+
+	if !c.isDryRun() {
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	_, stderr, err := c.runner.Run("wsl.exe", "--export", name, outputPath, "--vhd")
+	if err != nil {
+		return fmt.Errorf("failed to export distribution as VHD: %w\nOutput: %s", err, stderr)
+	}
+	return nil
+}
+
+// ExportOptions contains options for exporting a WSL distribution
+type ExportOptions struct {
+	Name        string // Name of the distribution
+	OutputPath  string // Path of the uncompressed .tar; compressed exports add .gz or .xz
+	Compression string // CompressionNone (default), CompressionGzip or CompressionXz
+}
+
+// ExportResult describes the file produced by ExportWithOptions
+type ExportResult struct {
+	Path             string // Final path of the export
+	Size             int64  // Size of the final file in bytes
+	UncompressedSize int64  // Size of the raw tar before compression
+}
+
+// ExportWithOptions exports a distribution to a tar file and optionally
+// compresses it. The raw tar is removed once compression succeeds.
+func (c *Client) ExportWithOptions(opts ExportOptions) (*ExportResult, error) {
+	compression := opts.Compression
+	if compression == "" {
+		compression = CompressionNone
+	}
+	if compression != CompressionNone && compression != CompressionGzip && compression != CompressionXz {
+		return nil, fmt.Errorf("unsupported compression '%s' (must be none, gzip, or xz)", opts.Compression)
+	}
+
+	if err := c.Export(opts.Name, opts.OutputPath); err != nil {
+		return nil, err
+	}
+
+	finalPath := opts.OutputPath
+	switch compression {
+	case CompressionGzip:
+		finalPath += ".gz"
+	case CompressionXz:
+		finalPath += ".xz"
+	}
+	if c.isDryRun() {
+		return &ExportResult{Path: finalPath}, nil
+	}
+
+	info, err := os.Stat(opts.OutputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exported file: %w", err)
+	}
+	result := &ExportResult{Path: opts.OutputPath, Size: info.Size(), UncompressedSize: info.Size()}
+
+	switch compression {
+	case CompressionGzip:
+		err = gzipFile(opts.OutputPath, finalPath)
+	case CompressionXz:
+		err = c.xzFile(opts.OutputPath, finalPath)
+	default:
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Remove(opts.OutputPath)
+
+	info, err = os.Stat(finalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed file: %w", err)
+	}
+	result.Path = finalPath
+	result.Size = info.Size()
+	return result, nil
+}
+
+// gzipFile compresses src into dst, writing to a temporary file first so an
+// interrupted compression never leaves a truncated archive behind
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", src, err)
+	}
+	defer in.Close()
+
+	partial := dst + ".partial"
+	out, err := os.Create(partial)
+	if err != nil {
+		return fmt.Errorf("failed to create '%s': %w", partial, err)
+	}
+
+	zw := gzip.NewWriter(out)
+	_, copyErr := io.Copy(zw, in)
+	closeErr := zw.Close()
+	fileErr := out.Close()
+	if err := errors.Join(copyErr, closeErr, fileErr); err != nil {
+		_ = os.Remove(partial)
+		return fmt.Errorf("failed to compress '%s': %w", filepath.Base(src), err)
+	}
+
+	if err := os.Rename(partial, dst); err != nil {
+		_ = os.Remove(partial)
+		return fmt.Errorf("failed to rename compressed file: %w", err)
+	}
+	return nil
+}
+
+// xzFile compresses src into dst with the host's xz binary
+func (c *Client) xzFile(src, dst string) error {
+	if _, err := exec.LookPath("xz"); err != nil {
+		return fmt.Errorf("xz compression requires the 'xz' command in PATH: %w", err)
+	}
+
+	// xz -k keeps the source and writes <src>.xz next to it
+	_, stderr, err := c.runner.Run("xz", "-z", "-k", "-f", "-T0", src)
+	if err != nil {
+		_ = os.Remove(src + ".xz")
+		return fmt.Errorf("failed to compress '%s' with xz: %w\nOutput: %s", filepath.Base(src), err, stderr)
+	}
+
+	if src+".xz" != dst {
+		if err := os.Rename(src+".xz", dst); err != nil {
+			return fmt.Errorf("failed to rename compressed file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Rename renames a WSL distribution by exporting it to a temporary tar file,
+// importing it under the new name next to the original install location, and
+// only then unregistering the old name. If the import fails (for example
+// because the disk is full) the original distribution is left untouched.
+func (c *Client) Rename(oldName, newName string) error {
+	if oldName == "" || newName == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+	if oldName == newName {
+		return fmt.Errorf("new name must differ from the current name")
+	}
+	if err := ValidateDistroName(newName); err != nil {
+		return err
+	}
+
+	// Check both names against the installed distributions
+	distros, err := c.ListInstalledDistros()
+	if err != nil {
+		return fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	var oldExists, newExists bool
+	version := 2
+	for _, d := range distros {
+		if d.Name == oldName {
+			oldExists = true
+			if d.Version == "1" {
+				version = 1
+			}
+		}
+		if d.Name == newName {
+			newExists = true
+		}
+	}
+	if !oldExists && !c.isDryRun() {
+		return fmt.Errorf("distribution '%s' does not exist", oldName)
+	}
+	if newExists {
+		return fmt.Errorf("distribution '%s' already exists", newName)
+	}
+
+	// Place the renamed distribution next to the original one. Fall back to the
+	// default install root when the original location cannot be determined.
+	cwd, _ := os.Getwd()
+	newPath := filepath.Join(cwd, "wsl-distros", newName)
+	oldPath, err := c.GetInstallPath(oldName)
+	if err == nil && oldPath != "" {
+		newPath = filepath.Join(filepath.Dir(oldPath), newName)
+	}
+
+	tempDir := filepath.Join(cwd, ".autowsl_tmp")
+	tarPath := filepath.Join(tempDir, fmt.Sprintf("%s-rename.tar", oldName))
+	defer func() {
+		_ = os.Remove(tarPath)
+		_ = os.Remove(tempDir) // only succeeds if nothing else is in there
+	}()
+
+	if err := c.Export(oldName, tarPath); err != nil {
+		return err
+	}
+
+	if err := c.Import(ImportOptions{Name: newName, InstallPath: newPath, TarFilePath: tarPath, Version: version}); err != nil {
+		_ = os.Remove(newPath)
+		if isDiskSpaceError(err) {
+			return fmt.Errorf("not enough disk space to import '%s' (original '%s' left unchanged): %w", newName, oldName, err)
+		}
+		return fmt.Errorf("failed to import '%s' (original '%s' left unchanged): %w", newName, oldName, err)
+	}
+
+	if err := c.Unregister(oldName); err != nil {
+		return fmt.Errorf("imported '%s' but failed to unregister '%s': %w", newName, oldName, err)
+	}
+
+	// wsl --unregister removes the disk image but leaves the directory behind
+	if oldPath != "" {
+		_ = os.Remove(oldPath)
+	}
+
+	return nil
+}
+
+// MoveOptions contains options for moving a distribution to a new location
+type MoveOptions struct {
+	Name         string // Distribution to move
+	NewPath      string // New installation directory
+	TempDir      string // Where the intermediate tar is written
+	KeepOriginal bool   // Import as a copy named <Name>-moved and leave the original registered
+}
+
+// Move relocates a distribution by exporting it to a tar file in TempDir and
+// importing it at NewPath under a temporary name. Only once that import has
+// succeeded is the original unregistered and the copy given its name, so a
+// failed import leaves the original untouched.
+// With KeepOriginal the copy is imported under "<name>-moved" and the
+// original stays registered so it can be verified before removal.
+// It returns the name the moved distribution is registered under.
+func (c *Client) Move(opts MoveOptions) (string, error) {
+	if opts.Name == "" {
+		return "", fmt.Errorf("distribution name cannot be empty")
+	}
+	if opts.NewPath == "" {
+		return "", fmt.Errorf("new path cannot be empty")
+	}
+
+	distros, err := c.ListInstalledDistros()
+	if err != nil {
+		return "", fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	found := false
+	version := 2
+	for _, d := range distros {
+		if d.Name == opts.Name {
+			found = true
+			if d.Version == "1" {
+				version = 1
+			}
+		}
+	}
+	if !found && !c.isDryRun() {
+		return "", fmt.Errorf("distribution '%s' does not exist", opts.Name)
+	}
+
+	oldPath, err := c.GetInstallPath(opts.Name)
+	if err != nil && !c.isDryRun() {
+		return "", err
+	}
+
+	tarPath := filepath.Join(opts.TempDir, fmt.Sprintf("%s-move.tar", opts.Name))
+	defer func() {
+		_ = os.Remove(tarPath)
+		_ = os.Remove(opts.TempDir) // only succeeds if nothing else is in there
+	}()
+
+	if err := c.Export(opts.Name, tarPath); err != nil {
+		return "", err
+	}
+
+	if opts.KeepOriginal {
+		newName := opts.Name + "-moved"
+		if err := c.Import(ImportOptions{Name: newName, InstallPath: opts.NewPath, TarFilePath: tarPath, Version: version}); err != nil {
+			return "", fmt.Errorf("failed to import '%s' (original '%s' left unchanged): %w", newName, opts.Name, err)
+		}
+		return newName, nil
+	}
+
+	tempName := opts.Name + "-moving"
+	if err := c.Import(ImportOptions{Name: tempName, InstallPath: opts.NewPath, TarFilePath: tarPath, Version: version}); err != nil {
+		return "", fmt.Errorf("failed to import at '%s' (original '%s' left unchanged): %w", opts.NewPath, opts.Name, err)
+	}
+
+	if err := c.Unregister(opts.Name); err != nil {
+		// Drop the copy so the original is the only registration again
+		_ = c.Unregister(tempName)
+		return "", fmt.Errorf("failed to unregister '%s' (nothing was moved): %w", opts.Name, err)
+	}
+
+	if err := c.setRegisteredName(tempName, opts.Name); err != nil {
+		return tempName, fmt.Errorf("moved '%s' but it is still registered as '%s': %w (rename it with 'autowsl rename %s %s')",
+			opts.Name, tempName, err, tempName, opts.Name)
+	}
+
+	// wsl --unregister removes the disk image but leaves the directory behind
+	if oldPath != "" {
+		_ = os.Remove(oldPath)
+	}
+
+	return opts.Name, nil
+}
+
+// GetInstallPath returns the BasePath that WSL recorded for a distribution in
+// the registry (HKCU\Software\Microsoft\Windows\CurrentVersion\Lxss).
+func (c *Client) GetInstallPath(name string) (string, error) {
+	paths, err := c.InstallPaths()
+	if err != nil {
+		return "", err
+	}
+	if p, ok := paths[name]; ok {
+		return p, nil
+	}
+	return "", fmt.Errorf("install location for '%s' not found", name)
+}
+
+// lxssKey is the registry key under which WSL registers distributions
+const lxssKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\Lxss`
+
+// InstallPaths returns the install location of every registered distribution,
+// keyed by distribution name, from a single registry query.
+func (c *Client) InstallPaths() (map[string]string, error) {
+	output, stderr, err := c.runner.Run("reg.exe", "query", lxssKey, "/s")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query WSL registry: %w\nOutput: %s", err, stderr)
+	}
+	return parseLxssRegistry(output), nil
+}
+
+// setRegisteredName changes the name a stopped distribution is registered
+// under by editing DistributionName in its Lxss registry key
+func (c *Client) setRegisteredName(oldName, newName string) error {
+	if c.isDryRun() {
+		fmt.Printf("[dry-run] would rename '%s' to '%s' in %s\n", oldName, newName, lxssKey)
+		return nil
+	}
+	output, stderr, err := c.runner.Run("reg.exe", "query", lxssKey, "/s")
+	if err != nil {
+		return fmt.Errorf("failed to query WSL registry: %w\nOutput: %s", err, stderr)
+	}
+	for _, e := range parseLxssEntries(output) {
+		if e.name != oldName {
+			continue
+		}
+		_, stderr, err := c.runner.Run("reg.exe", "add", e.key, "/v", "DistributionName", "/t", "REG_SZ", "/d", newName, "/f")
+		if err != nil {
+			return fmt.Errorf("failed to rename '%s' in the registry: %w\nOutput: %s", oldName, err, stderr)
+		}
+		return nil
+	}
+	return fmt.Errorf("registry key for '%s' not found", oldName)
+}
+
+// lxssEntry is a distribution registered under the Lxss key
+type lxssEntry struct {
+	key      string // Full registry key, e.g. HKEY_CURRENT_USER\...\Lxss\{guid}
+	name     string
+	basePath string
+}
+
+// parseLxssEntries reads the registered distributions from `reg query /s`
+// output, which is grouped by subkey:
+//
+//	HKEY_CURRENT_USER\...\Lxss\{guid}
+//	    DistributionName    REG_SZ    Ubuntu
+//	    BasePath    REG_SZ    C:\Users\me\wsl-distros\ubuntu
+func parseLxssEntries(output string) []lxssEntry {
+	var entries []lxssEntry
+	var current lxssEntry
+	flush := func() {
+		if current.name != "" {
+			entries = append(entries, current)
+		}
+		current = lxssEntry{}
+	}
+	for _, raw := range strings.Split(output, "\n") {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "HKEY_") {
+			flush()
+			current.key = line
+			continue
+		}
+		fields := strings.SplitN(line, "    ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		switch strings.TrimSpace(fields[0]) {
+		case "DistributionName":
+			current.name = strings.TrimSpace(fields[2])
+		case "BasePath":
+			current.basePath = strings.TrimPrefix(strings.TrimSpace(fields[2]), `\\?\`)
+		}
+	}
+	flush()
+	return entries
+}
+
+// parseLxssRegistry maps DistributionName to BasePath in `reg query /s` output
+func parseLxssRegistry(output string) map[string]string {
+	paths := make(map[string]string)
+	for _, e := range parseLxssEntries(output) {
+		if e.basePath != "" {
+			paths[e.name] = e.basePath
+		}
+	}
+	return paths
+}
+
+// isDiskSpaceError reports whether an import/export failure was caused by a full disk
+func isDiskSpaceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not enough space") ||
+		strings.Contains(msg, "disk is full") ||
+		strings.Contains(msg, "0x80070070")
+}
+
 // Package-level convenience functions that use a default client
 // These maintain backward compatibility with existing code
 
@@ -137,6 +691,11 @@ func Import(opts ImportOptions) error {
 	return DefaultClient().Import(opts)
 }
 
+// ImportFromURL downloads a rootfs tarball and imports it (uses default client)
+func ImportFromURL(url string, opts ImportOptions) error {
+	return DefaultClient().ImportFromURL(url, opts)
+}
+
 // Unregister removes a WSL distribution (uses default client)
 func Unregister(name string) error {
 	return DefaultClient().Unregister(name)
@@ -146,3 +705,38 @@ func Unregister(name string) error {
 func Export(name, outputPath string) error {
 	return DefaultClient().Export(name, outputPath)
 }
+
+// ExportWithOptions exports and optionally compresses a WSL distribution (uses default client)
+func ExportWithOptions(opts ExportOptions) (*ExportResult, error) {
+	return DefaultClient().ExportWithOptions(opts)
+}
+
+// ExportVHD exports a WSL 2 distribution as a .vhdx (uses default client)
+func ExportVHD(name, outputPath string) error {
+	return DefaultClient().ExportVHD(name, outputPath)
+}
+
+// ConvertVersion converts a distribution between WSL 1 and WSL 2 (uses default client)
+func ConvertVersion(name string, targetVersion int) error {
+	return DefaultClient().ConvertVersion(name, targetVersion)
+}
+
+// Move relocates a WSL distribution (uses default client)
+func Move(opts MoveOptions) (string, error) {
+	return DefaultClient().Move(opts)
+}
+
+// InstallPaths returns the install location of every distribution (uses default client)
+func InstallPaths() (map[string]string, error) {
+	return DefaultClient().InstallPaths()
+}
+
+// GetInstallPath returns where a distribution is installed (uses default client)
+func GetInstallPath(name string) (string, error) {
+	return DefaultClient().GetInstallPath(name)
+}
+
+// Rename renames a WSL distribution (uses default client)
+func Rename(oldName, newName string) error {
+	return DefaultClient().Rename(oldName, newName)
+}
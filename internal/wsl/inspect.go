@@ -0,0 +1,118 @@
+package wsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MemoryInfo holds memory figures from a distribution's /proc/meminfo
+type MemoryInfo struct {
+	TotalKB     uint64 `json:"total_kb" yaml:"total_kb"`
+	AvailableKB uint64 `json:"available_kb" yaml:"available_kb"`
+}
+
+// UsedKB returns the memory in use (total minus available)
+func (m MemoryInfo) UsedKB() uint64 {
+	if m.AvailableKB > m.TotalKB {
+		return 0
+	}
+	return m.TotalKB - m.AvailableKB
+}
+
+// Process is a process running inside a distribution
+type Process struct {
+	PID  int    `json:"pid" yaml:"pid"`
+	Name string `json:"name" yaml:"name"`
+}
+
+// ReadFile returns the contents of a file inside a distribution.
+// Starts the distribution if it is stopped.
+func (c *Client) ReadFile(name, path string) (string, error) {
+	output, stderr, err := c.runner.Run("wsl.exe", "-d", name, "--", "cat", path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s in '%s': %w\nOutput: %s", path, name, err, stderr)
+	}
+	return output, nil
+}
+
+// Memory reads memory usage from /proc/meminfo inside a distribution
+func (c *Client) Memory(name string) (*MemoryInfo, error) {
+	output, err := c.ReadFile(name, "/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	return parseMeminfo(output)
+}
+
+// Processes lists the processes running inside a distribution. /proc is read
+// directly because minimal images often ship without ps.
+func (c *Client) Processes(name string) ([]Process, error) {
+	script := `for p in /proc/[0-9]*; do echo "${p#/proc/} $(cat $p/comm 2>/dev/null)"; done`
+	output, stderr, err := c.runner.Run("wsl.exe", "-d", name, "--", "sh", "-c", script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes in '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	return parseProcesses(output), nil
+}
+
+// parseMeminfo extracts MemTotal and MemAvailable from /proc/meminfo output
+func parseMeminfo(output string) (*MemoryInfo, error) {
+	info := &MemoryInfo{}
+	found := 0
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			info.TotalKB = value
+			found++
+		case "MemAvailable:":
+			info.AvailableKB = value
+			found++
+		}
+	}
+	if found < 2 {
+		return nil, fmt.Errorf("MemTotal/MemAvailable not found in /proc/meminfo")
+	}
+	return info, nil
+}
+
+// parseProcesses parses "<pid> <name>" lines, skipping anything malformed
+func parseProcesses(output string) []Process {
+	var procs []Process
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		p := Process{PID: pid}
+		if len(fields) == 2 {
+			p.Name = strings.TrimSpace(fields[1])
+		}
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// ReadFile returns the contents of a file inside a distribution (uses default client)
+func ReadFile(name, path string) (string, error) {
+	return DefaultClient().ReadFile(name, path)
+}
+
+// Memory reads memory usage inside a distribution (uses default client)
+func Memory(name string) (*MemoryInfo, error) {
+	return DefaultClient().Memory(name)
+}
+
+// Processes lists the processes inside a distribution (uses default client)
+func Processes(name string) ([]Process, error) {
+	return DefaultClient().Processes(name)
+}
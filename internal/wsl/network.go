@@ -0,0 +1,170 @@
+package wsl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PortProxyListenAddress is the Windows address port-proxy rules listen on,
+// so the forwarded port is reachable from other machines
+const PortProxyListenAddress = "0.0.0.0"
+
+// ErrNotElevated is returned when a command needs an elevated prompt
+var ErrNotElevated = errors.New("administrator privileges are required (run autowsl from an elevated prompt)")
+
+// PortProxy is a Windows netsh v4tov4 port-proxy rule
+type PortProxy struct {
+	ListenAddress  string `json:"listen_address" yaml:"listen_address"`
+	ListenPort     int    `json:"listen_port" yaml:"listen_port"`
+	ConnectAddress string `json:"connect_address" yaml:"connect_address"`
+	ConnectPort    int    `json:"connect_port" yaml:"connect_port"`
+}
+
+// GetWSL2IP returns the address port-proxy rules connect to: the eth0 address
+// of a WSL 2 distribution. It changes whenever WSL restarts.
+func (c *Client) GetWSL2IP(distroName string) (string, error) {
+	if c.isDryRun() {
+		_, _ = c.IPv4(distroName)
+		return "<wsl2-ip>", nil
+	}
+	return c.IPv4(distroName)
+}
+
+// ValidatePort checks that port is a usable TCP port number
+func ValidatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d (must be 1-65535)", port)
+	}
+	return nil
+}
+
+// ParsePortMapping parses <listen-port>:<guest-port>. A single port forwards
+// to the same port in the guest.
+func ParsePortMapping(spec string) (listenPort, guestPort int, err error) {
+	listen, guest, found := strings.Cut(spec, ":")
+	if !found {
+		guest = listen
+	}
+	if listenPort, err = strconv.Atoi(listen); err != nil {
+		return 0, 0, fmt.Errorf("invalid port mapping '%s' (expected <listen-port>:<guest-port>)", spec)
+	}
+	if guestPort, err = strconv.Atoi(guest); err != nil {
+		return 0, 0, fmt.Errorf("invalid port mapping '%s' (expected <listen-port>:<guest-port>)", spec)
+	}
+	if err := ValidatePort(listenPort); err != nil {
+		return 0, 0, err
+	}
+	if err := ValidatePort(guestPort); err != nil {
+		return 0, 0, err
+	}
+	return listenPort, guestPort, nil
+}
+
+// IsElevated reports whether autowsl runs with administrator privileges.
+// "net session" only succeeds from an elevated prompt.
+func (c *Client) IsElevated() bool {
+	_, _, err := c.runner.Run("net.exe", "session")
+	return err == nil
+}
+
+// AddPortProxy forwards listenPort on all Windows interfaces to
+// connectAddr:connectPort. It requires administrator privileges.
+func (c *Client) AddPortProxy(listenPort, connectPort int, connectAddr string) error {
+	if err := ValidatePort(listenPort); err != nil {
+		return err
+	}
+	if err := ValidatePort(connectPort); err != nil {
+		return err
+	}
+	if ip := net.ParseIP(connectAddr); (ip == nil || ip.To4() == nil) && !c.isDryRun() {
+		return fmt.Errorf("invalid connect address '%s'", connectAddr)
+	}
+	return c.netshPortProxy("add", "v4tov4",
+		"listenport="+strconv.Itoa(listenPort),
+		"listenaddress="+PortProxyListenAddress,
+		"connectport="+strconv.Itoa(connectPort),
+		"connectaddress="+connectAddr)
+}
+
+// RemovePortProxy deletes the port-proxy rule listening on listenPort. It
+// requires administrator privileges.
+func (c *Client) RemovePortProxy(listenPort int) error {
+	if err := ValidatePort(listenPort); err != nil {
+		return err
+	}
+	return c.netshPortProxy("delete", "v4tov4",
+		"listenport="+strconv.Itoa(listenPort),
+		"listenaddress="+PortProxyListenAddress)
+}
+
+// netshPortProxy runs a modifying netsh interface portproxy command after
+// checking for administrator privileges
+func (c *Client) netshPortProxy(args ...string) error {
+	if !c.IsElevated() {
+		return ErrNotElevated
+	}
+	output, stderr, err := c.runner.Run("netsh.exe", append([]string{"interface", "portproxy"}, args...)...)
+	if err != nil {
+		if strings.Contains(strings.ToLower(output+stderr), "elevation") {
+			return ErrNotElevated
+		}
+		return fmt.Errorf("netsh interface portproxy %s failed: %w\nOutput: %s%s", args[0], err, output, stderr)
+	}
+	return nil
+}
+
+// ListPortProxies returns the configured v4tov4 port-proxy rules
+func (c *Client) ListPortProxies() ([]PortProxy, error) {
+	output, stderr, err := c.runner.Run("netsh.exe", "interface", "portproxy", "show", "v4tov4")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list port-proxy rules: %w\nOutput: %s", err, stderr)
+	}
+	return ParsePortProxies(output), nil
+}
+
+// ParsePortProxies parses the table printed by netsh interface portproxy show.
+// Header and separator lines are skipped.
+func ParsePortProxies(output string) []PortProxy {
+	var proxies []PortProxy
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		listenPort, err1 := strconv.Atoi(fields[1])
+		connectPort, err2 := strconv.Atoi(fields[3])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		proxies = append(proxies, PortProxy{
+			ListenAddress:  fields[0],
+			ListenPort:     listenPort,
+			ConnectAddress: fields[2],
+			ConnectPort:    connectPort,
+		})
+	}
+	return proxies
+}
+
+// GetWSL2IP returns the IPv4 address of a distribution (uses default client)
+func GetWSL2IP(distroName string) (string, error) {
+	return DefaultClient().GetWSL2IP(distroName)
+}
+
+// AddPortProxy adds a Windows port-proxy rule (uses default client)
+func AddPortProxy(listenPort, connectPort int, connectAddr string) error {
+	return DefaultClient().AddPortProxy(listenPort, connectPort, connectAddr)
+}
+
+// RemovePortProxy deletes a Windows port-proxy rule (uses default client)
+func RemovePortProxy(listenPort int) error {
+	return DefaultClient().RemovePortProxy(listenPort)
+}
+
+// ListPortProxies returns the Windows port-proxy rules (uses default client)
+func ListPortProxies() ([]PortProxy, error) {
+	return DefaultClient().ListPortProxies()
+}
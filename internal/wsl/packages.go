@@ -0,0 +1,90 @@
+package wsl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Package is an installed package of a distribution
+type Package struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// packageListCmds list installed packages for each package manager. dpkg and
+// rpm print name<TAB>version; pacman and apk output is parsed natively.
+var packageListCmds = map[string]string{
+	"apt":    `dpkg-query -W -f='${Package}\t${Version}\n'`,
+	"dnf":    `rpm -qa --queryformat '%{NAME}\t%{VERSION}\n'`,
+	"yum":    `rpm -qa --queryformat '%{NAME}\t%{VERSION}\n'`,
+	"zypper": `rpm -qa --queryformat '%{NAME}\t%{VERSION}\n'`,
+	"pacman": "pacman -Q",
+	"apk":    "apk info -v",
+}
+
+// PackageListCommand returns the command that lists installed packages for
+// the named package manager
+func PackageListCommand(pmName string) (string, error) {
+	cmd, ok := packageListCmds[pmName]
+	if !ok {
+		return "", fmt.Errorf("unsupported package manager '%s'", pmName)
+	}
+	return cmd, nil
+}
+
+// ListPackages returns the packages installed in a distribution, sorted by
+// name. pmName is the distribution's package manager, e.g. "apt".
+func (c *Client) ListPackages(name, pmName string) ([]Package, error) {
+	listCmd, err := PackageListCommand(pmName)
+	if err != nil {
+		return nil, err
+	}
+
+	output, stderr, err := c.runner.Run("wsl.exe", "-d", name, "--", "sh", "-c", listCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages in '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	return ParsePackages(pmName, output), nil
+}
+
+// ParsePackages parses the output of the list command of a package manager
+func ParsePackages(pmName, output string) []Package {
+	var packages []Package
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if p, ok := parsePackageLine(pmName, line); ok {
+			packages = append(packages, p)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages
+}
+
+// parsePackageLine parses one line of package list output
+func parsePackageLine(pmName, line string) (Package, bool) {
+	switch pmName {
+	case "pacman":
+		// "bash 5.2.026-2"
+		name, version, _ := strings.Cut(line, " ")
+		return Package{Name: name, Version: strings.TrimSpace(version)}, name != ""
+	case "apk":
+		// "musl-1.2.4-r2": the version is the last two dash-separated parts
+		parts := strings.Split(line, "-")
+		if len(parts) < 3 {
+			return Package{Name: line}, true
+		}
+		n := len(parts) - 2
+		return Package{Name: strings.Join(parts[:n], "-"), Version: strings.Join(parts[n:], "-")}, true
+	}
+	name, version, _ := strings.Cut(line, "\t")
+	return Package{Name: strings.TrimSpace(name), Version: strings.TrimSpace(version)}, name != ""
+}
+
+// ListPackages returns the packages installed in a distribution (uses default client)
+func ListPackages(name, pmName string) ([]Package, error) {
+	return DefaultClient().ListPackages(name, pmName)
+}
@@ -0,0 +1,210 @@
+package wsl
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statePollInterval is how often WaitForState checks the distribution state
+var statePollInterval = 250 * time.Millisecond
+
+// RuntimeStatus is live information about a running distribution
+type RuntimeStatus struct {
+	IPv4          string `json:"ipv4,omitempty" yaml:"ipv4,omitempty"`
+	MemoryTotalMB int    `json:"memory_total_mb" yaml:"memory_total_mb"`
+	MemoryUsedMB  int    `json:"memory_used_mb" yaml:"memory_used_mb"`
+	ProcessCount  int    `json:"process_count" yaml:"process_count"`
+}
+
+// Start boots a distribution by running a trivial command in it
+func (c *Client) Start(name string) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+
+	_, stderr, err := c.runner.Run("wsl.exe", "-d", name, "--", "echo", "started")
+	if err != nil {
+		return fmt.Errorf("failed to start distribution '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	return nil
+}
+
+// Stop terminates a running distribution with wsl --terminate
+func (c *Client) Stop(name string) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+
+	_, stderr, err := c.runner.Run("wsl.exe", "--terminate", name)
+	if err != nil {
+		return fmt.Errorf("failed to stop distribution '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	return nil
+}
+
+// WaitForState polls wsl -l -v until a distribution reports state (e.g.
+// "Stopped" or "Running"), giving up after timeout
+func (c *Client) WaitForState(name, state string, timeout time.Duration) error {
+	if c.isDryRun() {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		distros, err := c.ListInstalledDistros()
+		if err != nil {
+			return fmt.Errorf("failed to check the state of '%s': %w", name, err)
+		}
+		current := ""
+		for _, d := range distros {
+			if d.Name == name {
+				current = d.State
+			}
+		}
+		if current == "" {
+			return fmt.Errorf("distribution '%s' does not exist", name)
+		}
+		if strings.EqualFold(current, state) {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("'%s' did not reach the %s state within %s (current state: %s)", name, state, timeout, current)
+		}
+		time.Sleep(statePollInterval)
+	}
+}
+
+// Exec runs a command in a distribution attached to the terminal and returns
+// the guest command's exit code. err is only set when the command could not be
+// run at all; a non-zero exit code is not an error.
+func (c *Client) Exec(distroName, user string, args []string) (int, error) {
+	err := c.RunCommand(distroName, args, RunCommandOptions{User: user})
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return -1, err
+}
+
+// Shutdown stops all distributions and the WSL2 virtual machine
+func (c *Client) Shutdown() error {
+	_, stderr, err := c.runner.Run("wsl.exe", "--shutdown")
+	if err != nil {
+		return fmt.Errorf("failed to shut down WSL: %w\nOutput: %s", err, stderr)
+	}
+	return nil
+}
+
+// RuntimeStatus collects the IP address, memory usage, and process count of a
+// running distribution. Querying a stopped distribution starts it.
+func (c *Client) RuntimeStatus(name string) (*RuntimeStatus, error) {
+	status := &RuntimeStatus{}
+
+	// WSL 1 has no eth0, so a missing address is not an error
+	status.IPv4, _ = c.IPv4(name)
+
+	output, stderr, err := c.runner.Run("wsl.exe", "-d", name, "--", "sh", "-c", "free -m")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory usage of '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	if status.MemoryTotalMB, status.MemoryUsedMB, err = parseFreeMB(output); err != nil {
+		return nil, err
+	}
+
+	output, stderr, err = c.runner.Run("wsl.exe", "-d", name, "--", "sh", "-c", "ps aux --no-headers | wc -l")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count processes of '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	if status.ProcessCount, err = strconv.Atoi(strings.TrimSpace(output)); err != nil {
+		return nil, fmt.Errorf("unexpected process count %q", strings.TrimSpace(output))
+	}
+
+	return status, nil
+}
+
+// IPv4 returns the IPv4 address of eth0 in a distribution. Querying a
+// stopped distribution starts it.
+func (c *Client) IPv4(name string) (string, error) {
+	output, stderr, err := c.runner.Run("wsl.exe", "-d", name, "--", "sh", "-c", "ip -4 addr show eth0 2>/dev/null")
+	if err != nil {
+		return "", fmt.Errorf("failed to read the IP address of '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	ip := parseIPv4(output)
+	if ip == "" {
+		return "", fmt.Errorf("'%s' has no IPv4 address on eth0 (WSL 1 distributions use the Windows network directly)", name)
+	}
+	return ip, nil
+}
+
+// parseIPv4 returns the first address from `ip addr` output
+// ("inet 172.20.1.2/20 brd ..."), without the prefix length
+func parseIPv4(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "inet" {
+			addr, _, _ := strings.Cut(fields[1], "/")
+			return addr
+		}
+	}
+	return ""
+}
+
+// parseFreeMB returns total and used memory from the Mem: line of `free -m`
+func parseFreeMB(output string) (total, used int, err error) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "Mem:" {
+			continue
+		}
+		if total, err = strconv.Atoi(fields[1]); err != nil {
+			break
+		}
+		if used, err = strconv.Atoi(fields[2]); err != nil {
+			break
+		}
+		return total, used, nil
+	}
+	return 0, 0, fmt.Errorf("unexpected output from free -m: %q", strings.TrimSpace(output))
+}
+
+// Start boots a distribution (uses default client)
+func Start(name string) error {
+	return DefaultClient().Start(name)
+}
+
+// Stop terminates a running distribution (uses default client)
+func Stop(name string) error {
+	return DefaultClient().Stop(name)
+}
+
+// WaitForState waits until a distribution reports state (uses default client)
+func WaitForState(name, state string, timeout time.Duration) error {
+	return DefaultClient().WaitForState(name, state, timeout)
+}
+
+// IPv4 returns the IPv4 address of eth0 in a distribution (uses default client)
+func IPv4(name string) (string, error) {
+	return DefaultClient().IPv4(name)
+}
+
+// Exec runs a command in a distribution and returns its exit code (uses default client)
+func Exec(distroName, user string, args []string) (int, error) {
+	return DefaultClient().Exec(distroName, user, args)
+}
+
+// Shutdown stops all distributions and the WSL2 virtual machine (uses default client)
+func Shutdown() error {
+	return DefaultClient().Shutdown()
+}
+
+// GetRuntimeStatus collects live information about a running distribution (uses default client)
+func GetRuntimeStatus(name string) (*RuntimeStatus, error) {
+	return DefaultClient().RuntimeStatus(name)
+}
@@ -0,0 +1,44 @@
+package wsl
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// loginShellScript prints the /etc/passwd entry of the user the shell runs as
+const loginShellScript = `grep "^$(id -un):" /etc/passwd`
+
+// ValidateShellPath checks that shell is an absolute Linux path, e.g. /bin/zsh
+func ValidateShellPath(shell string) error {
+	if !path.IsAbs(shell) {
+		return fmt.Errorf("invalid shell '%s' (must be an absolute path such as /bin/zsh)", shell)
+	}
+	return nil
+}
+
+// LoginShell returns the login shell configured in /etc/passwd for user in a
+// distribution. An empty user means the distribution's default user.
+func (c *Client) LoginShell(name, user string) (string, error) {
+	args := buildRunArgs(name, []string{"sh", "-c", loginShellScript}, user)
+	output, stderr, err := c.runner.Run("wsl.exe", args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to read login shell in '%s': %w\nOutput: %s", name, err, stderr)
+	}
+	return ParseLoginShell(output)
+}
+
+// ParseLoginShell returns the shell field of an /etc/passwd entry
+func ParseLoginShell(entry string) (string, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(entry), "\n")
+	fields := strings.Split(strings.TrimSpace(line), ":")
+	if len(fields) < 7 || fields[6] == "" {
+		return "", fmt.Errorf("no login shell in passwd entry %q", line)
+	}
+	return fields[6], nil
+}
+
+// LoginShell returns the login shell of user in a distribution (uses default client)
+func LoginShell(name, user string) (string, error) {
+	return DefaultClient().LoginShell(name, user)
+}
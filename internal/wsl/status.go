@@ -4,17 +4,31 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 // InstalledDistro represents a currently installed WSL distribution
 type InstalledDistro struct {
-	Name    string
-	State   string // Running, Stopped
-	Version string // 1 or 2
-	Default bool
+	Name    string `json:"name" yaml:"name"`
+	State   string `json:"state" yaml:"state"`     // Running, Stopped
+	Version string `json:"version" yaml:"version"` // 1 or 2
+	Default bool   `json:"default" yaml:"default"`
 }
 
+// WSLVersionInfo holds the component versions reported by wsl --version.
+// WSL is empty for the inbox WSL of older Windows builds.
+type WSLVersionInfo struct {
+	WSL          string `json:"wsl" yaml:"wsl"`
+	Kernel       string `json:"kernel" yaml:"kernel"`
+	WSLg         string `json:"wslg,omitempty" yaml:"wslg,omitempty"`
+	SystemDistro string `json:"system_distro,omitempty" yaml:"system_distro,omitempty"`
+}
+
+// componentVersionRe matches version numbers such as 2.0.14.0 or kernel
+// versions such as 5.15.133.1-microsoft-standard-WSL2
+var componentVersionRe = regexp.MustCompile(`\d+(\.\d+)+(-[\w.-]+)?`)
+
 // CheckWSLInstalled checks if WSL is installed and available
 func (c *Client) CheckWSLInstalled() error {
 	_, _, err := c.runner.Run("wsl.exe", "--status")
@@ -24,6 +38,57 @@ func (c *Client) CheckWSLInstalled() error {
 	return nil
 }
 
+// GetWSLVersion returns the versions of WSL and its components. Older Windows
+// builds have no wsl --version; the kernel version is then read from
+// wsl --status.
+func (c *Client) GetWSLVersion() (WSLVersionInfo, error) {
+	output, _, err := c.runner.Run("wsl.exe", "--version")
+	if err == nil {
+		if info := ParseWSLVersion(output); info.WSL != "" {
+			return info, nil
+		}
+	}
+
+	output, stderr, err := c.runner.Run("wsl.exe", "--status")
+	if err != nil {
+		return WSLVersionInfo{}, fmt.Errorf("failed to read WSL version: %w\nOutput: %s", err, stderr)
+	}
+	return ParseWSLVersion(output), nil
+}
+
+// ParseWSLVersion parses the "<component> version: <version>" lines of
+// wsl --version or wsl --status. The output is UTF-16 and localized, so
+// components are recognized by the keywords in their label; lines whose
+// label has none of them, e.g. a translated "kernel", are skipped.
+func ParseWSLVersion(output string) WSLVersionInfo {
+	output = strings.ReplaceAll(output, "\x00", "")
+	output = strings.ReplaceAll(output, "：", ":") // full-width colon of CJK locales
+
+	var info WSLVersionInfo
+	for _, line := range strings.Split(output, "\n") {
+		label, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		version := componentVersionRe.FindString(value)
+		if version == "" {
+			continue
+		}
+		label = strings.ToLower(label)
+		switch {
+		case strings.Contains(label, "wslg"):
+			info.WSLg = version
+		case strings.Contains(label, "kernel"):
+			info.Kernel = version
+		case strings.Contains(label, "system") && strings.Contains(label, "distr"):
+			info.SystemDistro = version
+		case strings.Contains(label, "wsl") && info.WSL == "":
+			info.WSL = version
+		}
+	}
+	return info
+}
+
 // ListInstalledDistros lists all currently installed WSL distributions
 func (c *Client) ListInstalledDistros() ([]InstalledDistro, error) {
 	output, stderr, err := c.runner.Run("wsl.exe", "-l", "-v")
@@ -70,31 +135,21 @@ func parseWSLList(output string) ([]InstalledDistro, error) {
 
 	// Skip header lines
 	startIdx := 0
+	var columns []int
 	for i, line := range lines {
 		if strings.Contains(line, "NAME") || strings.Contains(line, "---") {
 			startIdx = i + 1
+			columns = wslListColumns(strings.TrimRight(line, "\r"))
 			break
 		}
 	}
 
-	// Regular expression to parse each line
-	// Format: "  * Ubuntu-22.04    Running         2"
-	re := regexp.MustCompile(`^\s*(\*?)\s*([^\s]+)\s+(\w+)\s+(\d+)\s*$`)
-
 	for _, line := range lines[startIdx:] {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
-
-		matches := re.FindStringSubmatch(line)
-		if len(matches) == 5 {
-			distro := InstalledDistro{
-				Name:    strings.TrimSpace(matches[2]),
-				State:   strings.TrimSpace(matches[3]),
-				Version: strings.TrimSpace(matches[4]),
-				Default: matches[1] == "*",
-			}
+		if distro, ok := parseWSLListRow(line, columns); ok {
 			distros = append(distros, distro)
 		}
 	}
@@ -102,6 +157,64 @@ func parseWSLList(output string) ([]InstalledDistro, error) {
 	return distros, nil
 }
 
+// wslListRow matches a "wsl -l -v" row whose columns don't line up with the
+// header. The name is everything before the last two fields, so it may
+// contain spaces. Format: "  * Ubuntu-22.04    Running         2"
+var wslListRow = regexp.MustCompile(`^\s*(\*?)\s*(.+?)\s+(\S+)\s+(\d+)\s*$`)
+
+// wslListColumns returns where the NAME, STATE and VERSION columns of a
+// "wsl -l -v" header start, or nil. The titles are localized, so columns are
+// found as words preceded by at least two spaces rather than by name.
+func wslListColumns(header string) []int {
+	runes := []rune(header)
+	var columns []int
+	for i, r := range runes {
+		if r != ' ' && (i == 0 || runes[i-1] == ' ' && (i == 1 || runes[i-2] == ' ')) {
+			columns = append(columns, i)
+		}
+	}
+	if len(columns) != 3 {
+		return nil
+	}
+	return columns
+}
+
+// parseWSLListRow parses one row of "wsl -l -v", slicing it at the header's
+// column positions so names and states with spaces survive. Rows that don't
+// fit the columns fall back to wslListRow.
+func parseWSLListRow(line string, columns []int) (InstalledDistro, bool) {
+	runes := []rune(line)
+	if len(columns) == 3 && len(runes) > columns[2] && runes[columns[1]-1] == ' ' && runes[columns[2]-1] == ' ' {
+		name := strings.TrimSpace(string(runes[:columns[1]]))
+		state := strings.TrimSpace(string(runes[columns[1]:columns[2]]))
+		version := strings.TrimSpace(string(runes[columns[2]:]))
+		isDefault := strings.HasPrefix(name, "*")
+		name = unquoteDistroName(strings.TrimSpace(strings.TrimPrefix(name, "*")))
+		if _, err := strconv.Atoi(version); err == nil && name != "" && state != "" {
+			return InstalledDistro{Name: name, State: state, Version: version, Default: isDefault}, true
+		}
+	}
+
+	matches := wslListRow.FindStringSubmatch(line)
+	if len(matches) != 5 {
+		return InstalledDistro{}, false
+	}
+	return InstalledDistro{
+		Name:    unquoteDistroName(strings.TrimSpace(matches[2])),
+		State:   strings.TrimSpace(matches[3]),
+		Version: strings.TrimSpace(matches[4]),
+		Default: matches[1] == "*",
+	}, true
+}
+
+// unquoteDistroName strips quotes around a distribution name
+func unquoteDistroName(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return name[1 : len(name)-1]
+	}
+	return name
+}
+
 // parseWSLListBasic parses output of the legacy "wsl -l" (no -v) command.
 // Expected format (example):
 //
@@ -131,8 +244,8 @@ func parseWSLListBasic(output string) []InstalledDistro {
 			defaultFlag = true
 			line = strings.TrimSpace(strings.ReplaceAll(line, "(Default)", ""))
 		}
-		// Remove leading * if present
-		line = strings.TrimLeft(line, "* ")
+		// Remove leading * if present; the rest of the line is the name, spaces included
+		line = unquoteDistroName(strings.TrimSpace(strings.TrimPrefix(line, "*")))
 		if line == "" {
 			continue
 		}
@@ -160,6 +273,95 @@ func (c *Client) IsDistroInstalled(name string) (bool, error) {
 	return false, nil
 }
 
+// DistroFilter selects installed distributions by state
+type DistroFilter struct {
+	Running bool
+	Stopped bool
+}
+
+// FilterDistros returns the distributions that match f
+func FilterDistros(distros []InstalledDistro, f DistroFilter) []InstalledDistro {
+	filtered := make([]InstalledDistro, 0, len(distros))
+	for _, d := range distros {
+		if f.Running && d.State != "Running" {
+			continue
+		}
+		if f.Stopped && d.State != "Stopped" {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+// MaxDistroNameLength is the longest distribution name accepted by ValidateDistroName
+const MaxDistroNameLength = 64
+
+// ValidateDistroName checks that a name can be registered with wsl --import.
+// Names are stored as registry values, so only letters, digits, '.', '_' and
+// '-' are allowed, without spaces or leading/trailing dashes.
+func ValidateDistroName(name string) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+	if len(name) > MaxDistroNameLength {
+		return fmt.Errorf("distribution name '%s' is too long (%d characters, max %d)", name, len(name), MaxDistroNameLength)
+	}
+	if strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("distribution name '%s' cannot contain spaces", name)
+	}
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") {
+		return fmt.Errorf("distribution name '%s' cannot start or end with a dash", name)
+	}
+	for _, r := range name {
+		if !isDistroNameChar(r) {
+			return fmt.Errorf("distribution name '%s' contains invalid character '%c' (allowed: letters, digits, '.', '_', '-')", name, r)
+		}
+	}
+	return nil
+}
+
+func isDistroNameChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '.' || r == '_' || r == '-'
+}
+
+// SetDefault makes the given distribution the WSL default
+func (c *Client) SetDefault(name string) error {
+	if name == "" {
+		return fmt.Errorf("distribution name cannot be empty")
+	}
+
+	exists, err := c.IsDistroInstalled(name)
+	if err != nil {
+		return fmt.Errorf("failed to check if distro exists: %w", err)
+	}
+	if !exists && !c.isDryRun() {
+		return fmt.Errorf("distribution '%s' does not exist", name)
+	}
+
+	_, stderr, err := c.runner.Run("wsl.exe", "--set-default", name)
+	if err != nil {
+		return fmt.Errorf("failed to set default distribution: %w\nOutput: %s", err, stderr)
+	}
+
+	return nil
+}
+
+// GetDefaultDistro returns the name of the WSL default distribution
+func (c *Client) GetDefaultDistro() (string, error) {
+	distros, err := c.ListInstalledDistros()
+	if err != nil {
+		return "", err
+	}
+	for _, d := range distros {
+		if d.Default {
+			return d.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no default distribution is set")
+}
+
 // Package-level convenience functions that use a default client
 // These maintain backward compatibility with existing code
 
@@ -177,3 +379,18 @@ func ListInstalledDistros() ([]InstalledDistro, error) {
 func IsDistroInstalled(name string) (bool, error) {
 	return DefaultClient().IsDistroInstalled(name)
 }
+
+// SetDefault makes the given distribution the WSL default (uses default client)
+func SetDefault(name string) error {
+	return DefaultClient().SetDefault(name)
+}
+
+// GetDefaultDistro returns the name of the WSL default distribution (uses default client)
+func GetDefaultDistro() (string, error) {
+	return DefaultClient().GetDefaultDistro()
+}
+
+// GetWSLVersion returns the versions of WSL and its components (uses default client)
+func GetWSLVersion() (WSLVersionInfo, error) {
+	return DefaultClient().GetWSLVersion()
+}
@@ -0,0 +1,56 @@
+package wsl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultVHDName is the virtual disk name wsl --import and the Store use
+const defaultVHDName = "ext4.vhdx"
+
+// FindVHD returns the virtual disk in a WSL 2 install directory. The name
+// varies (ext4.vhdx, or <name>.vhdx for some packages); ext4.vhdx is preferred
+// when several disks are present.
+func FindVHD(installPath string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(installPath, "*.vhdx"))
+	if err != nil {
+		return "", fmt.Errorf("failed to search '%s' for a virtual disk: %w", installPath, err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no virtual disk (.vhdx) found in %s", installPath)
+	}
+	for _, m := range matches {
+		if filepath.Base(m) == defaultVHDName {
+			return m, nil
+		}
+	}
+	return matches[0], nil
+}
+
+// GetVHDSize returns the size of a virtual disk file in bytes
+func GetVHDSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read virtual disk '%s': %w", path, err)
+	}
+	return info.Size(), nil
+}
+
+// VHDPath returns the path of a distribution's virtual disk
+func (c *Client) VHDPath(name string) (string, error) {
+	base, err := c.GetInstallPath(name)
+	if err != nil {
+		return "", err
+	}
+	path, err := FindVHD(base)
+	if err != nil {
+		return "", fmt.Errorf("virtual disk for '%s' not found: %w", name, err)
+	}
+	return path, nil
+}
+
+// VHDPath returns the path of a distribution's virtual disk (uses default client)
+func VHDPath(name string) (string, error) {
+	return DefaultClient().VHDPath(name)
+}
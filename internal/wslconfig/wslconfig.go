@@ -0,0 +1,325 @@
+package wslconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileName is the name of the global WSL2 settings file in the user profile
+const FileName = ".wslconfig"
+
+// Sections are the sections of .wslconfig autowsl reads and writes
+var Sections = []string{"wsl2", "experimental"}
+
+// valueKind describes how a known key is validated
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindBool
+	kindInt
+	kindSize
+	kindPath
+)
+
+// knownKey is a documented .wslconfig setting
+type knownKey struct {
+	section string
+	name    string
+	kind    valueKind
+	choices []string
+}
+
+// knownKeys lists the documented settings; other keys are written unvalidated
+// so newer WSL releases keep working
+var knownKeys = []knownKey{
+	{section: "wsl2", name: "memory", kind: kindSize},
+	{section: "wsl2", name: "processors", kind: kindInt},
+	{section: "wsl2", name: "swap", kind: kindSize},
+	{section: "wsl2", name: "swapFile", kind: kindPath},
+	{section: "wsl2", name: "defaultVhdSize", kind: kindSize},
+	{section: "wsl2", name: "kernel", kind: kindPath},
+	{section: "wsl2", name: "kernelCommandLine", kind: kindString},
+	{section: "wsl2", name: "localhostForwarding", kind: kindBool},
+	{section: "wsl2", name: "nestedVirtualization", kind: kindBool},
+	{section: "wsl2", name: "guiApplications", kind: kindBool},
+	{section: "wsl2", name: "pageReporting", kind: kindBool},
+	{section: "wsl2", name: "debugConsole", kind: kindBool},
+	{section: "wsl2", name: "safeMode", kind: kindBool},
+	{section: "wsl2", name: "vmIdleTimeout", kind: kindInt},
+	{section: "wsl2", name: "networkingMode", kind: kindString, choices: []string{"nat", "mirrored", "virtioproxy", "none"}},
+	{section: "wsl2", name: "firewall", kind: kindBool},
+	{section: "wsl2", name: "dnsTunneling", kind: kindBool},
+	{section: "wsl2", name: "autoProxy", kind: kindBool},
+	{section: "experimental", name: "autoMemoryReclaim", kind: kindString, choices: []string{"disabled", "gradual", "dropcache"}},
+	{section: "experimental", name: "sparseVhd", kind: kindBool},
+	{section: "experimental", name: "hostAddressLoopback", kind: kindBool},
+	{section: "experimental", name: "bestEffortDnsParsing", kind: kindBool},
+	{section: "experimental", name: "useWindowsDnsCache", kind: kindBool},
+	{section: "experimental", name: "ignoredPorts", kind: kindString},
+}
+
+// sizePattern matches sizes such as 8GB, 4096MB, or 512 (bytes)
+var sizePattern = regexp.MustCompile(`(?i)^[0-9]+\s*(B|KB|MB|GB|TB)?$`)
+
+// line is one line of the file. Comments and blank lines keep their raw text
+// so a rewrite only touches the keys that changed.
+type line struct {
+	raw     string
+	section string
+	key     string
+	value   string
+}
+
+// Config is a parsed .wslconfig file
+type Config struct {
+	lines []line
+}
+
+// Entry is a key and value in a section
+type Entry struct {
+	Section string `json:"section"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
+// Path returns the location of .wslconfig (%USERPROFILE%\.wslconfig on Windows)
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user profile directory: %w", err)
+	}
+	return filepath.Join(homeDir, FileName), nil
+}
+
+// Parse parses .wslconfig INI text
+func Parse(text string) (*Config, error) {
+	c := &Config{}
+	section := ""
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+			c.lines = append(c.lines, line{raw: raw, section: section})
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			section = strings.ToLower(strings.TrimSpace(trimmed[1 : len(trimmed)-1]))
+			c.lines = append(c.lines, line{raw: raw, section: section})
+		default:
+			key, value, ok := strings.Cut(trimmed, "=")
+			if !ok || section == "" {
+				return nil, fmt.Errorf(".wslconfig line %d: unexpected %q", i+1, trimmed)
+			}
+			c.lines = append(c.lines, line{
+				section: section,
+				key:     strings.TrimSpace(key),
+				value:   strings.TrimSpace(value),
+			})
+		}
+	}
+	// Drop the empty line produced by a trailing newline
+	if n := len(c.lines); n > 0 && c.lines[n-1].key == "" && c.lines[n-1].raw == "" {
+		c.lines = c.lines[:n-1]
+	}
+	return c, nil
+}
+
+// Load reads .wslconfig from the user profile. A missing file yields an empty config.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(path)
+}
+
+// LoadFrom reads a .wslconfig file. A missing file yields an empty config.
+func LoadFrom(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return Parse(string(data))
+}
+
+// Save writes the config to .wslconfig in the user profile
+func (c *Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return c.SaveTo(path)
+}
+
+// SaveTo writes the config to path
+func (c *Config) SaveTo(path string) error {
+	if err := os.WriteFile(path, []byte(c.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Reset removes .wslconfig so WSL falls back to its defaults. It reports
+// whether a file was removed.
+func Reset() (bool, error) {
+	path, err := Path()
+	if err != nil {
+		return false, err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// Get returns the value of "section.key"; keys are case-insensitive
+func (c *Config) Get(key string) (string, bool) {
+	section, name, err := splitKey(key)
+	if err != nil {
+		return "", false
+	}
+	for _, l := range c.lines {
+		if l.section == section && strings.EqualFold(l.key, name) {
+			return l.value, true
+		}
+	}
+	return "", false
+}
+
+// Entries returns all keys in file order
+func (c *Config) Entries() []Entry {
+	var entries []Entry
+	for _, l := range c.lines {
+		if l.key != "" {
+			entries = append(entries, Entry{Section: l.section, Key: l.key, Value: l.value})
+		}
+	}
+	return entries
+}
+
+// Set validates and assigns "section.key", e.g. "wsl2.memory". An existing key
+// is updated in place; a new one is appended to its section.
+func (c *Config) Set(key, value string) error {
+	section, name, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	if known := findKnownKey(section, name); known != nil {
+		name = known.name
+		if value, err = validate(*known, value); err != nil {
+			return fmt.Errorf("invalid value for '%s': %w", key, err)
+		}
+	}
+
+	for i, l := range c.lines {
+		if l.section == section && strings.EqualFold(l.key, name) {
+			c.lines[i].value = value
+			return nil
+		}
+	}
+
+	// Insert after the last key of the section, or start the section at the end
+	insertAt := -1
+	for i, l := range c.lines {
+		if l.section == section && (l.key != "" || strings.HasPrefix(strings.TrimSpace(l.raw), "[")) {
+			insertAt = i + 1
+		}
+	}
+	entry := line{section: section, key: name, value: value}
+	if insertAt < 0 {
+		if len(c.lines) > 0 {
+			c.lines = append(c.lines, line{})
+		}
+		c.lines = append(c.lines, line{raw: "[" + section + "]", section: section}, entry)
+		return nil
+	}
+	c.lines = append(c.lines[:insertAt], append([]line{entry}, c.lines[insertAt:]...)...)
+	return nil
+}
+
+// String renders the config as INI text
+func (c *Config) String() string {
+	var b strings.Builder
+	for _, l := range c.lines {
+		if l.key != "" {
+			fmt.Fprintf(&b, "%s=%s\n", l.key, l.value)
+		} else {
+			b.WriteString(l.raw + "\n")
+		}
+	}
+	return b.String()
+}
+
+// splitKey splits "section.key" and checks the section is supported
+func splitKey(key string) (string, string, error) {
+	section, name, ok := strings.Cut(key, ".")
+	if !ok || section == "" || name == "" {
+		return "", "", fmt.Errorf("invalid .wslconfig key '%s' (expected section.key, e.g. wsl2.memory)", key)
+	}
+	section = strings.ToLower(section)
+	for _, s := range Sections {
+		if section == s {
+			return section, name, nil
+		}
+	}
+	return "", "", fmt.Errorf("unsupported .wslconfig section '%s' (must be one of: %s)", section, strings.Join(Sections, ", "))
+}
+
+// findKnownKey looks up a documented setting
+func findKnownKey(section, name string) *knownKey {
+	for i := range knownKeys {
+		if knownKeys[i].section == section && strings.EqualFold(knownKeys[i].name, name) {
+			return &knownKeys[i]
+		}
+	}
+	return nil
+}
+
+// validate checks value against the kind of k and returns it normalized
+func validate(k knownKey, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch k.kind {
+	case kindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%s (expected true or false)", value)
+		}
+		return strconv.FormatBool(b), nil
+	case kindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("%s (expected a non-negative number)", value)
+		}
+		return value, nil
+	case kindSize:
+		if !sizePattern.MatchString(value) {
+			return "", fmt.Errorf("%s (expected a size such as 8GB or 4096MB)", value)
+		}
+		return strings.ToUpper(strings.ReplaceAll(value, " ", "")), nil
+	case kindPath:
+		// .wslconfig needs escaped backslashes in Windows paths
+		if strings.Contains(value, `\`) && !strings.Contains(value, `\\`) {
+			value = strings.ReplaceAll(value, `\`, `\\`)
+		}
+		return value, nil
+	}
+	if len(k.choices) > 0 {
+		for _, choice := range k.choices {
+			if strings.EqualFold(value, choice) {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("%s (must be one of: %s)", value, strings.Join(k.choices, ", "))
+	}
+	return value, nil
+}
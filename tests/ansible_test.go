@@ -0,0 +1,742 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/playbooks"
+)
+
+func TestBuildAnsibleCommandSkipTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     ansible.PlaybookOptions
+		contains string
+		absent   string
+	}{
+		{
+			name:     "skip tags provided",
+			opts:     ansible.PlaybookOptions{SkipTags: []string{"docker", "slow"}},
+			contains: "--skip-tags 'docker,slow'",
+		},
+		{
+			name:   "no skip tags",
+			opts:   ansible.PlaybookOptions{Tags: []string{"docker"}},
+			absent: "--skip-tags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", tt.opts)
+			if tt.contains != "" && !strings.Contains(cmd, tt.contains) {
+				t.Errorf("Expected command to contain %q, got: %s", tt.contains, cmd)
+			}
+			if tt.absent != "" && strings.Contains(cmd, tt.absent) {
+				t.Errorf("Expected command not to contain %q, got: %s", tt.absent, cmd)
+			}
+		})
+	}
+}
+
+func TestBuildAnsibleCommandLimit(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{Limit: "localhost"})
+	if !strings.Contains(cmd, "--limit 'localhost'") {
+		t.Errorf("Expected --limit 'localhost', got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{})
+	if strings.Contains(cmd, "--limit") {
+		t.Errorf("Expected no --limit flag, got: %s", cmd)
+	}
+}
+
+func TestBuildAnsibleCommandQuotesArguments(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{
+		Limit:              "web'; touch /tmp/pwned; echo '",
+		BecomePasswordFile: "/tmp/it's-secret",
+		VaultIDs:           []ansible.VaultID{{Label: "o'brien", Source: "/tmp/vault"}},
+		Tags:               []string{"web", "it's"},
+		SkipTags:           []string{"slow'; reboot; '"},
+	})
+	for _, want := range []string{
+		`--tags 'web,it'\''s'`,
+		`--skip-tags 'slow'\''; reboot; '\'''`,
+		`--limit 'web'\''; touch /tmp/pwned; echo '\'''`,
+		`--become-password-file '/tmp/it'\''s-secret'`,
+		`--vault-id 'o'\''brien@/tmp/vault'`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("Expected %s, got: %s", want, cmd)
+		}
+	}
+}
+
+func TestBuildAnsibleCommandForks(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{Forks: 10})
+	if !strings.Contains(cmd, " --forks 10") {
+		t.Errorf("Expected --forks 10, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{})
+	if strings.Contains(cmd, "--forks") {
+		t.Errorf("Expected no --forks flag by default, got: %s", cmd)
+	}
+}
+
+func TestBuildAnsibleCommandConnection(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{Connection: "local"})
+	if !strings.HasPrefix(cmd, "ansible-playbook /tmp/playbook.yml --connection=local -i localhost,") {
+		t.Errorf("Expected a local run, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{Connection: "ssh", InventoryPath: "/tmp/hosts.ini"})
+	if !strings.HasPrefix(cmd, "ansible-playbook /tmp/playbook.yml --connection=ssh -i '/tmp/hosts.ini'") {
+		t.Errorf("Expected an ssh run against the inventory, got: %s", cmd)
+	}
+	if strings.Contains(cmd, "localhost,") {
+		t.Errorf("Expected no localhost inventory for ssh, got: %s", cmd)
+	}
+}
+
+func TestValidateConnection(t *testing.T) {
+	inventory := filepath.Join(t.TempDir(), "hosts.ini")
+	if err := os.WriteFile(inventory, []byte("[vms]\nvm1 ansible_host=10.0.0.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		connection, inventory string
+		wantErr               bool
+	}{
+		{"", "", false},
+		{"local", "", false},
+		{"ssh", inventory, false},
+		{"local", inventory, true},
+		{"ssh", "", true},
+		{"ssh", inventory + ".missing", true},
+		{"ssh; rm -rf /", inventory, true},
+	}
+	for _, tt := range tests {
+		err := ansible.ValidateConnection(tt.connection, tt.inventory)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateConnection(%q, %q) = %v, wantErr %v", tt.connection, tt.inventory, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOutputLogHeader(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	header := ansible.OutputLogHeader(ansible.PlaybookOptions{
+		DistroName:   "Ubuntu",
+		PlaybookPath: "/work/playbooks/setup.yml",
+		Tags:         []string{"docker", "node"},
+	}, at)
+
+	want := "=== autowsl playbook=setup.yml distro=Ubuntu time=2026-03-01T09:30:00Z tags=docker,node ==="
+	if header != want {
+		t.Errorf("OutputLogHeader() = %q, want %q", header, want)
+	}
+}
+
+func TestBuildAnsibleCommandVault(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{VaultPasswordFile: "/tmp/autowsl-vault-pass"})
+	if !strings.Contains(cmd, "--vault-password-file '/tmp/autowsl-vault-pass'") {
+		t.Errorf("Expected vault password file flag, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{AskVaultPass: true})
+	if !strings.Contains(cmd, "--ask-vault-pass") {
+		t.Errorf("Expected --ask-vault-pass, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{})
+	if strings.Contains(cmd, "vault") {
+		t.Errorf("Expected no vault flags, got: %s", cmd)
+	}
+}
+
+func TestBuildPlaybookCommandsGalaxyFirst(t *testing.T) {
+	cmds := ansible.BuildPlaybookCommands("/tmp/playbook.yml", "/tmp/autowsl-requirements.yml", ansible.PlaybookOptions{})
+	if len(cmds) != 2 {
+		t.Fatalf("Expected galaxy and playbook commands, got: %v", cmds)
+	}
+	if !strings.HasPrefix(cmds[0], "ansible-galaxy collection install -r '/tmp/autowsl-requirements.yml'") {
+		t.Errorf("Expected galaxy install first, got: %s", cmds[0])
+	}
+	if !strings.HasPrefix(cmds[1], "ansible-playbook /tmp/playbook.yml") {
+		t.Errorf("Expected playbook command last, got: %s", cmds[1])
+	}
+
+	cmds = ansible.BuildPlaybookCommands("/tmp/playbook.yml", "/tmp/autowsl-requirements.yml", ansible.PlaybookOptions{SkipGalaxyInstall: true})
+	if len(cmds) != 1 || !strings.HasPrefix(cmds[0], "ansible-playbook") {
+		t.Errorf("Expected only the playbook command when skipping galaxy, got: %v", cmds)
+	}
+
+	cmds = ansible.BuildPlaybookCommands("/tmp/playbook.yml", "", ansible.PlaybookOptions{})
+	if len(cmds) != 1 {
+		t.Errorf("Expected only the playbook command without requirements, got: %v", cmds)
+	}
+}
+
+func TestBuildAnsibleCommandBecomePasswordFile(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{BecomePasswordFile: "/tmp/autowsl-become-pass"})
+	if !strings.Contains(cmd, "--become-password-file '/tmp/autowsl-become-pass'") {
+		t.Errorf("Expected become password file flag, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{})
+	if strings.Contains(cmd, "--become-password-file") {
+		t.Errorf("Expected no become password file flag, got: %s", cmd)
+	}
+}
+
+func TestBuildAnsibleCommandSyntaxCheck(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{SyntaxCheck: true})
+	if !strings.Contains(cmd, " --syntax-check") {
+		t.Errorf("Expected --syntax-check, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{})
+	if strings.Contains(cmd, "--syntax-check") {
+		t.Errorf("Expected no --syntax-check, got: %s", cmd)
+	}
+}
+
+func TestBuildUpdateCommand(t *testing.T) {
+	tests := []struct {
+		pm           string
+		packages     []string
+		securityOnly bool
+		want         string
+		wantErr      bool
+	}{
+		{pm: "apt", want: "sudo apt-get update && sudo apt-get upgrade -y"},
+		{pm: "apt", packages: []string{"git", "curl"}, want: "sudo apt-get update && sudo apt-get install -y --only-upgrade git curl"},
+		{pm: "dnf", securityOnly: true, want: "sudo dnf upgrade -y --security"},
+		{pm: "pacman", want: "sudo pacman -Syu --noconfirm"},
+		{pm: "pacman", packages: []string{"git"}, wantErr: true},
+		{pm: "apk", securityOnly: true, wantErr: true},
+		{pm: "apt", packages: []string{"git; rm -rf /"}, wantErr: true},
+		{pm: "apt", packages: []string{"git"}, securityOnly: true, wantErr: true},
+		{pm: "brew", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ansible.BuildUpdateCommand(tt.pm, tt.packages, tt.securityOnly)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("BuildUpdateCommand(%s, %v, %v) expected error, got %q", tt.pm, tt.packages, tt.securityOnly, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("BuildUpdateCommand(%s, %v, %v) = %q, %v; want %q", tt.pm, tt.packages, tt.securityOnly, got, err, tt.want)
+		}
+	}
+}
+
+func TestBuildAnsibleCommandCheckDiff(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{CheckMode: true, DiffMode: true})
+	if !strings.Contains(cmd, " --check") || !strings.Contains(cmd, " --diff") {
+		t.Errorf("Expected --check and --diff, got: %s", cmd)
+	}
+
+	cmd = ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{DiffMode: true})
+	if strings.Contains(cmd, "--check") || !strings.Contains(cmd, " --diff") {
+		t.Errorf("Expected only --diff, got: %s", cmd)
+	}
+}
+
+func TestBuildCloneCommand(t *testing.T) {
+	opts := ansible.CloneOptions{RepoURL: "https://github.com/user/repo", DestDir: "/tmp/autowsl-playbooks"}
+	if got := ansible.BuildCloneCommand(opts, ""); got != "git clone 'https://github.com/user/repo' '/tmp/autowsl-playbooks'" {
+		t.Errorf("Unexpected clone command: %s", got)
+	}
+
+	opts = ansible.CloneOptions{RepoURL: "git@github.com:org/infra.git", DestDir: "/tmp/autowsl-playbooks", Branch: "dev"}
+	got := ansible.BuildCloneCommand(opts, "/tmp/autowsl-ssh-key")
+	want := `GIT_SSH_COMMAND='ssh -i '\''/tmp/autowsl-ssh-key'\'' -o StrictHostKeyChecking=no' git clone --branch 'dev' 'git@github.com:org/infra.git' '/tmp/autowsl-playbooks'`
+	if got != want {
+		t.Errorf("BuildCloneCommand() = %s, want %s", got, want)
+	}
+}
+
+func TestParseAnsibleVersion(t *testing.T) {
+	tests := map[string]string{
+		"ansible-playbook [core 2.14.3]\n  config file = None\n": "2.14.3",
+		"ansible-playbook 2.9.6\n  python version = 3.8.10":      "2.9.6",
+	}
+	for output, want := range tests {
+		got, err := ansible.ParseAnsibleVersion(output)
+		if err != nil || got != want {
+			t.Errorf("ParseAnsibleVersion(%q) = %q, %v; want %q", output, got, err, want)
+		}
+	}
+
+	if _, err := ansible.ParseAnsibleVersion("command not found"); err == nil {
+		t.Error("Expected error for output without a version")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.14.3", "2.14", 1},
+		{"2.9.6", "2.14", -1},
+		{"2.14", "v2.14.0", 0},
+		{"10.0", "9.9.9", 1},
+	}
+	for _, tt := range tests {
+		got, err := ansible.CompareVersions(tt.a, tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v; want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+
+	if _, err := ansible.CompareVersions("2.x", "2.14"); err == nil {
+		t.Error("Expected error for invalid version")
+	}
+}
+
+func TestExecutionSummaryFailedPlaybooks(t *testing.T) {
+	summary := &ansible.ExecutionSummary{}
+	summary.Add(ansible.ExecutionResult{PlaybookName: "base.yml", Status: "failed"})
+	summary.Add(ansible.ExecutionResult{PlaybookName: "dev.yml", Status: "success"})
+	summary.Add(ansible.ExecutionResult{PlaybookName: "extras.yml", Status: "failed"})
+
+	got := strings.Join(summary.FailedPlaybooks(), ",")
+	if got != "base.yml,extras.yml" {
+		t.Errorf("FailedPlaybooks() = %q, want %q", got, "base.yml,extras.yml")
+	}
+	if summary.SuccessCount() != 1 {
+		t.Errorf("SuccessCount() = %d, want 1", summary.SuccessCount())
+	}
+}
+
+func TestExecutionSummaryWriteJSON(t *testing.T) {
+	summary := &ansible.ExecutionSummary{}
+	summary.Add(ansible.ExecutionResult{PlaybookName: "base.yml", Status: "success", Duration: 90 * time.Second})
+	summary.Add(ansible.ExecutionResult{PlaybookName: "dev.yml", Status: "failed", Duration: time.Second, Error: errors.New("exit status 2")})
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := summary.WriteJSON(path); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+
+	var got struct {
+		Version int `json:"version"`
+		Total   int `json:"total"`
+		Success int `json:"success"`
+		Failed  int `json:"failed"`
+		Results []struct {
+			Playbook        string  `json:"playbook"`
+			Status          string  `json:"status"`
+			DurationSeconds float64 `json:"duration_seconds"`
+			Error           string  `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if got.Version != ansible.SummarySchemaVersion {
+		t.Errorf("version = %d, want %d", got.Version, ansible.SummarySchemaVersion)
+	}
+	if got.Total != 2 || got.Success != 1 || got.Failed != 1 {
+		t.Errorf("counts = %d/%d/%d, want 2/1/1", got.Total, got.Success, got.Failed)
+	}
+	if len(got.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(got.Results))
+	}
+	if got.Results[0].Playbook != "base.yml" || got.Results[0].DurationSeconds != 90 || got.Results[0].Error != "" {
+		t.Errorf("Unexpected first result: %+v", got.Results[0])
+	}
+	if got.Results[1].Status != "failed" || got.Results[1].Error != "exit status 2" {
+		t.Errorf("Unexpected second result: %+v", got.Results[1])
+	}
+}
+
+func TestExecutionSummaryToJSONEmpty(t *testing.T) {
+	data, err := (&ansible.ExecutionSummary{}).ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"results": []`) {
+		t.Errorf("Expected an empty results array, got %s", data)
+	}
+}
+
+func TestParseVaultID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ansible.VaultID
+	}{
+		{"dev@C:\\secrets\\dev.txt", ansible.VaultID{Label: "dev", Source: "C:\\secrets\\dev.txt"}},
+		{"prod@prompt", ansible.VaultID{Label: "prod", Source: ansible.VaultPromptSource}},
+		{"prod@@prompt", ansible.VaultID{Label: "prod", Source: ansible.VaultPromptSource}},
+		{"./vault-pass", ansible.VaultID{Source: "./vault-pass"}},
+	}
+	for _, tt := range tests {
+		got, err := ansible.ParseVaultID(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseVaultID(%q) = %+v, %v; want %+v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "dev@", "my label@file", "dev@it's"} {
+		if _, err := ansible.ParseVaultID(value); err == nil {
+			t.Errorf("ParseVaultID(%q): expected error", value)
+		}
+	}
+}
+
+func TestBuildAnsibleCommandVaultIDs(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{
+		VaultIDs: []ansible.VaultID{
+			{Label: "dev", Source: "/tmp/autowsl-vault-id-0"},
+			{Label: "prod", Source: ansible.VaultPromptSource},
+		},
+	})
+	if !strings.Contains(cmd, " --vault-id 'dev@/tmp/autowsl-vault-id-0' --vault-id 'prod@prompt'") {
+		t.Errorf("Expected both vault IDs, got: %s", cmd)
+	}
+	if !ansible.HasVaultPrompt([]ansible.VaultID{{Label: "prod", Source: ansible.VaultPromptSource}}) {
+		t.Error("Expected HasVaultPrompt to detect the prompt source")
+	}
+}
+
+func TestValidateExtraVarsFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"vars.json":  `{"users": [{"name": "dev", "groups": ["docker"]}]}`,
+		"vars.yml":   "users:\n  - name: dev\n",
+		"broken.yml": "users: [dev\n",
+		"list.yml":   "- dev\n- ops\n",
+		"empty.yml":  "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"vars.json", "vars.yml"} {
+		if err := ansible.ValidateExtraVarsFile(filepath.Join(dir, name)); err != nil {
+			t.Errorf("ValidateExtraVarsFile(%s) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"broken.yml", "list.yml", "empty.yml", "missing.yml"} {
+		if err := ansible.ValidateExtraVarsFile(filepath.Join(dir, name)); err == nil {
+			t.Errorf("ValidateExtraVarsFile(%s): expected error", name)
+		}
+	}
+}
+
+func TestBuildAnsibleCommandExtraVarsFile(t *testing.T) {
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{
+		ExtraVarsFile: "/tmp/autowsl-extravars.json",
+		ExtraVars:     map[string]any{"env": "dev"},
+	})
+	want := ` --extra-vars '@/tmp/autowsl-extravars.json' --extra-vars '{"env":"dev"}'`
+	if !strings.Contains(cmd, want) {
+		t.Errorf("Expected %q in: %s", want, cmd)
+	}
+}
+
+func TestBuildAnsibleCommandExtraVars(t *testing.T) {
+	jsonFile := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(jsonFile, []byte(`{"users": ["alice", "bob"], "uid": 1001}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	vars, err := playbooks.ParseExtraVars([]string{`greeting="hello world"`, "owner=it's me", "data=@" + jsonFile})
+	if err != nil {
+		t.Fatalf("ParseExtraVars failed: %v", err)
+	}
+
+	cmd := ansible.BuildAnsibleCommand("/tmp/playbook.yml", ansible.PlaybookOptions{ExtraVars: vars})
+	want := ` --extra-vars '{"data":{"uid":1001,"users":["alice","bob"]},"greeting":"hello world","owner":"it'\''s me"}'`
+	if !strings.HasSuffix(cmd, want) {
+		t.Errorf("Expected a single JSON --extra-vars argument %q in: %s", want, cmd)
+	}
+	if strings.Count(cmd, "--extra-vars") != 1 {
+		t.Errorf("Expected one --extra-vars argument: %s", cmd)
+	}
+}
+
+func TestPrefixEnv(t *testing.T) {
+	if got := ansible.PrefixEnv(nil, "ansible-playbook site.yml"); got != "ansible-playbook site.yml" {
+		t.Errorf("PrefixEnv(nil) = %q", got)
+	}
+
+	got := ansible.PrefixEnv(map[string]string{
+		"ANSIBLE_CONFIG":   "/tmp/autowsl-ansible.cfg",
+		"ANSIBLE_NOCOLOR":  "1",
+		"ANSIBLE_LOG_PATH": "/tmp/it's.log",
+	}, "ansible-playbook site.yml")
+	want := `ANSIBLE_CONFIG='/tmp/autowsl-ansible.cfg' ANSIBLE_LOG_PATH='/tmp/it'\''s.log' ANSIBLE_NOCOLOR='1' ansible-playbook site.yml`
+	if got != want {
+		t.Errorf("PrefixEnv() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildPipInstallCommand(t *testing.T) {
+	got := ansible.BuildPipInstallCommand([]string{"boto3", "docker[tls]>=7"})
+	want := "sudo PIP_BREAK_SYSTEM_PACKAGES=1 pip3 install 'boto3' 'docker[tls]>=7'"
+	if got != want {
+		t.Errorf("BuildPipInstallCommand() = %q, want %q", got, want)
+	}
+
+	if err := ansible.ValidatePipPackages([]string{"requests", "boto3==1.34.0"}); err != nil {
+		t.Errorf("ValidatePipPackages() = %v", err)
+	}
+	for _, bad := range []string{"", "-r", "x; rm -rf /", "a'b"} {
+		if err := ansible.ValidatePipPackages([]string{bad}); err == nil {
+			t.Errorf("ValidatePipPackages(%q): expected error", bad)
+		}
+	}
+}
+
+func TestLoadTagsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tags.yml")
+	if err := os.WriteFile(path, []byte("tags: [docker, nodejs, python]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := ansible.LoadTagsFile(path)
+	if err != nil {
+		t.Fatalf("LoadTagsFile failed: %v", err)
+	}
+	if got := strings.Join(tags, ","); got != "docker,nodejs,python" {
+		t.Errorf("LoadTagsFile() = %q", got)
+	}
+
+	invalid := map[string]string{
+		"empty.yml":  "tags: []\n",
+		"broken.yml": "tags: [docker\n",
+		"comma.yml":  "tags: ['a,b']\n",
+	}
+	for name, content := range invalid {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ansible.LoadTagsFile(p); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := ansible.MergeTags([]string{"docker", "git"}, []string{"nodejs", "docker", "python", "nodejs"})
+	if strings.Join(got, ",") != "docker,git,nodejs,python" {
+		t.Errorf("MergeTags() = %v", got)
+	}
+}
+
+func TestBuildGalaxyRolesCommand(t *testing.T) {
+	got := ansible.BuildGalaxyRolesCommand("/tmp/autowsl-galaxy-roles.yml")
+	want := "ansible-galaxy role install -r '/tmp/autowsl-galaxy-roles.yml'"
+	if got != want {
+		t.Errorf("BuildGalaxyRolesCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestInstallGalaxyRequirementsMissingFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "roles.yml")
+	if err := ansible.InstallRoles("Ubuntu", missing); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error for roles, got %v", err)
+	}
+	if err := ansible.InstallCollections("Ubuntu", missing); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error for collections, got %v", err)
+	}
+}
+
+func TestReinstallAnsibleWhenInstalled(t *testing.T) {
+	mock := NewMockRunner()
+	// Ansible is present; the distro is not Kali
+	mock.Errors["wsl.exe -d Reinstall sh -c grep -i kali /etc/os-release"] = errors.New("exit status 1")
+	ansible.SetRunner(mock)
+	defer ansible.SetRunner(nil)
+	ansible.SetDryRun(false)
+
+	installCmd := "wsl.exe -d Reinstall sh -c sudo apt-get install -y ansible"
+	installed := func() bool {
+		for _, call := range mock.Calls {
+			if call == installCmd {
+				return true
+			}
+		}
+		return false
+	}
+
+	if err := ansible.EnsureAnsible("Reinstall"); err != nil {
+		t.Fatalf("EnsureAnsible failed: %v", err)
+	}
+	if installed() {
+		t.Error("Expected EnsureAnsible not to install Ansible when it is present")
+	}
+
+	if err := ansible.ReinstallAnsible("Reinstall"); err != nil {
+		t.Fatalf("ReinstallAnsible failed: %v", err)
+	}
+	if !installed() {
+		t.Errorf("Expected ReinstallAnsible to install Ansible, calls: %v", mock.Calls)
+	}
+}
+
+func TestExecutePlaybookPrePostCmds(t *testing.T) {
+	playbook := filepath.Join(t.TempDir(), "site.yml")
+	if err := os.WriteFile(playbook, []byte("- hosts: localhost\n  tasks: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ansible.SetDryRun(true)
+	defer ansible.SetDryRun(false)
+
+	var out bytes.Buffer
+	err := ansible.ExecutePlaybook(ansible.PlaybookOptions{
+		DistroName:       "Ubuntu",
+		PlaybookPath:     playbook,
+		PrePlaybookCmds:  []string{"echo before"},
+		PostPlaybookCmds: []string{"echo after"},
+		Output:           &out,
+		AnsibleReady:     true,
+	})
+	if err != nil {
+		t.Fatalf("ExecutePlaybook failed: %v", err)
+	}
+
+	output := out.String()
+	before := strings.Index(output, "echo before")
+	playbookRun := strings.Index(output, "ansible-playbook")
+	after := strings.Index(output, "echo after")
+	if before < 0 || playbookRun < 0 || after < 0 || !(before < playbookRun && playbookRun < after) {
+		t.Errorf("Expected pre command, playbook, then post command; got:\n%s", output)
+	}
+}
+
+func TestEnsureSystemd(t *testing.T) {
+	ansible.SetDryRun(false)
+	updated := func(mock *MockRunner) bool {
+		for _, call := range mock.Calls {
+			if strings.HasPrefix(call, "wsl.exe -d Ubuntu sh -c f=/etc/wsl.conf;") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The check succeeds: systemd is already enabled, nothing is written
+	mock := NewMockRunner()
+	ansible.SetRunner(mock)
+	defer ansible.SetRunner(nil)
+	if !ansible.SystemdEnabled("Ubuntu") {
+		t.Error("Expected SystemdEnabled to report true when the check succeeds")
+	}
+	if err := ansible.EnsureSystemd("Ubuntu"); err != nil {
+		t.Fatalf("EnsureSystemd failed: %v", err)
+	}
+	if updated(mock) {
+		t.Errorf("Expected EnsureSystemd not to rewrite wsl.conf, calls: %v", mock.Calls)
+	}
+
+	// The check fails: wsl.conf is rewritten with systemd=true
+	checkCmd := mock.Calls[0]
+	mock = NewMockRunner()
+	mock.Errors[checkCmd] = errors.New("exit status 1")
+	ansible.SetRunner(mock)
+	if ansible.SystemdEnabled("Ubuntu") {
+		t.Error("Expected SystemdEnabled to report false when the check fails")
+	}
+	if err := ansible.EnsureSystemd("Ubuntu"); err != nil {
+		t.Fatalf("EnsureSystemd failed: %v", err)
+	}
+	if !updated(mock) {
+		t.Errorf("Expected EnsureSystemd to rewrite wsl.conf, calls: %v", mock.Calls)
+	}
+}
+
+func TestExecutePlaybookUsesRunner(t *testing.T) {
+	playbook := filepath.Join(t.TempDir(), "site.yml")
+	if err := os.WriteFile(playbook, []byte("- hosts: localhost\n  tasks: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mock := NewMockRunner()
+	ansible.SetRunner(mock)
+	defer ansible.SetRunner(nil)
+	ansible.SetDryRun(false)
+
+	var out bytes.Buffer
+	if err := ansible.ExecutePlaybook(ansible.PlaybookOptions{
+		DistroName:   "Ubuntu",
+		PlaybookPath: playbook,
+		Output:       &out,
+		AnsibleReady: true,
+	}); err != nil {
+		t.Fatalf("ExecutePlaybook failed: %v", err)
+	}
+
+	var copied, sudoChecked bool
+	for _, call := range mock.Calls {
+		copied = copied || strings.HasPrefix(call, "wsl.exe -d Ubuntu sh -c umask 077 && cat > '/tmp/")
+		sudoChecked = sudoChecked || call == `wsl.exe -d Ubuntu sh -c [ "$(id -u)" = 0 ] || sudo -n true`
+	}
+	if !copied {
+		t.Errorf("Expected the playbook to be copied through the runner, calls: %v", mock.Calls)
+	}
+	if !sudoChecked {
+		t.Errorf("Expected the sudo check to go through the runner, calls: %v", mock.Calls)
+	}
+}
+
+// TestBuildCloneCommandQuotesArguments runs the clone command through sh with
+// git replaced by a function that prints what it received
+func TestBuildCloneCommandQuotesArguments(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	opts := ansible.CloneOptions{RepoURL: "https://example.com/o'brien/repo", DestDir: "/tmp/it's here", Branch: "fix'; echo pwned; '"}
+	cmd := ansible.BuildCloneCommand(opts, "/tmp/o'brien key")
+	script := `git() { printf '%s\n' "$GIT_SSH_COMMAND" "$@"; }; ` + cmd
+	out, err := exec.Command(sh, "-c", script).Output()
+	if err != nil {
+		t.Fatalf("sh -c %q failed: %v", script, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	want := []string{"", "clone", "--branch", opts.Branch, opts.RepoURL, opts.DestDir}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %q, got %q", want, lines)
+	}
+	for i := 1; i < len(want); i++ {
+		if lines[i] != want[i] {
+			t.Errorf("Argument %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+
+	// GIT_SSH_COMMAND is itself run by the shell, so it must yield the key path intact
+	out, err = exec.Command(sh, "-c", `printf '%s\n' `+lines[0]).Output()
+	if err != nil {
+		t.Fatalf("GIT_SSH_COMMAND %q is not valid shell: %v", lines[0], err)
+	}
+	if got := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"); len(got) != 5 || got[2] != "/tmp/o'brien key" {
+		t.Errorf("GIT_SSH_COMMAND %q split into %q", lines[0], got)
+	}
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/batch"
+)
+
+func TestBatchParse(t *testing.T) {
+	spec := `
+- version: "Ubuntu 22.04 LTS"
+  name: ubuntu-dev
+  playbooks: [git, ./dev.yml]
+- version: "Debian GNU/Linux 12"
+  name: debian
+  path: D:\WSL\debian
+  wsl_version: 1
+`
+	entries, err := batch.Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].WSLVersion != batch.DefaultWSLVersion || len(entries[0].Playbooks) != 2 {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].WSLVersion != 1 || entries[1].Path != `D:\WSL\debian` {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestBatchParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":           "[]",
+		"missing version": "- name: ubuntu\n",
+		"bad name":        "- version: Ubuntu\n  name: my distro\n",
+		"duplicate":       "- version: Ubuntu\n  name: a\n- version: Debian\n  name: a\n",
+		"bad wsl_version": "- version: Ubuntu\n  name: a\n  wsl_version: 3\n",
+		"not a list":      "version: Ubuntu\n",
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := batch.Parse([]byte(spec)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestBatchCompare(t *testing.T) {
+	entries := []batch.Entry{
+		{Version: "Ubuntu 22.04 LTS", Name: "ubuntu"},
+		{Version: "Debian GNU/Linux 12", Name: "debian"},
+		{Version: "Fedora 40", Name: "fedora"},
+	}
+	installed := map[string]batch.Installed{
+		"ubuntu": {State: "Running", DistroVersion: "Ubuntu 22.04 LTS"},
+		"debian": {State: "Stopped", DistroVersion: "Debian GNU/Linux 11"},
+	}
+
+	statuses := batch.Compare(entries, installed)
+	want := []string{batch.StatusOK, batch.StatusVersionMismatch, batch.StatusMissing}
+	for i, s := range statuses {
+		if s.Status != want[i] {
+			t.Errorf("%s: status = %q, want %q", s.Name, s.Status, want[i])
+		}
+	}
+	if statuses[0].State != "Running" || statuses[2].State != "" {
+		t.Errorf("Unexpected states: %+v", statuses)
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/ansible"
+)
+
+// selfSignedCert returns a DER encoded self-signed CA certificate
+func selfSignedCert(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return der
+}
+
+func TestCertificatePEM(t *testing.T) {
+	der := selfSignedCert(t)
+
+	fromDER, err := ansible.CertificatePEM(der)
+	if err != nil {
+		t.Fatalf("CertificatePEM(DER) failed: %v", err)
+	}
+	if !strings.HasPrefix(string(fromDER), "-----BEGIN CERTIFICATE-----") {
+		t.Errorf("Expected PEM output, got %q", fromDER)
+	}
+
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	fromPEM, err := ansible.CertificatePEM(pemData)
+	if err != nil {
+		t.Fatalf("CertificatePEM(PEM) failed: %v", err)
+	}
+	if string(fromPEM) != string(pemData) {
+		t.Errorf("Expected PEM input to be kept, got %q", fromPEM)
+	}
+
+	if _, err := ansible.CertificatePEM([]byte("not a certificate")); err == nil {
+		t.Error("Expected error for invalid data")
+	}
+}
+
+func TestCertificateTarget(t *testing.T) {
+	dir, update, err := ansible.CertificateTarget("apt")
+	if err != nil || dir != "/usr/local/share/ca-certificates" || update != "update-ca-certificates" {
+		t.Errorf("Unexpected apt target: %s, %s, %v", dir, update, err)
+	}
+	dir, update, err = ansible.CertificateTarget("dnf")
+	if err != nil || dir != "/etc/pki/ca-trust/source/anchors" || update != "update-ca-trust" {
+		t.Errorf("Unexpected dnf target: %s, %s, %v", dir, update, err)
+	}
+	if _, _, err := ansible.CertificateTarget("brew"); err == nil {
+		t.Error("Expected error for unsupported package manager")
+	}
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/checksum"
+)
+
+func TestChecksumWriteSidecar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Ubuntu.appx")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := checksum.WriteSidecar(path)
+	if err != nil {
+		t.Fatalf("WriteSidecar() error = %v", err)
+	}
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if sum != want {
+		t.Errorf("WriteSidecar() = %s, want %s", sum, want)
+	}
+
+	data, err := os.ReadFile(checksum.SidecarPath(path))
+	if err != nil {
+		t.Fatalf("failed to read sidecar: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != want+"  Ubuntu.appx" {
+		t.Errorf("sidecar = %q", got)
+	}
+
+	if err := checksum.VerifyFile(path, strings.ToUpper(sum)); err != nil {
+		t.Errorf("VerifyFile() error = %v", err)
+	}
+	if err := checksum.VerifyFile(path, strings.Repeat("0", 64)); err == nil {
+		t.Error("Expected checksum mismatch")
+	}
+}
+
+func TestChecksumVerifyStream(t *testing.T) {
+	hello := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	var copied strings.Builder
+	if err := checksum.VerifyStream(io.TeeReader(strings.NewReader("hello"), &copied), hello); err != nil {
+		t.Errorf("VerifyStream() error = %v", err)
+	}
+	if copied.String() != "hello" {
+		t.Errorf("Expected the data to flow through, got %q", copied.String())
+	}
+
+	err := checksum.VerifyStream(strings.NewReader("hellO"), hello)
+	if !errors.Is(err, checksum.ErrMismatch) {
+		t.Errorf("Expected ErrMismatch, got %v", err)
+	}
+}
+
+func TestChecksumReader(t *testing.T) {
+	r := checksum.NewChecksumReader(strings.NewReader("hello"))
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("ReadAll() = %q, %v", data, err)
+	}
+	if got := r.Digest(); got != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Digest() = %s", got)
+	}
+}
@@ -0,0 +1,457 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yuanjua/autowsl/cmd"
+	"github.com/yuanjua/autowsl/internal/ansible"
+	"github.com/yuanjua/autowsl/internal/config"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/hooks"
+	"github.com/yuanjua/autowsl/internal/runner"
+	"github.com/yuanjua/autowsl/internal/ui"
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+// Importing cmd runs the init of every command, which panics when flags are
+// wired up incorrectly (e.g. marking an unregistered flag as exclusive).
+// Running --help on every command also parses its flags and help template.
+func TestCommandsInitialize(t *testing.T) {
+	if cmd.Version == "" {
+		t.Error("Expected a version string")
+	}
+	isolateHome(t)
+
+	var walk func(c *cobra.Command, path []string)
+	walk = func(c *cobra.Command, path []string) {
+		args := append(append([]string{}, path...), "--help")
+		out, err := runAutowsl(t, NewMockRunner(), args...)
+		if err != nil {
+			t.Errorf("autowsl %s failed: %v", strings.Join(args, " "), err)
+		} else if !strings.Contains(out, "Usage:") {
+			t.Errorf("autowsl %s printed no usage:\n%s", strings.Join(args, " "), out)
+		}
+		for _, sub := range c.Commands() {
+			walk(sub, append(path, sub.Name()))
+		}
+	}
+	walk(cmd.RootCommand(), nil)
+}
+
+// isolateHome points the config file and ~/.autowsl at a temporary directory
+func isolateHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("APPDATA", home)
+	return home
+}
+
+// resetFlags restores every flag of the command tree to its default, because
+// cobra keeps flag values between runs of the same command
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// runAutowsl runs an autowsl command line with WSL commands going to r and
+// returns what it printed to stdout
+func runAutowsl(t *testing.T, r runner.Runner, args ...string) (string, error) {
+	t.Helper()
+	wsl.SetRunner(r)
+	ansible.SetRunner(r)
+	defer wsl.SetRunner(nil)
+	defer ansible.SetRunner(nil)
+	defer ui.SetPrompter(ui.InteractivePrompter{})
+	defer wsl.SetDryRun(false)
+	defer ansible.SetDryRun(false)
+	defer extractor.SetDryRun(false)
+	defer hooks.SetDryRun(false)
+
+	root := cmd.RootCommand()
+	resetFlags(root)
+	root.SetArgs(args)
+	defer root.SetArgs(nil)
+
+	stdout := os.Stdout
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = pw
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&out, pr)
+		close(done)
+	}()
+
+	runErr := root.Execute()
+	os.Stdout = stdout
+	pw.Close()
+	<-done
+	return out.String(), runErr
+}
+
+// default_wsl_version is the version to install with; commands whose
+// --version means something else keep their own default
+func TestRestoreIgnoresDefaultWSLVersion(t *testing.T) {
+	isolateHome(t)
+	t.Setenv("AUTOWSL_WSL_VERSION", "1")
+	backup := writeBackup(t, "dev-backup.tar", tarBytes(t))
+	installPath := t.TempDir()
+
+	mock := NewMockRunner()
+	if out, err := runAutowsl(t, mock, "restore", backup, "--path", installPath); err != nil {
+		t.Fatalf("restore failed: %v\n%s", err, out)
+	}
+	if call := importCall(mock.Calls, "dev"); !strings.HasSuffix(call, " --version 2") {
+		t.Errorf("Expected restore to keep its own --version default, got %q", call)
+	}
+
+	// The mock export writes nothing, so provide the tar it would create
+	tempDir := t.TempDir()
+	t.Setenv("AUTOWSL_TEMP_DIR", tempDir)
+	if err := os.WriteFile(filepath.Join(tempDir, "Ubuntu-export.tar"), []byte("rootfs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mock = NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE      VERSION\n* Ubuntu    Stopped    2\n"
+	if out, err := runAutowsl(t, mock, "copy", "Ubuntu", "--name", "dev", "--path", installPath); err != nil {
+		t.Fatalf("copy failed: %v\n%s", err, out)
+	}
+	if call := importCall(mock.Calls, "dev"); !strings.HasSuffix(call, " --version 1") {
+		t.Errorf("Expected copy to use default_wsl_version, got %q", call)
+	}
+}
+
+func TestMoveIgnoresNoCleanupConfig(t *testing.T) {
+	isolateHome(t)
+	t.Setenv("AUTOWSL_NO_CLEANUP", "true")
+	tempDir := t.TempDir()
+	t.Setenv("AUTOWSL_TEMP_DIR", tempDir)
+	// The mock export writes nothing, so provide the tar it would create
+	if err := os.WriteFile(filepath.Join(tempDir, "Ubuntu-move.tar"), []byte("rootfs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE      VERSION\n* Ubuntu    Stopped    1\n"
+	mock.Outputs[`reg.exe query HKCU\Software\Microsoft\Windows\CurrentVersion\Lxss /s`] =
+		"HKEY_CURRENT_USER\\Software\\Microsoft\\Windows\\CurrentVersion\\Lxss\\{1}\n" +
+			"    DistributionName    REG_SZ    Ubuntu\n" +
+			"    BasePath    REG_SZ    " + t.TempDir() + "\n" +
+			"HKEY_CURRENT_USER\\Software\\Microsoft\\Windows\\CurrentVersion\\Lxss\\{2}\n" +
+			"    DistributionName    REG_SZ    Ubuntu-moving\n"
+
+	out, err := runAutowsl(t, &unregisteringRunner{mock}, "move", "Ubuntu", "--path", t.TempDir(), "--yes")
+	if err != nil {
+		t.Fatalf("move failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "Ubuntu-moved") {
+		t.Errorf("Expected no_cleanup not to keep the original:\n%s", out)
+	}
+	if !strings.Contains(strings.Join(mock.Calls, "\n"), "wsl.exe --unregister Ubuntu") {
+		t.Errorf("Expected the original to be replaced, calls: %v", mock.Calls)
+	}
+}
+
+func TestCatalogShowTypoNonInteractive(t *testing.T) {
+	isolateHome(t)
+
+	out, err := runAutowsl(t, NewMockRunner(), "catalog", "show", "ubuntu 22.04 lst", "--yes")
+	if err == nil {
+		t.Fatalf("Expected --yes not to accept the closest match, got:\n%s", out)
+	}
+	if !strings.Contains(err.Error(), "did you mean 'Ubuntu 22.04 LTS'") {
+		t.Errorf("Expected the suggestion in the error, got: %v", err)
+	}
+}
+
+func TestSetDefaultVerifiesTarget(t *testing.T) {
+	isolateHome(t)
+
+	// WSL accepted the command but reports no default at all
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE      VERSION\n  Ubuntu    Stopped    2\n  Debian    Stopped    2\n"
+	if _, err := runAutowsl(t, mock, "set-default", "Debian"); err == nil {
+		t.Error("Expected an error when Debian is not reported as the default")
+	}
+
+	mock = NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE      VERSION\n  Ubuntu    Stopped    2\n* Debian    Stopped    2\n"
+	out, err := runAutowsl(t, mock, "set-default", "Debian")
+	if err != nil {
+		t.Fatalf("set-default failed: %v", err)
+	}
+	if !strings.Contains(out, "Default distribution set to 'Debian'") {
+		t.Errorf("Unexpected output: %s", out)
+	}
+}
+
+func TestImportFromURLCommand(t *testing.T) {
+	isolateHome(t)
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("TMP", os.Getenv("TMPDIR"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("rootfs"))
+	}))
+	defer server.Close()
+
+	mock := NewMockRunner()
+	installPath := t.TempDir()
+	out, err := runAutowsl(t, mock, "import", "--url", server.URL+"/dev-rootfs.tar.gz", "--path", installPath)
+	if err != nil {
+		t.Fatalf("import failed: %v\n%s", err, out)
+	}
+
+	tarPath := filepath.Join(wsl.ImportDownloadDir("dev-rootfs"), "dev-rootfs.tar.gz")
+	want := "wsl.exe --import dev-rootfs " + installPath + " " + tarPath + " --version 2"
+	if !strings.Contains(strings.Join(mock.Calls, "\n"), want) {
+		t.Errorf("Expected %q, got calls: %v", want, mock.Calls)
+	}
+	if _, err := os.Stat(tarPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the download to be removed, got %v", err)
+	}
+
+	if _, err := runAutowsl(t, mock, "import", "--url", server.URL+"/a.tar", "--from-file", "b.tar"); err == nil {
+		t.Error("Expected --url and --from-file to be mutually exclusive")
+	}
+}
+
+func TestEnterPassesCommandArgv(t *testing.T) {
+	isolateHome(t)
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{
+			[]string{"enter", "Ubuntu", "--exec", "ls -la /home/my user"},
+			[]string{"wsl.exe", "-d", "Ubuntu", "--", "sh", "-c", "ls -la /home/my user"},
+		},
+		{
+			[]string{"enter", "Ubuntu", "--user", "root", "--", "echo", "a b", "it's"},
+			[]string{"wsl.exe", "-d", "Ubuntu", "-u", "root", "--", "echo", "a b", "it's"},
+		},
+	}
+
+	for _, tt := range tests {
+		mock := NewMockRunner()
+		mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE      VERSION\n* Ubuntu    Running    2\n"
+		if out, err := runAutowsl(t, mock, tt.args...); err != nil {
+			t.Fatalf("autowsl %s failed: %v\n%s", strings.Join(tt.args, " "), err, out)
+		}
+
+		var got []string
+		for _, argv := range mock.Argv {
+			if len(argv) > 1 && argv[1] == "-d" {
+				got = argv
+			}
+		}
+		if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+			t.Errorf("autowsl %s ran %q, want %q", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+}
+
+func TestInstallUsesConfigFile(t *testing.T) {
+	isolateHome(t)
+	t.Setenv("AUTOWSL_WSL_VERSION", "")
+	t.Setenv("AUTOWSL_INSTALL_PATH", "")
+	root := t.TempDir()
+	tempDir := t.TempDir()
+	t.Setenv("AUTOWSL_TEMP_DIR", tempDir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("rootfs"))
+	}))
+	defer server.Close()
+
+	if err := os.MkdirAll(filepath.Dir(config.Path()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configFile := "default_wsl_version: 1\ndefault_install_path: " + root + "\nkeep_tar: true\n"
+	if err := os.WriteFile(config.Path(), []byte(configFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tarPath := filepath.Join(tempDir, "dev.tar")
+	mock := NewMockRunner()
+	out, err := runAutowsl(t, mock, "install", "--url", server.URL+"/dev.tar", "--yes")
+	if err != nil {
+		t.Fatalf("install failed: %v\n%s", err, out)
+	}
+	want := "wsl.exe --import dev " + filepath.Join(root, "dev") + " " + tarPath + " --version 1"
+	if !strings.Contains(strings.Join(mock.Calls, "\n"), want) {
+		t.Errorf("Expected %q from the config file, got calls: %v", want, mock.Calls)
+	}
+	if _, err := os.Stat(tarPath); err != nil {
+		t.Errorf("Expected keep_tar to keep the tar file: %v", err)
+	}
+
+	// Flags still take precedence over the config file
+	mock = NewMockRunner()
+	installPath := t.TempDir()
+	out, err = runAutowsl(t, mock, "install", "--url", server.URL+"/dev.tar", "--yes",
+		"--version", "2", "--path", installPath, "--keep-tar=false")
+	if err != nil {
+		t.Fatalf("install with flags failed: %v\n%s", err, out)
+	}
+	want = "wsl.exe --import dev " + installPath + " " + tarPath + " --version 2"
+	if !strings.Contains(strings.Join(mock.Calls, "\n"), want) {
+		t.Errorf("Expected %q from the flags, got calls: %v", want, mock.Calls)
+	}
+	if _, err := os.Stat(tarPath); !os.IsNotExist(err) {
+		t.Errorf("Expected --keep-tar=false to remove the tar file, got %v", err)
+	}
+}
+
+func TestRenameDryRun(t *testing.T) {
+	isolateHome(t)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	mock := NewMockRunner()
+	out, err := runAutowsl(t, mock, "--dry-run", "rename", "missing", "renamed")
+	if err != nil {
+		t.Fatalf("Expected --dry-run rename to only print its steps, got: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Successfully renamed 'missing' to 'renamed'") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+	if _, err := os.Stat(".autowsl_tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected no temp directory in dry-run mode, got %v", err)
+	}
+}
+
+func TestProvisionParallelFlagConflicts(t *testing.T) {
+	isolateHome(t)
+
+	for _, flag := range []string{"--check", "--diff"} {
+		mock := NewMockRunner()
+		mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE      VERSION\n* Ubuntu    Running    2\n"
+		_, err := runAutowsl(t, mock, "provision", "Ubuntu", "--playbooks", "site.yml", flag, "--parallel")
+		if err == nil || !strings.Contains(err.Error(), "parallel") {
+			t.Errorf("Expected %s --parallel to be rejected, got %v", flag, err)
+		}
+		if len(mock.Calls) > 0 {
+			t.Errorf("Expected %s --parallel to fail before running anything, got %v", flag, mock.Calls)
+		}
+	}
+}
+
+func TestInstallDownloadUsesMaxRetries(t *testing.T) {
+	isolateHome(t)
+	t.Setenv("AUTOWSL_TEMP_DIR", t.TempDir())
+	defer distro.ResetCatalog()
+
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets++
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	catalog := filepath.Join(t.TempDir(), "catalog.json")
+	entry := `{"distributions": [{"group": "Corp", "version": "Corp Linux 1.0", "architecture": "x64", "url": "` + server.URL + `/corp.tar.gz"}]}`
+	if err := os.WriteFile(catalog, []byte(entry), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The catalog entry has no winget package, so this is the direct download
+	for _, tt := range []struct{ retries, want int }{{0, 1}, {1, 2}} {
+		gets = 0
+		_, err := runAutowsl(t, NewMockRunner(), "install", "Corp Linux 1.0", "--catalog", catalog,
+			"--name", "corp", "--yes", "--max-retries", fmt.Sprint(tt.retries))
+		if err == nil {
+			t.Fatalf("Expected the download to fail with --max-retries %d", tt.retries)
+		}
+		if gets != tt.want {
+			t.Errorf("Expected %d download attempt(s) with --max-retries %d, got %d", tt.want, tt.retries, gets)
+		}
+	}
+}
+
+func TestRemoteCatalogLoadedLazily(t *testing.T) {
+	home := isolateHome(t)
+	defer distro.ResetCatalog()
+
+	var requests int
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"distributions": [{"group": "Corp", "version": "Corp Linux 1.0", "architecture": "x64", "url": "https://example.com/corp.tar.gz"}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("AUTOWSL_CATALOG_URL", server.URL+"/distros.json")
+
+	// Commands that don't read the catalog never download it
+	for _, args := range [][]string{{"version"}, {"completion", "bash"}, {"__complete", "remove", ""}} {
+		if out, err := runAutowsl(t, NewMockRunner(), args...); err != nil {
+			t.Fatalf("autowsl %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no catalog download outside catalog commands, got %d", requests)
+	}
+
+	out, err := runAutowsl(t, NewMockRunner(), "catalog", "search", "corp")
+	if err != nil {
+		t.Fatalf("catalog search failed: %v", err)
+	}
+	if requests != 1 || !strings.Contains(out, "Corp Linux 1.0") {
+		t.Fatalf("Expected the remote entry after one download, got %d request(s):\n%s", requests, out)
+	}
+
+	// An expired cache is still used when the download fails
+	distro.ResetCatalog()
+	cachePath := filepath.Join(home, ".autowsl", distro.RemoteCatalogFile)
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(cachePath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	failing = true
+	out, err = runAutowsl(t, NewMockRunner(), "catalog", "search", "corp")
+	if err != nil {
+		t.Fatalf("catalog search failed: %v", err)
+	}
+	if requests != 2 || !strings.Contains(out, "Corp Linux 1.0") {
+		t.Errorf("Expected the expired cache after a failed download, got %d request(s):\n%s", requests, out)
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/config"
+)
+
+func TestConfigEnvOverrides(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("APPDATA", t.TempDir())
+	t.Setenv("AUTOWSL_WSL_VERSION", "1")
+	t.Setenv("AUTOWSL_INSTALL_PATH", "/mnt/d/wsl")
+	t.Setenv("AUTOWSL_MAX_RETRIES", "7")
+	t.Setenv("AUTOWSL_NO_CLEANUP", "true")
+
+	if err := config.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultWSLVersion != 1 {
+		t.Errorf("Expected WSL version 1 from the environment, got %d", cfg.DefaultWSLVersion)
+	}
+	if cfg.DefaultInstallPath != "/mnt/d/wsl" {
+		t.Errorf("Expected install path from the environment, got %q", cfg.DefaultInstallPath)
+	}
+	if cfg.MaxRetries != 7 {
+		t.Errorf("Expected 7 retries from the environment, got %d", cfg.MaxRetries)
+	}
+	if !cfg.NoCleanup {
+		t.Error("Expected no_cleanup from the environment")
+	}
+
+	for _, key := range []string{"default_wsl_version", "max_retries", "no_cleanup"} {
+		if !config.IsSet(key) {
+			t.Errorf("Expected %s to be set from the environment", key)
+		}
+	}
+	if config.IsSet("temp_dir") {
+		t.Error("Expected temp_dir to be unset")
+	}
+}
+
+func TestConfigEnvNames(t *testing.T) {
+	got := config.EnvNames("default_wsl_version")
+	want := []string{"AUTOWSL_DEFAULT_WSL_VERSION", "AUTOWSL_WSL_VERSION"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("EnvNames = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,311 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/distro"
+)
+
+func writeCatalog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadCatalogMerge(t *testing.T) {
+	defer distro.ResetCatalog()
+	embedded := len(distro.GetAllDistros())
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Corp", "version": "Corp Linux 1.0", "architecture": "x64", "url": "https://mirror.example.com/corp.appx"},
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Mirror.Ubuntu.2204"}
+	]}`)
+
+	if err := distro.LoadCatalog(path, false); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	distros := distro.GetAllDistros()
+	if len(distros) != embedded+1 {
+		t.Errorf("Expected %d distros after merge, got %d", embedded+1, len(distros))
+	}
+
+	d, err := distro.FindDistroByVersion("Ubuntu 22.04 LTS")
+	if err != nil {
+		t.Fatalf("Expected Ubuntu 22.04 LTS in merged catalog: %v", err)
+	}
+	if d.PackageID != "Mirror.Ubuntu.2204" {
+		t.Errorf("Expected external entry to override embedded one, got %s", d.PackageID)
+	}
+}
+
+func TestLoadCatalogReplace(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Corp", "version": "Corp Linux 1.0", "architecture": "x64", "packageId": "Corp.Linux"}
+	]}`)
+
+	if err := distro.LoadCatalog(path, true); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	distros := distro.GetAllDistros()
+	if len(distros) != 1 || distros[0].Version != "Corp Linux 1.0" {
+		t.Errorf("Expected only the external entry, got %+v", distros)
+	}
+}
+
+func TestGetGroups(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Corp", "version": "Corp Linux 1.0", "architecture": "x64", "packageId": "Corp.Linux"},
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2204"},
+		{"group": "Corp", "version": "Corp Linux 2.0", "architecture": "x64", "packageId": "Corp.Linux2"}
+	]}`)
+	if err := distro.LoadCatalog(path, true); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	if got := strings.Join(distro.GetGroups(), ","); got != "Corp,Ubuntu" {
+		t.Errorf("GetGroups() = %s, want Corp,Ubuntu", got)
+	}
+
+	group, err := distro.FindGroup("corp")
+	if err != nil || group != "Corp" {
+		t.Errorf("FindGroup(corp) = %q, %v; want Corp", group, err)
+	}
+	if len(distro.GetDistrosByGroup(group)) != 2 {
+		t.Errorf("Expected 2 distros in group Corp")
+	}
+	if _, err := distro.FindGroup("Arch"); err == nil {
+		t.Error("Expected error for an unknown group")
+	}
+}
+
+func TestLoadCatalogValidation(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"invalid json", `{"distributions": [`, "invalid catalog"},
+		{"empty", `{"distributions": []}`, "no entries"},
+		{"missing group", `{"distributions": [{"version": "X", "packageId": "A.B"}]}`, `"group"`},
+		{"missing version", `{"distributions": [{"group": "X", "packageId": "A.B"}]}`, `"version"`},
+		{"missing source", `{"distributions": [{"group": "X", "version": "Y"}]}`, `"packageId"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := distro.LoadCatalog(writeCatalog(t, tt.content), false)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestSearchCatalog(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Ubuntu", "version": "Ubuntu 24.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2404"},
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2204"},
+		{"group": "Debian", "version": "Debian GNU/Linux", "architecture": "x64", "packageId": "Debian.Debian"}
+	]}`)
+	if err := distro.LoadCatalog(path, true); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	if got := distro.Search("UBUNTU", ""); len(got) != 2 {
+		t.Errorf("Expected 2 case-insensitive matches, got %v", got)
+	}
+	if got := distro.Search("canonical.ubuntu.2404", ""); len(got) != 1 || got[0].Version != "Ubuntu 24.04 LTS" {
+		t.Errorf("Expected package ID match, got %v", got)
+	}
+	if got := distro.Search("", "debian"); len(got) != 1 || got[0].Group != "Debian" {
+		t.Errorf("Expected group filter to match Debian only, got %v", got)
+	}
+	if got := distro.Search("22.04", "debian"); len(got) != 0 {
+		t.Errorf("Expected no matches outside the group, got %v", got)
+	}
+}
+
+func TestFindDistroFuzzyAndPackageID(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Ubuntu", "version": "Ubuntu 24.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2404"},
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2204"},
+		{"group": "Debian", "version": "Debian GNU/Linux", "architecture": "x64", "url": "https://example.com/debian.appx"}
+	]}`)
+	if err := distro.LoadCatalog(path, true); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	if got, err := distro.FindDistroByVersionFuzzy("ubuntu"); err != nil || len(got) != 2 {
+		t.Errorf("Expected 2 partial matches, got %v, %v", got, err)
+	}
+	if got, err := distro.FindDistroByVersionFuzzy("22.04 lts"); err != nil || len(got) != 1 || got[0].Version != "Ubuntu 22.04 LTS" {
+		t.Errorf("Expected Ubuntu 22.04 LTS, got %v, %v", got, err)
+	}
+	if _, err := distro.FindDistroByVersionFuzzy("fedora"); err == nil {
+		t.Error("Expected error when nothing matches")
+	}
+	if _, err := distro.FindDistroByVersionFuzzy(" "); err == nil {
+		t.Error("Expected error for an empty query")
+	}
+
+	d, err := distro.FindDistroByPackageID("canonical.UBUNTU.2404")
+	if err != nil || d.Version != "Ubuntu 24.04 LTS" {
+		t.Errorf("Expected case-insensitive package ID match, got %v, %v", d, err)
+	}
+	if _, err := distro.FindDistroByPackageID("Canonical.Ubuntu"); err == nil {
+		t.Error("Expected error for a partial package ID")
+	}
+	if _, err := distro.FindDistroByPackageID(""); err == nil {
+		t.Error("Expected error for an empty package ID matching an entry without one")
+	}
+}
+
+func TestClosestMatches(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Ubuntu", "version": "Ubuntu 24.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2404"},
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2204"},
+		{"group": "Debian", "version": "Debian GNU/Linux", "architecture": "x64", "packageId": "Debian.Debian"}
+	]}`)
+	if err := distro.LoadCatalog(path, true); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	matches := distro.ClosestMatches("ubuntu 22.04 lst", 3)
+	if len(matches) != 3 {
+		t.Fatalf("Expected 3 matches, got %v", matches)
+	}
+	if matches[0].Distro.Version != "Ubuntu 22.04 LTS" || matches[0].Score < distro.AutoCorrectScore {
+		t.Errorf("Expected 'Ubuntu 22.04 LTS' to be a confident best match, got %+v", matches[0])
+	}
+	if matches[1].Score >= distro.AutoCorrectScore {
+		t.Errorf("Expected only one confident match, got %+v", matches[1])
+	}
+
+	if matches := distro.ClosestMatches("Canonical.Ubuntu.2404", 1); matches[0].Score != 100 {
+		t.Errorf("Expected exact package ID to score 100, got %+v", matches[0])
+	}
+}
+
+func TestFetchRemoteCatalog(t *testing.T) {
+	requests := 0
+	body := `{"distributions": [{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Remote.Ubuntu.2204"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/bad.json" {
+			w.Write([]byte(`{"distributions": []}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+	url := server.URL + "/distros.json"
+
+	distros, err := distro.FetchRemoteCatalog(url, cacheDir, time.Hour)
+	if err != nil {
+		t.Fatalf("FetchRemoteCatalog failed: %v", err)
+	}
+	if len(distros) != 1 || distros[0].PackageID != "Remote.Ubuntu.2204" {
+		t.Errorf("Unexpected remote entries: %+v", distros)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, distro.RemoteCatalogFile)); err != nil {
+		t.Errorf("Expected the catalog to be cached: %v", err)
+	}
+
+	// A fresh cache is used; maxAge 0 or another URL downloads again
+	if _, err := distro.FetchRemoteCatalog(url, cacheDir, time.Hour); err != nil || requests != 1 {
+		t.Errorf("Expected the cached catalog to be used, got %d request(s), %v", requests, err)
+	}
+	if _, err := distro.FetchRemoteCatalog(url, cacheDir, 0); err != nil || requests != 2 {
+		t.Errorf("Expected maxAge 0 to download again, got %d request(s), %v", requests, err)
+	}
+	if _, err := distro.FetchRemoteCatalog(url+"?v=2", cacheDir, time.Hour); err != nil || requests != 3 {
+		t.Errorf("Expected a different URL to download again, got %d request(s), %v", requests, err)
+	}
+
+	// An invalid download is rejected and leaves the cache alone
+	if _, err := distro.FetchRemoteCatalog(server.URL+"/bad.json", cacheDir, 0); err == nil {
+		t.Error("Expected error for a catalog without entries")
+	}
+	if _, err := distro.FetchRemoteCatalog(url+"?v=2", cacheDir, time.Hour); err != nil || requests != 4 {
+		t.Errorf("Expected the previous cache to be kept, got %d request(s), %v", requests, err)
+	}
+}
+
+func TestRemoteCatalogPriority(t *testing.T) {
+	defer distro.ResetCatalog()
+
+	distro.SetRemoteCatalog([]distro.Distro{
+		{Group: "Ubuntu", Version: "Ubuntu 22.04 LTS", Architecture: "x64", PackageID: "Remote.Ubuntu.2204"},
+	})
+	d, err := distro.FindDistroByVersion("Ubuntu 22.04 LTS")
+	if err != nil || d.PackageID != "Remote.Ubuntu.2204" {
+		t.Fatalf("Expected the remote entry to override the embedded one, got %+v, %v", d, err)
+	}
+
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Local.Ubuntu.2204"}
+	]}`)
+	if err := distro.LoadCatalog(path, false); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	d, err = distro.FindDistroByVersion("Ubuntu 22.04 LTS")
+	if err != nil || d.PackageID != "Local.Ubuntu.2204" {
+		t.Errorf("Expected the --catalog entry to override the remote one, got %+v, %v", d, err)
+	}
+}
+
+func TestCachedRemoteCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"distributions": [{"group": "Corp", "version": "Corp Linux 1.0", "architecture": "x64", "url": "https://example.com/corp.tar.gz"}]}`))
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+	url := server.URL + "/distros.json"
+
+	if _, _, ok := distro.CachedRemoteCatalog(url, cacheDir); ok {
+		t.Fatal("Expected no cached catalog before the first download")
+	}
+	if _, err := distro.FetchRemoteCatalog(url, cacheDir, time.Hour); err != nil {
+		t.Fatalf("FetchRemoteCatalog failed: %v", err)
+	}
+
+	// Expired copies are returned too, but only for the URL they came from
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(cacheDir, distro.RemoteCatalogFile), old, old); err != nil {
+		t.Fatal(err)
+	}
+	distros, cachedAt, ok := distro.CachedRemoteCatalog(url, cacheDir)
+	if !ok || len(distros) != 1 || time.Since(cachedAt) < 47*time.Hour {
+		t.Errorf("Expected the expired cache, got %+v at %v (%v)", distros, cachedAt, ok)
+	}
+	if _, _, ok := distro.CachedRemoteCatalog(url+"?v=2", cacheDir); ok {
+		t.Error("Expected no cached catalog for another URL")
+	}
+}
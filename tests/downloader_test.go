@@ -0,0 +1,260 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/checksum"
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/downloader"
+)
+
+var downloadPayload = strings.Repeat("autowsl-rootfs-", 1000)
+
+func payloadSHA256() string {
+	sum := sha256.Sum256([]byte(downloadPayload))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRangeServer serves downloadPayload; rangeStatus controls how Range requests are answered
+func newRangeServer(t *testing.T, rangeStatus int, requests *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.Header.Get("Range"))
+		w.Header().Set("Accept-Ranges", "bytes")
+
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", fmt.Sprint(len(downloadPayload)))
+			return
+		}
+
+		rangeHeader := r.Header.Get("Range")
+		if rangeHeader == "" {
+			w.Write([]byte(downloadPayload))
+			return
+		}
+
+		if rangeStatus == http.StatusRequestedRangeNotSatisfiable {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+
+		var start int
+		fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(downloadPayload)-1, len(downloadPayload)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(downloadPayload[start:]))
+	}))
+}
+
+func downloadWithPartial(t *testing.T, srv *httptest.Server, partial string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if partial != "" {
+		if err := os.WriteFile(filepath.Join(dir, "rootfs.appx"), []byte(partial), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := downloader.New()
+	d.VerifyChecksum = true
+	d.SetProgressOutput(io.Discard)
+	path, err := d.DownloadToDir(distro.Distro{URL: srv.URL + "/rootfs.appx", SHA256: payloadSHA256()}, dir)
+	if err != nil {
+		t.Fatalf("DownloadToDir failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != downloadPayload {
+		t.Fatalf("Downloaded content mismatch: got %d bytes, want %d", len(data), len(downloadPayload))
+	}
+	return path
+}
+
+func TestDownloaderResumesPartialFile(t *testing.T) {
+	var requests []string
+	srv := newRangeServer(t, http.StatusPartialContent, &requests)
+	defer srv.Close()
+
+	downloadWithPartial(t, srv, downloadPayload[:4000])
+
+	want := "GET bytes=4000-"
+	found := false
+	for _, r := range requests {
+		if r == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected range request %q, got %v", want, requests)
+	}
+}
+
+func TestDownloaderFallsBackOn416(t *testing.T) {
+	var requests []string
+	srv := newRangeServer(t, http.StatusRequestedRangeNotSatisfiable, &requests)
+	defer srv.Close()
+
+	downloadWithPartial(t, srv, downloadPayload[:4000])
+
+	if last := requests[len(requests)-1]; last != "GET " {
+		t.Errorf("Expected a full download after 416, last request was %q", last)
+	}
+}
+
+func TestDownloaderRestartsOnOversizedPartial(t *testing.T) {
+	var requests []string
+	srv := newRangeServer(t, http.StatusPartialContent, &requests)
+	defer srv.Close()
+
+	downloadWithPartial(t, srv, downloadPayload+"garbage")
+
+	for _, r := range requests {
+		if strings.HasPrefix(r, "GET bytes=") {
+			t.Errorf("Oversized partial should not be resumed, got request %q", r)
+		}
+	}
+}
+
+func TestDownloaderStreamingChecksumMismatch(t *testing.T) {
+	var requests []string
+	srv := newRangeServer(t, http.StatusPartialContent, &requests)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	dist := distro.Distro{URL: srv.URL + "/rootfs.appx", SHA256: strings.Repeat("0", 64)}
+
+	d := downloader.New()
+	d.VerifyChecksum = true
+	d.SetProgressOutput(io.Discard)
+	_, err := d.DownloadToDir(dist, dir)
+	if !errors.Is(err, checksum.ErrMismatch) {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "rootfs.appx")); !os.IsNotExist(err) {
+		t.Error("Expected the mismatched download to be removed")
+	}
+
+	// Warn mode keeps the file
+	d.VerifyChecksum = false
+	if _, err := d.DownloadToDir(dist, dir); err != nil {
+		t.Errorf("Expected warn mode to succeed, got %v", err)
+	}
+}
+
+func TestFormatProgress(t *testing.T) {
+	const mb = 1024 * 1024
+
+	line := downloader.FormatProgress(145*mb, 290*mb, 4*mb)
+	if !strings.HasPrefix(line, "Progress: 50.0% (145 MB / 290 MB) @ 4.0 MB/s ETA ") {
+		t.Errorf("Unexpected progress line: %s", line)
+	}
+	if !strings.HasSuffix(line, "ETA 36s") {
+		t.Errorf("Expected ETA of 36s, got: %s", line)
+	}
+
+	line = downloader.FormatProgress(10*mb, -1, 2*mb)
+	if line != "Downloaded: 10 MB @ 2.0 MB/s" {
+		t.Errorf("Unexpected progress line for unknown size: %s", line)
+	}
+
+	line = downloader.FormatProgress(10*mb, 20*mb, 0)
+	if strings.Contains(line, "MB/s") || strings.Contains(line, "ETA") {
+		t.Errorf("Expected no speed before it can be measured, got: %s", line)
+	}
+}
+
+func TestProgressWriterConcurrentWrites(t *testing.T) {
+	var buf bytes.Buffer
+	pw := &downloader.ProgressWriter{Total: -1, Writer: &buf, PrintEveryMB: 1 << 30}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				pw.Write([]byte("0123456789"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if pw.Downloaded != 8000 || buf.Len() != 8000 {
+		t.Errorf("Expected 8000 bytes, got Downloaded=%d written=%d", pw.Downloaded, buf.Len())
+	}
+}
+
+func TestProgressWriterOutput(t *testing.T) {
+	var data, progress bytes.Buffer
+	pw := &downloader.ProgressWriter{Total: 10, Writer: &data}
+	pw.SetOutput(&progress)
+	pw.Write([]byte("0123456789"))
+	if !strings.Contains(progress.String(), "100.0%") {
+		t.Errorf("Expected progress in the output writer, got %q", progress.String())
+	}
+
+	progress.Reset()
+	silent := &downloader.ProgressWriter{Total: 10, Writer: &data, Output: &progress, Silent: true}
+	silent.Write([]byte("0123456789"))
+	if progress.Len() != 0 || silent.Downloaded != 10 {
+		t.Errorf("Expected no progress output when silent, got %q", progress.String())
+	}
+}
+
+func TestDetectPackageType(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		want        downloader.PackageType
+	}{
+		{"rootfs.tar.gz", "", downloader.PackageTar},
+		{"rootfs.tgz", "application/octet-stream", downloader.PackageTar},
+		{"Ubuntu.appxbundle", "", downloader.PackageAppx},
+		{"download", "application/gzip", downloader.PackageTar},
+		{"download", "application/vnd.ms-appx", downloader.PackageAppx},
+		{"rootfs.tar", "application/x-tar; charset=binary", downloader.PackageTar},
+		{"download", "application/octet-stream", downloader.PackageUnknown},
+	}
+	for _, tt := range tests {
+		if got := downloader.DetectPackageType(tt.filename, tt.contentType); got != tt.want {
+			t.Errorf("DetectPackageType(%q, %q) = %q, want %q", tt.filename, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadURLSniffsTarball(t *testing.T) {
+	gzipMagic := "\x1f\x8b\x08\x00rest-of-archive"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte(gzipMagic))
+	}))
+	defer srv.Close()
+
+	d := downloader.New()
+	d.SetProgressOutput(io.Discard)
+	path, kind, err := d.DownloadURL(srv.URL+"/rootfs?sig=abc", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadURL failed: %v", err)
+	}
+	if kind != downloader.PackageTar {
+		t.Errorf("Expected tarball, got %q", kind)
+	}
+	if strings.Contains(path, "?") {
+		t.Errorf("Expected query string stripped from file name, got %s", path)
+	}
+}
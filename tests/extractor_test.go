@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/extractor"
+	"github.com/yuanjua/autowsl/internal/system"
+)
+
+func TestFindTempDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		".autowsl_tmp",
+		filepath.Join("project", ".autowsl_tmp"),
+		filepath.Join("project", "nested", "deeper", ".autowsl_tmp"),
+		filepath.Join("other", "not_tmp"),
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs, err := extractor.FindTempDirs(root, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(dirs) != 1 || dirs[0] != filepath.Join(root, ".autowsl_tmp") {
+		t.Errorf("Expected only the top-level temp dir, got %v", dirs)
+	}
+
+	dirs, err = extractor.FindTempDirs(root, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sort.Strings(dirs)
+	want := []string{
+		filepath.Join(root, ".autowsl_tmp"),
+		filepath.Join(root, "project", ".autowsl_tmp"),
+		filepath.Join(root, "project", "nested", "deeper", ".autowsl_tmp"),
+	}
+	sort.Strings(want)
+	if len(dirs) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, dirs)
+	}
+	for i := range want {
+		if dirs[i] != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], dirs[i])
+		}
+	}
+}
+
+func TestTempDirLock(t *testing.T) {
+	dir := t.TempDir()
+	if extractor.IsLocked(dir) {
+		t.Fatal("Expected fresh directory to be unlocked")
+	}
+
+	release, err := extractor.AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !extractor.IsLocked(dir) {
+		t.Error("Expected directory to be locked")
+	}
+
+	release()
+	if extractor.IsLocked(dir) {
+		t.Error("Expected directory to be unlocked after release")
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.tar"), make([]byte, 1000), 0644)
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "sub", "b.appx"), make([]byte, 24), 0644)
+
+	size, err := extractor.DirSize(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if size != 1024 {
+		t.Errorf("Expected 1024 bytes, got %d", size)
+	}
+}
+
+func TestExtractAppxProgress(t *testing.T) {
+	dir := t.TempDir()
+	rootfs := bytes.Repeat([]byte("rootfs"), 500*1024) // ~3 MB
+
+	appxPath := filepath.Join(dir, "distro.appx")
+	f, err := os.Create(appxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("install.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(rootfs)
+	zw.Close()
+	f.Close()
+
+	var reports [][2]int64
+	tarPath, err := extractor.ExtractAppx(appxPath, filepath.Join(dir, "out"), extractor.ExtractOptions{
+		Progress: func(extracted, total int64) { reports = append(reports, [2]int64{extracted, total}) },
+	})
+	if err != nil {
+		t.Fatalf("ExtractAppx failed: %v", err)
+	}
+	if filepath.Base(tarPath) != "install.tar.gz" {
+		t.Errorf("Unexpected tar path: %s", tarPath)
+	}
+
+	if len(reports) < 3 {
+		t.Fatalf("Expected a report about every MB, got %v", reports)
+	}
+	last := reports[len(reports)-1]
+	if last[0] != int64(len(rootfs)) || last[1] != int64(len(rootfs)) {
+		t.Errorf("Expected the final report to be complete, got %v", last)
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i][0] <= reports[i-1][0] {
+			t.Errorf("Progress went backwards: %v", reports)
+		}
+	}
+}
+
+// writeZip creates a ZIP archive at path with the given file contents
+func writeZip(t *testing.T, path string, files map[string][]byte) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractMsixBundle(t *testing.T) {
+	dir := t.TempDir()
+
+	msixPath := filepath.Join(dir, "Distro.msix")
+	writeZip(t, msixPath, map[string][]byte{"install.tar.gz": []byte("rootfs")})
+	msix, err := os.ReadFile(msixPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(dir, "Distro.msixbundle")
+	writeZip(t, bundlePath, map[string][]byte{
+		"AppxMetadata/AppxBundleManifest.xml": []byte("<Bundle/>"),
+		"Distro.msix":                         msix,
+	})
+
+	tarPath, err := extractor.ExtractAppx(bundlePath, filepath.Join(dir, "out"), extractor.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractAppx failed: %v", err)
+	}
+	if data, _ := os.ReadFile(tarPath); string(data) != "rootfs" {
+		t.Errorf("Unexpected rootfs content: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "Distro.msix")); !os.IsNotExist(err) {
+		t.Error("Expected the nested package to be removed after extraction")
+	}
+}
+
+func TestExtractAppxForceArch(t *testing.T) {
+	dir := t.TempDir()
+
+	nested := map[string][]byte{}
+	for _, arch := range []string{"x64", "ARM64"} {
+		path := filepath.Join(dir, arch+".appx")
+		writeZip(t, path, map[string][]byte{"install.tar.gz": []byte("rootfs-" + arch)})
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nested["Distro_"+arch+".appx"] = data
+	}
+	bundlePath := filepath.Join(dir, "Distro.appxbundle")
+	writeZip(t, bundlePath, nested)
+
+	for _, arch := range []system.HostArchitecture{system.ArchX64, system.ArchARM64} {
+		tarPath, err := extractor.ExtractAppx(bundlePath, filepath.Join(dir, "out-"+string(arch)), extractor.ExtractOptions{ForceArch: arch})
+		if err != nil {
+			t.Fatalf("ExtractAppx(%s) failed: %v", arch, err)
+		}
+		data, _ := os.ReadFile(tarPath)
+		if !strings.EqualFold(string(data), "rootfs-"+string(arch)) {
+			t.Errorf("Expected the %s rootfs, got %q", arch, data)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/history"
+)
+
+func TestHistoryAppendCapsEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	for i := 0; i < 5; i++ {
+		entry := history.Entry{
+			Timestamp: time.Now(),
+			Distro:    "ubuntu",
+			Playbook:  string(rune('a' + i)),
+			Success:   i%2 == 0,
+		}
+		if err := history.AppendTo(path, entry, 3); err != nil {
+			t.Fatalf("AppendTo failed: %v", err)
+		}
+	}
+
+	entries, err := history.LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries after capping, got %d", len(entries))
+	}
+	if entries[0].Playbook != "c" || entries[2].Playbook != "e" {
+		t.Errorf("Expected the oldest entries to be dropped, got %v", entries)
+	}
+}
+
+func TestHistoryLoadMissingFile(t *testing.T) {
+	entries, err := history.LoadFrom(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries and no error, got %v, %v", entries, err)
+	}
+}
+
+func TestHistoryFilter(t *testing.T) {
+	entries := []history.Entry{
+		{Distro: "ubuntu", Playbook: "a", Success: true},
+		{Distro: "debian", Playbook: "b", Success: false},
+		{Distro: "ubuntu", Playbook: "c", Success: false},
+		{Distro: "ubuntu", Playbook: "d", Success: true},
+	}
+
+	if got := history.Filter(entries, "ubuntu", false, 0); len(got) != 3 {
+		t.Errorf("Expected 3 ubuntu entries, got %d", len(got))
+	}
+	if got := history.Filter(entries, "", true, 0); len(got) != 2 {
+		t.Errorf("Expected 2 failed entries, got %d", len(got))
+	}
+	got := history.Filter(entries, "ubuntu", false, 2)
+	if len(got) != 2 || got[0].Playbook != "c" || got[1].Playbook != "d" {
+		t.Errorf("Expected the last 2 ubuntu entries, got %v", got)
+	}
+}
+
+func TestHistoryPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Now()
+	for i, age := range []time.Duration{60 * 24 * time.Hour, 40 * 24 * time.Hour, 10 * 24 * time.Hour, time.Hour, 0} {
+		entry := history.Entry{Timestamp: now.Add(-age), Distro: "ubuntu", Playbook: string(rune('a' + i))}
+		if err := history.AppendTo(path, entry, 100); err != nil {
+			t.Fatalf("AppendTo failed: %v", err)
+		}
+	}
+
+	removed, err := history.PruneFile(path, 0, now.Add(-30*24*time.Hour))
+	if err != nil || removed != 2 {
+		t.Fatalf("PruneFile(before 30d) = %d, %v; want 2 removed", removed, err)
+	}
+	removed, err = history.PruneFile(path, 2, time.Time{})
+	if err != nil || removed != 1 {
+		t.Fatalf("PruneFile(keep 2) = %d, %v; want 1 removed", removed, err)
+	}
+
+	entries, err := history.LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Playbook != "d" || entries[1].Playbook != "e" {
+		t.Errorf("Expected the two newest entries, got %v", entries)
+	}
+
+	if removed, err := history.PruneFile(filepath.Join(t.TempDir(), "missing.json"), 1, time.Time{}); err != nil || removed != 0 {
+		t.Errorf("PruneFile on a missing file = %d, %v", removed, err)
+	}
+}
+
+func TestHistoryParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"-2d", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := history.ParseAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAge(%q) = %s, %v", tt.in, got, err)
+		}
+	}
+}
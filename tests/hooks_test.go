@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/hooks"
+)
+
+func TestHooksRunWithStopsAtFirstFailure(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Errors["powershell.exe -NoProfile -NonInteractive -Command exit 1"] = errors.New("exit status 1")
+
+	err := hooks.RunWith(mock, []string{"Write-Output one", "exit 1", "Write-Output three"})
+	if err == nil {
+		t.Fatal("Expected error from failing hook")
+	}
+	if len(mock.Calls) != 2 {
+		t.Errorf("Expected 2 hook calls before stopping, got %v", mock.Calls)
+	}
+	if mock.Calls[0] != "powershell.exe -NoProfile -NonInteractive -Command Write-Output one" {
+		t.Errorf("Unexpected hook command: %s", mock.Calls[0])
+	}
+}
+
+func TestHooksEnvAndMerge(t *testing.T) {
+	vars := hooks.Env{Distro: "Ubuntu", Playbook: "curl,git", Status: hooks.StatusSuccess}.Vars()
+	want := []string{"AUTOWSL_DISTRO=Ubuntu", "AUTOWSL_PLAYBOOK=curl,git", "AUTOWSL_STATUS=success"}
+	for i := range want {
+		if vars[i] != want[i] {
+			t.Errorf("Vars()[%d] = %q, want %q", i, vars[i], want[i])
+		}
+	}
+
+	merged := hooks.Hooks{PreProvision: []string{"a"}}.Merge(hooks.Hooks{PreProvision: []string{"b"}, PostProvision: []string{"c"}})
+	if len(merged.PreProvision) != 2 || merged.PreProvision[1] != "b" || len(merged.PostProvision) != 1 {
+		t.Errorf("Unexpected merged hooks: %+v", merged)
+	}
+}
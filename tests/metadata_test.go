@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/metadata"
+)
+
+func TestMetadataRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	want := metadata.Metadata{
+		InstalledAt:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		PackageID:     "Canonical.Ubuntu.2204",
+		DistroGroup:   "Ubuntu",
+		DistroVersion: "Ubuntu 22.04 LTS",
+		WSLVersion:    2,
+	}
+	if err := metadata.Write(dir, want); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	got, err := metadata.Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !got.InstalledAt.Equal(want.InstalledAt) || got.PackageID != want.PackageID || got.WSLVersion != 2 {
+		t.Errorf("Read returned %+v, want %+v", got, want)
+	}
+}
+
+func TestMetadataAddPlaybooks(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := metadata.Read(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected os.ErrNotExist for missing metadata, got %v", err)
+	}
+
+	if err := metadata.AddPlaybooks(dir, []string{"curl.yml"}, []string{"base"}); err != nil {
+		t.Fatalf("AddPlaybooks failed: %v", err)
+	}
+	if err := metadata.AddPlaybooks(dir, []string{"curl.yml", "dev.yml"}, nil); err != nil {
+		t.Fatalf("AddPlaybooks failed: %v", err)
+	}
+
+	m, err := metadata.Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(m.PlaybooksRun) != 2 || m.PlaybooksRun[1] != "dev.yml" {
+		t.Errorf("Expected playbooks without duplicates, got %v", m.PlaybooksRun)
+	}
+	if len(m.Tags) != 1 || m.Tags[0] != "base" {
+		t.Errorf("Expected tags [base], got %v", m.Tags)
+	}
+}
+
+func TestMetadataRecordStart(t *testing.T) {
+	dir := t.TempDir()
+	if err := metadata.Write(dir, metadata.Metadata{Source: "catalog"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if err := metadata.RecordStart(dir); err != nil {
+		t.Fatalf("RecordStart failed: %v", err)
+	}
+
+	m, err := metadata.Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if m.LastStartedAt.IsZero() {
+		t.Error("Expected LastStartedAt to be set")
+	}
+	if m.Source != "catalog" {
+		t.Errorf("Expected existing fields to be kept, got %+v", m)
+	}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/playbooks"
+)
+
+func TestParseExtraVars(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "users.json")
+	if err := os.WriteFile(jsonFile, []byte("{\"users\": [\"alice\", \"bob\"]}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	badFile := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badFile, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	users := map[string]any{"users": []any{"alice", "bob"}}
+
+	tests := []struct {
+		name    string
+		entry   string
+		key     string
+		want    any
+		wantErr bool
+	}{
+		{"plain", "env=prod", "env", "prod", false},
+		{"embedded equals", "opts=a=1,b=2", "opts", "a=1,b=2", false},
+		{"spaces trimmed", " env = prod ", "env", "prod", false},
+		{"double quotes", `greeting="hello world"`, "greeting", "hello world", false},
+		{"single quotes", "greeting='another value'", "greeting", "another value", false},
+		{"quoted equals", `query="a=b"`, "query", "a=b", false},
+		{"mismatched quotes kept", `name="bob'`, "name", `"bob'`, false},
+		{"lone quote kept", `name="`, "name", `"`, false},
+		{"empty value", "name=", "name", "", false},
+		{"file reference", "data=@" + jsonFile, "data", users, false},
+		{"quoted file reference", "data='@" + jsonFile + "'", "data", users, false},
+		{"missing file", "data=@" + filepath.Join(dir, "missing.json"), "", "", true},
+		{"invalid JSON file", "data=@" + badFile, "", "", true},
+		{"empty file name", "data=@", "", "", true},
+		{"no equals", "novalue", "", "", true},
+		{"empty key", "=value", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := playbooks.ParseExtraVars([]string{tt.entry})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error for %q, got %v", tt.entry, vars)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExtraVars(%q) failed: %v", tt.entry, err)
+			}
+			if got, ok := vars[tt.key]; !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExtraVars(%q)[%q] = %#v, want %#v", tt.entry, tt.key, got, tt.want)
+			}
+		})
+	}
+}
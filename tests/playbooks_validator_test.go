@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/playbooks"
+)
+
+func TestValidatePlaybook(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", "- hosts: all\n  tasks:\n    - debug: msg=hi\n", false},
+		{"no tasks", "- hosts: localhost\n  roles: [common]\n", false},
+		{"import_playbook", "- import_playbook: site.yml\n- hosts: all\n", false},
+		{"mapping at top level", "hosts: all\ntasks: []\n", true},
+		{"missing hosts", "- name: setup\n  tasks: []\n", true},
+		{"tasks not a list", "- hosts: all\n  tasks:\n    debug: msg=hi\n", true},
+		{"invalid yaml", "- hosts: all\n  tasks: [\n", true},
+		{"empty", "", true},
+		{"html page", "<html><body>Not Found</body></html>\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "site.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			err := playbooks.ValidatePlaybook(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePlaybook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolverRejectsInvalidDownloads(t *testing.T) {
+	srv, _ := newFlakyServer(0, http.StatusOK, "<html>login</html>\n")
+	defer srv.Close()
+
+	tempDir := t.TempDir()
+	r := playbooks.NewResolver(tempDir, t.TempDir())
+	r.RetryConfig = fastRetry
+
+	if _, err := r.Resolve(srv.URL + "/site.yml"); err == nil {
+		t.Fatal("Expected an error for a download that is not a playbook")
+	}
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 0 {
+		t.Errorf("Expected the invalid download not to be kept, found %d file(s)", len(entries))
+	}
+}
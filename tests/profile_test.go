@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/profile"
+)
+
+func TestProfileSaveLoadListDelete(t *testing.T) {
+	dir := t.TempDir()
+
+	frontend := profile.Profile{
+		Name:                 "frontend",
+		DefaultDistroVersion: "Ubuntu 22.04 LTS",
+		Playbooks:            []string{"node", "git"},
+		ExtraVars:            map[string]string{"user": "dev"},
+		WSLVersion:           2,
+	}
+	if err := profile.SaveTo(dir, frontend); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	if err := profile.SaveTo(dir, profile.Profile{Name: "backend", KeepTar: true}); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	got, err := profile.LoadFrom(dir, "frontend")
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if got.DefaultDistroVersion != "Ubuntu 22.04 LTS" || len(got.Playbooks) != 2 || got.ExtraVars["user"] != "dev" || got.WSLVersion != 2 {
+		t.Errorf("Unexpected profile: %+v", got)
+	}
+
+	profiles, err := profile.ListFrom(dir)
+	if err != nil {
+		t.Fatalf("ListFrom failed: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "backend" || !profiles[0].KeepTar {
+		t.Errorf("Unexpected profiles: %+v", profiles)
+	}
+
+	if err := profile.DeleteFrom(dir, "frontend"); err != nil {
+		t.Fatalf("DeleteFrom failed: %v", err)
+	}
+	if _, err := profile.LoadFrom(dir, "frontend"); !errors.Is(err, profile.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestProfileValidateName(t *testing.T) {
+	for _, name := range []string{"", "../evil", "with space"} {
+		if err := profile.ValidateName(name); err == nil {
+			t.Errorf("Expected error for profile name %q", name)
+		}
+	}
+	if err := profile.ValidateName("go-1.22_backend"); err != nil {
+		t.Errorf("Expected valid name, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+	"github.com/yuanjua/autowsl/internal/ui"
+)
+
+// tarBytes returns a tar archive holding a single file
+func tarBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("root:x:0:0:root:/root:/bin/bash\n")
+	if err := tw.WriteHeader(&tar.Header{Name: "etc/passwd", Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// gzipBytes compresses data with gzip
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeBackup writes a backup file into a temporary directory
+func writeBackup(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// importCall returns the wsl --import call for name, if any
+func importCall(calls []string, name string) string {
+	for _, c := range calls {
+		if strings.HasPrefix(c, "wsl.exe --import "+name+" ") {
+			return c
+		}
+	}
+	return ""
+}
+
+func TestRestoreVerifiesBackup(t *testing.T) {
+	isolateHome(t)
+
+	tarData := tarBytes(t)
+	tests := []struct {
+		name    string
+		file    string
+		data    []byte
+		wantErr string
+	}{
+		{"plain tar", "dev-backup.tar", tarData, ""},
+		{"gzip tar", "dev-backup.tar.gz", gzipBytes(t, tarData), ""},
+		{"xz magic", "dev-backup.tar.xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x01, 0x02}, ""},
+		{"empty", "dev-backup.tar", nil, "empty or truncated"},
+		{"truncated", "dev-backup.tar", []byte{0x1f, 0x8b, 0x08}, "empty or truncated"},
+		{"gzip without tar", "dev-backup.tar.gz", gzipBytes(t, bytes.Repeat([]byte("not a tar "), 100)), "not a valid tar archive"},
+		{"bad gzip header", "dev-backup.tar.gz", []byte{0x1f, 0x8b, 0x00, 0x00, 0x00, 0x00, 0x00}, "not a valid gzip archive"},
+		{"not a tar", "dev-backup.tar", bytes.Repeat([]byte("x"), 1024), "not a valid tar archive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockRunner()
+			path := writeBackup(t, tt.file, tt.data)
+
+			out, err := runAutowsl(t, mock, "restore", path, "--path", t.TempDir())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("restore failed: %v\n%s", err, out)
+				}
+				if importCall(mock.Calls, "dev") == "" {
+					t.Errorf("Expected an import of 'dev', got calls: %v", mock.Calls)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if len(mock.Calls) > 0 {
+				t.Errorf("Expected no WSL calls for an unreadable backup, got %v", mock.Calls)
+			}
+		})
+	}
+
+	if _, err := runAutowsl(t, NewMockRunner(), "restore", filepath.Join(t.TempDir(), "missing.tar")); err == nil ||
+		!strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected a missing-file error, got %v", err)
+	}
+}
+
+func TestRestoreBackupBaseName(t *testing.T) {
+	isolateHome(t)
+
+	tarData := tarBytes(t)
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"ubuntu-2204-lts-backup.tar", "ubuntu-2204-lts"},
+		{"dev-box-backup.tar.gz", "dev-box"},
+		{"Ubuntu-22.04-backup.tar", "ubuntu-2204"},
+		{"plain.tar", "plain"},
+		{"nightly.tar.gz", "nightly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data := tarData
+			if strings.HasSuffix(tt.file, ".gz") {
+				data = gzipBytes(t, tarData)
+			}
+			mock := NewMockRunner()
+			path := writeBackup(t, tt.file, data)
+
+			out, err := runAutowsl(t, mock, "restore", path, "--path", t.TempDir())
+			if err != nil {
+				t.Fatalf("restore failed: %v\n%s", err, out)
+			}
+			if importCall(mock.Calls, tt.want) == "" {
+				t.Errorf("Expected the backup to be restored as %q, got calls: %v", tt.want, mock.Calls)
+			}
+		})
+	}
+}
+
+// scriptedPrompter answers prompts in order and records their defaults
+type scriptedPrompter struct {
+	answers  []string
+	defaults []string
+}
+
+func (p *scriptedPrompter) Prompt(prompt promptui.Prompt) (string, error) {
+	p.defaults = append(p.defaults, fmt.Sprint(prompt.Default))
+	if len(p.answers) == 0 {
+		return "", promptui.ErrInterrupt
+	}
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer, nil
+}
+
+func (p *scriptedPrompter) Select(promptui.Select) (int, string, error) {
+	return -1, "", promptui.ErrInterrupt
+}
+
+func TestRestorePromptsWhileNameExists(t *testing.T) {
+	isolateHome(t)
+	path := writeBackup(t, "dev-backup.tar", tarBytes(t))
+	installed := "  NAME       STATE      VERSION\n* dev        Stopped    2\n  dev-old    Stopped    2\n"
+
+	// Every taken name is prompted for again until a free one is given
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = installed
+	prompter := &scriptedPrompter{answers: []string{"dev-old", "dev-new"}}
+	ui.SetPrompter(prompter)
+	out, err := runAutowsl(t, mock, "restore", path, "--path", t.TempDir())
+	if err != nil {
+		t.Fatalf("restore failed: %v\n%s", err, out)
+	}
+	if importCall(mock.Calls, "dev-new") == "" {
+		t.Errorf("Expected an import of 'dev-new', got calls: %v", mock.Calls)
+	}
+	if want := []string{"dev-restored", "dev-old-restored"}; strings.Join(prompter.defaults, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected prompt defaults %v, got %v", want, prompter.defaults)
+	}
+	if !strings.Contains(out, "Distribution 'dev' already exists") || !strings.Contains(out, "Distribution 'dev-old' already exists") {
+		t.Errorf("Expected both taken names to be reported:\n%s", out)
+	}
+
+	// A cancelled prompt reports the taken name and imports nothing
+	mock = NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = installed
+	ui.SetPrompter(&scriptedPrompter{})
+	if _, err := runAutowsl(t, mock, "restore", path); err == nil || !strings.Contains(err.Error(), "'dev' already exists") {
+		t.Errorf("Expected an already-exists error, got %v", err)
+	}
+	if call := importCall(mock.Calls, "dev"); call != "" {
+		t.Errorf("Expected no import, got %q", call)
+	}
+
+	// Non-interactive runs take the suggested name
+	mock = NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = installed
+	if _, err := runAutowsl(t, mock, "restore", path, "--path", t.TempDir(), "--yes"); err != nil {
+		t.Fatalf("restore --yes failed: %v", err)
+	}
+	if importCall(mock.Calls, "dev-restored") == "" {
+		t.Errorf("Expected an import of 'dev-restored', got calls: %v", mock.Calls)
+	}
+}
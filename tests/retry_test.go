@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/distro"
+	"github.com/yuanjua/autowsl/internal/downloader"
+	"github.com/yuanjua/autowsl/internal/playbooks"
+)
+
+var fastRetry = downloader.RetryConfig{
+	MaxAttempts:  3,
+	InitialDelay: time.Millisecond,
+	MaxDelay:     5 * time.Millisecond,
+}
+
+// newFlakyServer fails the first `failures` requests with the given status
+func newFlakyServer(failures int, status int, body string) (*httptest.Server, *int) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	return srv, &calls
+}
+
+func TestDownloaderRetriesServerErrors(t *testing.T) {
+	srv, calls := newFlakyServer(2, http.StatusServiceUnavailable, "rootfs")
+	defer srv.Close()
+
+	d := downloader.New()
+	d.RetryConfig = fastRetry
+	d.SetProgressOutput(io.Discard)
+
+	path, err := d.DownloadToDir(distro.Distro{URL: srv.URL + "/rootfs.appx"}, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected download to succeed after retries, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 requests, got %d", *calls)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "rootfs" {
+		t.Errorf("Unexpected content: %q", data)
+	}
+}
+
+func TestResolverRetriesServerErrors(t *testing.T) {
+	srv, calls := newFlakyServer(2, http.StatusBadGateway, "- hosts: all\n")
+	defer srv.Close()
+
+	r := playbooks.NewResolver(t.TempDir(), t.TempDir())
+	r.RetryConfig = fastRetry
+
+	paths, err := r.Resolve(srv.URL + "/site.yml")
+	if err != nil {
+		t.Fatalf("Expected resolve to succeed after retries, got %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("Expected 1 path, got %d", len(paths))
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 requests, got %d", *calls)
+	}
+}
+
+func TestResolverDoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := newFlakyServer(10, http.StatusNotFound, "")
+	defer srv.Close()
+
+	r := playbooks.NewResolver(t.TempDir(), t.TempDir())
+	r.RetryConfig = fastRetry
+
+	if _, err := r.Resolve(srv.URL + "/missing.yml"); err == nil {
+		t.Fatal("Expected error for 404, got nil")
+	}
+	if *calls != 1 {
+		t.Errorf("Expected a single request for 404, got %d", *calls)
+	}
+}
+
+func TestResolverCachesDownloads(t *testing.T) {
+	srv, calls := newFlakyServer(0, http.StatusOK, "- hosts: all\n")
+	defer srv.Close()
+
+	r := playbooks.NewResolver(t.TempDir(), t.TempDir())
+	r.RetryConfig = fastRetry
+
+	paths, err := r.ResolveMultiple([]string{srv.URL + "/site.yml", srv.URL + "/site.yml"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(paths) != 1 || *calls != 1 {
+		t.Errorf("Expected 1 path from 1 request, got %d paths from %d requests", len(paths), *calls)
+	}
+
+	// A different URL with the same long prefix must not reuse the cache
+	if _, err := r.Resolve(srv.URL + "/site.yml?ref=main"); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected a second request for a different URL, got %d", *calls)
+	}
+
+	r.ForceRefresh = true
+	if _, err := r.Resolve(srv.URL + "/site.yml"); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected ForceRefresh to download again, got %d requests", *calls)
+	}
+}
+
+func TestResolverCacheExpires(t *testing.T) {
+	srv, calls := newFlakyServer(0, http.StatusOK, "- hosts: all\n")
+	defer srv.Close()
+
+	r := playbooks.NewResolver(t.TempDir(), t.TempDir())
+	r.RetryConfig = fastRetry
+	r.CacheMaxAge = time.Minute
+
+	paths, err := r.Resolve(srv.URL + "/site.yml")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(paths[0], old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Resolve(srv.URL + "/site.yml"); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected a stale cache entry to be downloaded again, got %d requests", *calls)
+	}
+}
@@ -1,6 +1,10 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -58,6 +62,25 @@ func TestExecRunnerTimeoutExceeded(t *testing.T) {
 	}
 }
 
+func TestExecRunnerTimeoutCancels(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on Windows")
+	}
+
+	r := runner.NewExecRunner(100 * time.Millisecond)
+	start := time.Now()
+	_, _, err := r.Run("sleep", "5")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("Expected the timeout in the error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the command to be killed at the timeout, took %s", elapsed)
+	}
+}
+
 func TestExecRunnerWithInput(t *testing.T) {
 	// Create runner with no timeout
 	r := runner.NewExecRunner(0)
@@ -100,6 +123,37 @@ func TestExecRunnerDryRun(t *testing.T) {
 	}
 }
 
+func TestExecRunnerWSLPath(t *testing.T) {
+	r := runner.NewExecRunner(0)
+	if r.WSLPath != runner.DefaultWSLPath {
+		t.Errorf("Expected default WSLPath %q, got %q", runner.DefaultWSLPath, r.WSLPath)
+	}
+
+	// wsl.exe is replaced by the configured binary; other commands are not
+	r.WSLPath = "echo"
+	stdout, stderr, err := r.Run("wsl.exe", "-l", "-v")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v\nStderr: %s", err, stderr)
+	}
+	if strings.TrimSpace(stdout) != "-l -v" {
+		t.Errorf("Expected the alternative binary to run, got: %q", stdout)
+	}
+
+	r.DryRun = true
+	stdout, _, _ = r.Run("wsl.exe", "--shutdown")
+	if !strings.Contains(stdout, "echo --shutdown") {
+		t.Errorf("Expected dry-run log with the alternative binary, got: %s", stdout)
+	}
+}
+
+func TestFormatCommand(t *testing.T) {
+	got := runner.FormatCommand("wsl.exe", "-d", "Ubuntu", "sh", "-c", "echo it's here")
+	want := `wsl.exe -d Ubuntu sh -c 'echo it'\''s here'`
+	if got != want {
+		t.Errorf("FormatCommand() = %s, want %s", got, want)
+	}
+}
+
 func TestExecRunnerInvalidCommand(t *testing.T) {
 	r := runner.NewExecRunner(0)
 
@@ -109,3 +163,59 @@ func TestExecRunnerInvalidCommand(t *testing.T) {
 		t.Error("Expected error for invalid command, got nil")
 	}
 }
+
+func TestRecordAndReplay(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe --list --verbose"] = "  NAME      STATE           VERSION\n* Ubuntu    Running         2\n"
+	mock.Errors["wsl.exe --terminate Missing"] = errors.New("exit status 1")
+	mock.Stderr["wsl.exe --terminate Missing"] = "no such distribution"
+
+	rec := runner.NewRecordingRunner(mock)
+	rec.Run("wsl.exe", "--list", "--verbose")
+	rec.Run("wsl.exe", "--terminate", "Missing")
+	rec.RunWithInput("wsl.exe", "echo hi", "-d", "Ubuntu", "--", "sh")
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if err := rec.SaveRecording(path); err != nil {
+		t.Fatalf("SaveRecording failed: %v", err)
+	}
+
+	replay, err := runner.LoadRecording(path)
+	if err != nil {
+		t.Fatalf("LoadRecording failed: %v", err)
+	}
+
+	stdout, _, err := replay.Run("wsl.exe", "--list", "--verbose")
+	if err != nil || !strings.Contains(stdout, "Ubuntu") {
+		t.Errorf("Unexpected replay of --list: %q, %v", stdout, err)
+	}
+	_, stderr, err := replay.Run("wsl.exe", "--terminate", "Missing")
+	if err == nil || err.Error() != "exit status 1" || stderr != "no such distribution" {
+		t.Errorf("Expected recorded failure, got %q, %v", stderr, err)
+	}
+	if _, _, err := replay.RunWithInput("wsl.exe", "echo hi", "-d", "Ubuntu", "--", "sh"); err != nil {
+		t.Errorf("Expected recorded stdin command to replay, got %v", err)
+	}
+	if _, _, err := replay.RunWithInput("wsl.exe", "echo bye", "-d", "Ubuntu", "--", "sh"); err == nil {
+		t.Error("Expected an error for a command run with different stdin")
+	}
+	if _, _, err := replay.Run("wsl.exe", "--shutdown"); err == nil {
+		t.Error("Expected an error for a command that was not recorded")
+	}
+}
+
+func TestReplayRunnerOrder(t *testing.T) {
+	replay := runner.NewReplayRunner([]runner.Invocation{
+		{Name: "wsl.exe", Args: []string{"--status"}, Stdout: "first"},
+		{Name: "wsl.exe", Args: []string{"--status"}, Stdout: "second"},
+	})
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		stdout, _, _ := replay.Run("wsl.exe", "--status")
+		got = append(got, stdout)
+	}
+	if strings.Join(got, ",") != "first,second,second" {
+		t.Errorf("Expected results in recorded order, then the last repeated; got %v", got)
+	}
+}
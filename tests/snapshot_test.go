@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/snapshot"
+)
+
+func TestSnapshotSaveListPrune(t *testing.T) {
+	root := t.TempDir()
+	base := time.Date(2024, 1, 31, 15, 45, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		at := base.Add(time.Duration(i) * time.Hour)
+		s := snapshot.Snapshot{Name: snapshot.NewName("Ubuntu", at), Distro: "Ubuntu", CreatedAt: at, Size: 1024}
+		if err := snapshot.SaveTo(root, s); err != nil {
+			t.Fatalf("SaveTo() error = %v", err)
+		}
+		if err := os.WriteFile(snapshot.TarPath(root, "Ubuntu", s.Name), []byte("tar"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := snapshot.ListFrom(root, "Ubuntu")
+	if err != nil {
+		t.Fatalf("ListFrom() error = %v", err)
+	}
+	if len(snapshots) != 3 || snapshots[0].Name != "Ubuntu-20240131-174500" {
+		t.Fatalf("Expected 3 snapshots newest first, got %+v", snapshots)
+	}
+
+	deleted, err := snapshot.PruneFrom(root, "Ubuntu", 1)
+	if err != nil {
+		t.Fatalf("PruneFrom() error = %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 deleted snapshots, got %d", len(deleted))
+	}
+	if _, err := os.Stat(snapshot.TarPath(root, "Ubuntu", "Ubuntu-20240131-154500")); !os.IsNotExist(err) {
+		t.Error("Expected the tar of a pruned snapshot to be removed")
+	}
+
+	if _, err := snapshot.LoadFrom(root, "Ubuntu", "Ubuntu-20240131-154500"); !errors.Is(err, snapshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := snapshot.DeleteFrom(root, "Ubuntu", "Ubuntu-20240131-174500"); err != nil {
+		t.Errorf("DeleteFrom() error = %v", err)
+	}
+	if snapshots, _ := snapshot.ListFrom(root, "Ubuntu"); len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots left, got %d", len(snapshots))
+	}
+}
+
+func TestSnapshotRestoreName(t *testing.T) {
+	if got := snapshot.RestoreName("Ubuntu", []string{"Ubuntu", "ubuntu-1", "Debian"}); got != "Ubuntu-2" {
+		t.Errorf("RestoreName() = %s, want Ubuntu-2", got)
+	}
+	if err := snapshot.ValidateName("../etc"); err == nil {
+		t.Error("Expected error for a path-like snapshot name")
+	}
+}
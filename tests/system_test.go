@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/system"
+)
+
+func TestFeatureSupported(t *testing.T) {
+	tests := []struct {
+		feature string
+		build   int
+		ok      bool
+	}{
+		{system.FeatureWSL2, 19041, true},
+		{system.FeatureWSL2, 18363, false},
+		{system.FeatureSparseVHD, 22631, true},
+		{system.FeatureSparseVHD, 22000, false},
+		{system.FeatureMirroredNetworking, 22621, true},
+		{system.FeatureMirroredNetworking, 19045, false},
+		{system.FeatureVHDExport, 22000, true},
+		{system.FeatureVHDExport, 19045, false},
+		{"hologram", 99999, false},
+	}
+	for _, tt := range tests {
+		err := system.FeatureSupported(tt.feature, tt.build)
+		if (err == nil) != tt.ok {
+			t.Errorf("FeatureSupported(%q, %d) = %v, want ok=%v", tt.feature, tt.build, err, tt.ok)
+		}
+	}
+
+	err := system.FeatureSupported(system.FeatureMirroredNetworking, 19045)
+	if err == nil || !strings.Contains(err.Error(), "22621") || !strings.Contains(err.Error(), "19045") {
+		t.Errorf("Expected the required and current build in the error, got %v", err)
+	}
+	if system.MinimumBuild(system.FeatureSparseVHD) != 22557 {
+		t.Errorf("MinimumBuild(%q) = %d", system.FeatureSparseVHD, system.MinimumBuild(system.FeatureSparseVHD))
+	}
+}
+
+func TestParseArchitecture(t *testing.T) {
+	if arch, err := system.ParseArchitecture("ARM64"); err != nil || arch != system.ArchARM64 {
+		t.Errorf("ParseArchitecture(ARM64) = %q, %v", arch, err)
+	}
+	if arch, err := system.ParseArchitecture("x64"); err != nil || arch != system.ArchX64 {
+		t.Errorf("ParseArchitecture(x64) = %q, %v", arch, err)
+	}
+	if _, err := system.ParseArchitecture("x86"); err == nil {
+		t.Error("Expected x86 to be rejected")
+	}
+
+	if !system.ShouldSkipArchitectureFor(system.ArchX64, "Ubuntu_2204_ARM64.appx") {
+		t.Error("Expected the ARM64 package to be skipped for x64")
+	}
+	if system.ShouldSkipArchitectureFor(system.ArchARM64, "Ubuntu_2204_ARM64.appx") {
+		t.Error("Expected the ARM64 package to be kept for arm64")
+	}
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+	"github.com/yuanjua/autowsl/internal/ui"
+)
+
+func TestNonInteractivePrompter(t *testing.T) {
+	p := ui.NonInteractivePrompter{}
+
+	if _, err := p.Prompt(promptui.Prompt{Label: "Remove it", IsConfirm: true}); err != nil {
+		t.Errorf("Expected confirmation to be accepted, got %v", err)
+	}
+
+	got, err := p.Prompt(promptui.Prompt{Label: "Distribution name", Default: "ubuntu-2204"})
+	if err != nil || got != "ubuntu-2204" {
+		t.Errorf("Expected default value, got %q, %v", got, err)
+	}
+
+	if _, err := p.Prompt(promptui.Prompt{Label: "New distribution name"}); !errors.Is(err, ui.ErrNoDefault) {
+		t.Errorf("Expected ErrNoDefault for prompt without default, got %v", err)
+	}
+
+	if _, _, err := p.Select(promptui.Select{Label: "Select distribution"}); !errors.Is(err, ui.ErrNoDefault) {
+		t.Errorf("Expected ErrNoDefault for selection, got %v", err)
+	}
+}
+
+// answerPrompter answers every prompt with a fixed string
+type answerPrompter struct{ answer string }
+
+func (p answerPrompter) Prompt(promptui.Prompt) (string, error) { return p.answer, nil }
+
+func (p answerPrompter) Select(promptui.Select) (int, string, error) { return 0, "", nil }
+
+func TestConfirmWord(t *testing.T) {
+	defer ui.SetPrompter(ui.InteractivePrompter{})
+
+	tests := []struct {
+		answer string
+		want   bool
+	}{
+		{"yes", true},
+		{" YES ", true},
+		{"y", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		ui.SetPrompter(answerPrompter{tt.answer})
+		if got := ui.ConfirmWord("Remove 2 distribution(s)", "yes"); got != tt.want {
+			t.Errorf("ConfirmWord with answer %q = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+
+	ui.SetPrompter(ui.NonInteractivePrompter{})
+	if !ui.ConfirmWord("Remove 2 distribution(s)", "yes") {
+		t.Error("Expected non-interactive mode to confirm")
+	}
+}
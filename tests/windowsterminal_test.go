@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/windowsterminal"
+)
+
+func TestGenerateProfile(t *testing.T) {
+	p, err := windowsterminal.GenerateProfile("ubuntu-2204-lts")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if p.Commandline != "wsl.exe -d ubuntu-2204-lts" {
+		t.Errorf("Unexpected commandline: %s", p.Commandline)
+	}
+	if len(p.GUID) != 38 || p.GUID[0] != '{' || p.GUID[37] != '}' {
+		t.Errorf("Expected braced GUID, got %s", p.GUID)
+	}
+
+	again, _ := windowsterminal.GenerateProfile("ubuntu-2204-lts")
+	if again.GUID != p.GUID {
+		t.Errorf("Expected stable GUID, got %s and %s", p.GUID, again.GUID)
+	}
+
+	other, _ := windowsterminal.GenerateProfile("debian")
+	if other.GUID == p.GUID {
+		t.Error("Expected different names to get different GUIDs")
+	}
+
+	if _, err := windowsterminal.GenerateProfile(""); err == nil {
+		t.Error("Expected error for empty name")
+	}
+}
+
+func TestAppendProfile(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+	initial := `{"defaultProfile": "{00000000-0000-0000-0000-000000000000}", "profiles": {"defaults": {}, "list": [{"guid": "{00000000-0000-0000-0000-000000000000}", "name": "PowerShell"}]}}`
+	if err := os.WriteFile(settingsPath, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := windowsterminal.GenerateProfile("dev-box")
+	if err := windowsterminal.AppendProfile(settingsPath, p); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Appending the same profile again must not duplicate it
+	if err := windowsterminal.AppendProfile(settingsPath, p); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, _ := os.ReadFile(settingsPath)
+	var settings struct {
+		DefaultProfile string `json:"defaultProfile"`
+		Profiles       struct {
+			List []windowsterminal.Profile `json:"list"`
+		} `json:"profiles"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("Failed to parse written settings: %v", err)
+	}
+
+	if settings.DefaultProfile == "" {
+		t.Error("Expected existing settings to be preserved")
+	}
+	if len(settings.Profiles.List) != 2 {
+		t.Fatalf("Expected 2 profiles, got %d", len(settings.Profiles.List))
+	}
+	if settings.Profiles.List[1].Name != "dev-box" {
+		t.Errorf("Expected new profile to be appended, got %+v", settings.Profiles.List[1])
+	}
+
+	if _, err := os.Stat(settingsPath + ".bak"); err != nil {
+		t.Errorf("Expected backup file, got %v", err)
+	}
+}
@@ -2,8 +2,14 @@ package tests
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/yuanjua/autowsl/internal/distro"
+
 	"github.com/yuanjua/autowsl/internal/winget"
 )
 
@@ -137,3 +143,79 @@ func ExampleDownloadOptions_custom() {
 
 	fmt.Printf("Downloaded to: %s\n", filePath)
 }
+
+func TestManagerForceDirectDownload(t *testing.T) {
+	srv, calls := newFlakyServer(0, http.StatusOK, "rootfs")
+	defer srv.Close()
+
+	dir := t.TempDir()
+	mgr := winget.NewManager(dir)
+	mgr.SetProgressOutput(io.Discard)
+	path, err := mgr.Download(winget.DownloadOptions{
+		PackageID:   "Canonical.Ubuntu.2204",
+		Distro:      distro.Distro{Version: "Ubuntu 22.04 LTS", URL: srv.URL + "/ubuntu.tar.gz"},
+		ForceDirect: true,
+	})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if *calls == 0 || filepath.Dir(path) != dir {
+		t.Errorf("Expected a direct download into %s, got %s after %d requests", dir, path, *calls)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "rootfs" {
+		t.Errorf("Unexpected content: %q", data)
+	}
+
+	if _, err := mgr.Download(winget.DownloadOptions{Distro: distro.Distro{Version: "Custom"}, ForceDirect: true}); err == nil {
+		t.Error("Expected error for a distribution without a direct URL")
+	}
+}
+
+func TestManagerListInstalledWingetDistros(t *testing.T) {
+	defer distro.ResetCatalog()
+	path := writeCatalog(t, `{"distributions": [
+		{"group": "Ubuntu", "version": "Ubuntu 22.04 LTS", "architecture": "x64", "packageId": "Canonical.Ubuntu.2204"},
+		{"group": "Debian", "version": "Debian GNU/Linux", "architecture": "x64", "packageId": "9MSVKQC78PK6"}
+	]}`)
+	if err := distro.LoadCatalog(path, false); err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	mock := NewMockRunner()
+	mock.Outputs["winget list --accept-source-agreements"] = "\r   - \r   \\ \r" +
+		"Name                 Id                          Version      Available  Source\r\n" +
+		"-------------------------------------------------------------------------------\r\n" +
+		"Ubuntu 22.04.5 LTS   Canonical.Ubuntu.2204       2204.5.10.0             winget\r\n" +
+		"Debian               9MSVKQC78PK6                1.20.2.0                msstore\r\n" +
+		"Visual Studio Code   Microsoft.VisualStudioCode  1.95.3       1.96.0     winget\r\n" +
+		"Kali Linux           KaliLinux.KaliLinux         2024.4.0                winget\r\n"
+
+	mgr := winget.NewManager(t.TempDir())
+	mgr.SetRunner(mock)
+	distros, err := mgr.ListInstalledWingetDistros()
+	if err != nil {
+		t.Fatalf("ListInstalledWingetDistros failed: %v", err)
+	}
+
+	// VS Code is not a WSL package and Kali is not in the catalog
+	if len(distros) != 2 {
+		t.Fatalf("Expected 2 distros, got %d: %+v", len(distros), distros)
+	}
+	if distros[0].PackageID != "Canonical.Ubuntu.2204" || distros[0].Name != "Ubuntu 22.04.5 LTS" || distros[0].Version != "Ubuntu 22.04 LTS" {
+		t.Errorf("Unexpected first distro: %+v", distros[0])
+	}
+	if distros[1].PackageID != "9MSVKQC78PK6" || distros[1].Name != "Debian" {
+		t.Errorf("Unexpected second distro: %+v", distros[1])
+	}
+}
+
+func TestManagerListInstalledWingetDistrosError(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Errors["winget list --accept-source-agreements"] = fmt.Errorf("exit status 1")
+
+	mgr := winget.NewManager(t.TempDir())
+	mgr.SetRunner(mock)
+	if _, err := mgr.ListInstalledWingetDistros(); err == nil {
+		t.Error("Expected an error when winget list fails")
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+const sampleWSLConf = `# managed by hand
+[boot]
+systemd=true
+
+[user]
+default = dev
+
+[network]
+hostname = devbox
+nameserver = 1.1.1.1
+`
+
+func TestParseWSLConf(t *testing.T) {
+	conf, err := wsl.ParseConf(sampleWSLConf)
+	if err != nil {
+		t.Fatalf("ParseConf failed: %v", err)
+	}
+	if conf.Boot.Systemd == nil || !*conf.Boot.Systemd {
+		t.Errorf("Expected systemd=true, got %v", conf.Boot.Systemd)
+	}
+	if conf.User.Default != "dev" || conf.Network.Hostname != "devbox" {
+		t.Errorf("Unexpected user/network: %+v %+v", conf.User, conf.Network)
+	}
+	if conf.Extra["network"]["nameserver"] != "1.1.1.1" {
+		t.Errorf("Expected unknown key kept in Extra, got %v", conf.Extra)
+	}
+
+	if _, err := wsl.ParseConf("[boot]\nsystemd=maybe\n"); err == nil {
+		t.Error("Expected error for invalid bool")
+	}
+}
+
+func TestWSLConfRoundTrip(t *testing.T) {
+	conf, err := wsl.ParseConf(sampleWSLConf)
+	if err != nil {
+		t.Fatalf("ParseConf failed: %v", err)
+	}
+	if err := conf.Set("interop.appendWindowsPath", "false"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	text := conf.String()
+	for _, want := range []string{"[boot]\nsystemd=true\n", "hostname=devbox\nnameserver=1.1.1.1\n", "[interop]\nappendWindowsPath=false\n", "[user]\ndefault=dev\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+
+	again, err := wsl.ParseConf(text)
+	if err != nil || again.String() != text {
+		t.Errorf("Expected stable round trip, got:\n%s", again.String())
+	}
+}
+
+func TestWSLWriteConf(t *testing.T) {
+	mock := NewMockRunner()
+	client := wsl.NewClient(mock)
+
+	conf := wsl.WSLConf{}
+	_ = conf.Set("boot.systemd", "true")
+	if err := client.WriteConf("Ubuntu", conf); err != nil {
+		t.Fatalf("WriteConf failed: %v", err)
+	}
+	if len(mock.Calls) != 1 || mock.Calls[0] != "wsl.exe -d Ubuntu -u root sh -c cat > /etc/wsl.conf" {
+		t.Errorf("Unexpected calls: %v", mock.Calls)
+	}
+}
@@ -1,8 +1,18 @@
 package tests
 
 import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/yuanjua/autowsl/internal/runner"
 	"github.com/yuanjua/autowsl/internal/wsl"
 )
 
@@ -141,3 +151,373 @@ func TestWSLExportValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestWSLRenameValidation(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = `  NAME                   STATE           VERSION
+* Ubuntu                 Running         2
+  Debian                 Stopped         2
+`
+	client := wsl.NewClient(mock)
+
+	tests := []struct {
+		name    string
+		oldName string
+		newName string
+	}{
+		{"empty old name", "", "new"},
+		{"empty new name", "Ubuntu", ""},
+		{"same name", "Ubuntu", "Ubuntu"},
+		{"old does not exist", "Arch", "new"},
+		{"new already exists", "Ubuntu", "Debian"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := client.Rename(tt.oldName, tt.newName); err == nil {
+				t.Errorf("Rename(%q, %q) expected error, got nil", tt.oldName, tt.newName)
+			}
+		})
+	}
+}
+
+func TestWSLRenameKeepsOriginalOnImportFailure(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = `  NAME                   STATE           VERSION
+* Ubuntu                 Running         2
+`
+	client := wsl.NewClient(mock)
+
+	// The mock export never writes a tar file, so the import step must fail
+	if err := client.Rename("Ubuntu", "dev-box"); err == nil {
+		t.Fatal("Expected error when import fails, got nil")
+	}
+
+	for _, call := range mock.Calls {
+		if strings.HasPrefix(call, "wsl.exe --unregister") {
+			t.Errorf("Original distro should not be unregistered after failed import, got call: %s", call)
+		}
+	}
+}
+
+func TestWSLDryRunLeavesNoFiles(t *testing.T) {
+	r := runner.NewExecRunner(0)
+	r.DryRun = true
+	var out bytes.Buffer
+	r.Out = &out
+	client := wsl.NewClient(r)
+
+	root := t.TempDir()
+	installPath := filepath.Join(root, "distros", "dry")
+	backupPath := filepath.Join(root, "backups", "dry.tar")
+
+	err := client.Import(wsl.ImportOptions{
+		Name:        "dry",
+		InstallPath: installPath,
+		TarFilePath: filepath.Join(root, ".autowsl_tmp", "install.tar.gz"),
+	})
+	if err != nil {
+		t.Fatalf("Expected dry-run import to succeed, got: %v", err)
+	}
+	if err := client.Export("dry", backupPath); err != nil {
+		t.Fatalf("Expected dry-run export to succeed, got: %v", err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no files after dry run, found %d entries", len(entries))
+	}
+
+	if !strings.Contains(out.String(), "[dry-run] wsl.exe --import dry") {
+		t.Errorf("Expected import command to be printed, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "[dry-run] wsl.exe --export dry") {
+		t.Errorf("Expected export command to be printed, got: %s", out.String())
+	}
+}
+
+func TestWSLExportWithGzip(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* Ubuntu    Stopped         2\n"
+	client := wsl.NewClient(mock)
+
+	// The mock runner does not write anything, so stand in for wsl --export
+	tarPath := filepath.Join(t.TempDir(), "ubuntu-backup.tar")
+	content := strings.Repeat("rootfs ", 4096)
+	if err := os.WriteFile(tarPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.ExportWithOptions(wsl.ExportOptions{
+		Name:        "Ubuntu",
+		OutputPath:  tarPath,
+		Compression: wsl.CompressionGzip,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if result.Path != tarPath+".gz" {
+		t.Errorf("Expected %s.gz, got %s", tarPath, result.Path)
+	}
+	if result.UncompressedSize != int64(len(content)) || result.Size >= result.UncompressedSize {
+		t.Errorf("Unexpected sizes: %+v", result)
+	}
+	if _, err := os.Stat(tarPath); !os.IsNotExist(err) {
+		t.Error("Expected raw tar to be removed after compression")
+	}
+
+	f, err := os.Open(result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Expected a valid gzip file: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	if string(data) != content {
+		t.Error("Decompressed content does not match the original tar")
+	}
+}
+
+func TestWSLExportInvalidCompression(t *testing.T) {
+	client := wsl.NewClient(NewMockRunner())
+
+	_, err := client.ExportWithOptions(wsl.ExportOptions{Name: "Ubuntu", OutputPath: "out.tar", Compression: "zip"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported compression") {
+		t.Errorf("Expected unsupported compression error, got: %v", err)
+	}
+}
+
+func TestWSLConvertVersion(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* Ubuntu    Stopped         1\n  Debian    Stopped         2\n"
+	client := wsl.NewClient(mock)
+
+	if err := client.ConvertVersion("Ubuntu", 2); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	last := mock.Calls[len(mock.Calls)-1]
+	if last != "wsl.exe --set-version Ubuntu 2" {
+		t.Errorf("Expected 'wsl.exe --set-version Ubuntu 2', got %q", last)
+	}
+
+	// Already at the target version: no conversion is attempted
+	calls := len(mock.Calls)
+	if err := client.ConvertVersion("Debian", 2); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, c := range mock.Calls[calls:] {
+		if strings.Contains(c, "--set-version") {
+			t.Errorf("Expected no conversion for Debian, got %q", c)
+		}
+	}
+
+	if err := client.ConvertVersion("Missing", 2); err == nil {
+		t.Error("Expected error for non-existent distro")
+	}
+	if err := client.ConvertVersion("Ubuntu", 3); err == nil {
+		t.Error("Expected error for invalid version")
+	}
+}
+
+func TestWSLCompactWindows11(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* Ubuntu    Running         2\n  Legacy    Stopped         1\n"
+	mock.Outputs[`reg.exe query HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion /v CurrentBuild`] =
+		"\nHKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows NT\\CurrentVersion\n    CurrentBuild    REG_SZ    22631\n"
+	client := wsl.NewClient(mock)
+
+	if err := client.Compact("Ubuntu"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	terminate, sparse := -1, -1
+	for i, c := range mock.Calls {
+		switch c {
+		case "wsl.exe --terminate Ubuntu":
+			terminate = i
+		case "wsl.exe --manage Ubuntu --set-sparse true":
+			sparse = i
+		}
+	}
+	if terminate == -1 || sparse == -1 || terminate > sparse {
+		t.Errorf("Expected terminate before --set-sparse, got calls: %v", mock.Calls)
+	}
+
+	if err := client.Compact("Legacy"); err == nil {
+		t.Error("Expected error compacting a WSL 1 distribution")
+	}
+}
+
+// unregisteringRunner drops all distros from 'wsl -l -v' once one is unregistered
+type unregisteringRunner struct {
+	*MockRunner
+}
+
+func (r *unregisteringRunner) Run(name string, args ...string) (string, string, error) {
+	if len(args) > 0 && args[0] == "--unregister" {
+		r.Outputs["wsl.exe -l -v"] = ""
+	}
+	return r.MockRunner.Run(name, args...)
+}
+
+// moveFixture prepares the tar a mock export would have written and a mock
+// that lists Ubuntu, installed at <root>/old, and the copy imported for a move
+func moveFixture(t *testing.T) (mock *MockRunner, root, tempDir, tarPath string) {
+	t.Helper()
+	root = t.TempDir()
+	tempDir = filepath.Join(root, ".autowsl_tmp")
+	tarPath = filepath.Join(tempDir, "Ubuntu-move.tar")
+
+	// The mock export never writes a tar file, so stand in for wsl --export
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tarPath, []byte("rootfs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock = NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* Ubuntu    Stopped         2\n"
+	mock.Outputs[`reg.exe query HKCU\Software\Microsoft\Windows\CurrentVersion\Lxss /s`] =
+		"HKEY_CURRENT_USER\\Software\\Microsoft\\Windows\\CurrentVersion\\Lxss\\{1}\n" +
+			"    DistributionName    REG_SZ    Ubuntu\n" +
+			"    BasePath    REG_SZ    " + filepath.Join(root, "old") + "\n" +
+			"HKEY_CURRENT_USER\\Software\\Microsoft\\Windows\\CurrentVersion\\Lxss\\{2}\n" +
+			"    DistributionName    REG_SZ    Ubuntu-moving\n" +
+			"    BasePath    REG_SZ    " + filepath.Join(root, "new") + "\n"
+	return mock, root, tempDir, tarPath
+}
+
+// moveSequence returns the export, import, unregister and registry edits of a move
+func moveSequence(calls []string) []string {
+	var sequence []string
+	for _, c := range calls {
+		if strings.HasPrefix(c, "wsl.exe --export") || strings.HasPrefix(c, "wsl.exe --unregister") ||
+			strings.HasPrefix(c, "wsl.exe --import") || strings.HasPrefix(c, "reg.exe add") {
+			sequence = append(sequence, c)
+		}
+	}
+	return sequence
+}
+
+func TestWSLMoveImportsBeforeUnregistering(t *testing.T) {
+	mock, root, tempDir, tarPath := moveFixture(t)
+	newPath := filepath.Join(root, "new")
+	client := wsl.NewClient(&unregisteringRunner{mock})
+
+	name, err := client.Move(wsl.MoveOptions{Name: "Ubuntu", NewPath: newPath, TempDir: tempDir})
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if name != "Ubuntu" {
+		t.Errorf("Expected the moved distribution to keep its name, got %q", name)
+	}
+
+	want := []string{
+		"wsl.exe --export Ubuntu " + tarPath,
+		"wsl.exe --import Ubuntu-moving " + newPath + " " + tarPath + " --version 2",
+		"wsl.exe --unregister Ubuntu",
+		`reg.exe add HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Lxss\{2} /v DistributionName /t REG_SZ /d Ubuntu /f`,
+	}
+	if got := moveSequence(mock.Calls); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected call sequence:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWSLMoveKeepsOriginalOnImportFailure(t *testing.T) {
+	mock, root, tempDir, tarPath := moveFixture(t)
+	newPath := filepath.Join(root, "new")
+	newImport := "wsl.exe --import Ubuntu-moving " + newPath + " " + tarPath + " --version 2"
+	mock.Errors[newImport] = fmt.Errorf("exit status 1")
+	client := wsl.NewClient(&unregisteringRunner{mock})
+
+	_, err := client.Move(wsl.MoveOptions{Name: "Ubuntu", NewPath: newPath, TempDir: tempDir})
+	if err == nil || !strings.Contains(err.Error(), "original 'Ubuntu' left unchanged") {
+		t.Fatalf("Expected import error, got: %v", err)
+	}
+
+	want := []string{
+		"wsl.exe --export Ubuntu " + tarPath,
+		newImport,
+	}
+	if got := moveSequence(mock.Calls); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected call sequence:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWSLImportFromURL(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("TMP", os.Getenv("TMPDIR"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("rootfs"))
+	}))
+	defer server.Close()
+
+	for _, keep := range []bool{false, true} {
+		mock := NewMockRunner()
+		name := fmt.Sprintf("url-import-%v", keep)
+		err := wsl.NewClient(mock).ImportFromURL(server.URL+"/rootfs.tar.gz", wsl.ImportOptions{
+			Name:        name,
+			InstallPath: t.TempDir(),
+			KeepTar:     keep,
+		})
+		if err != nil {
+			t.Fatalf("ImportFromURL failed: %v", err)
+		}
+
+		tarPath := filepath.Join(wsl.ImportDownloadDir(name), "rootfs.tar.gz")
+		found := false
+		for _, call := range mock.Calls {
+			if strings.HasPrefix(call, "wsl.exe --import "+name+" ") && strings.Contains(call, tarPath) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected an import of %s, got calls: %v", tarPath, mock.Calls)
+		}
+
+		_, statErr := os.Stat(tarPath)
+		if keep && statErr != nil {
+			t.Errorf("Expected the tarball to be kept: %v", statErr)
+		}
+		if !keep && !os.IsNotExist(statErr) {
+			t.Errorf("Expected the tarball to be removed, got %v", statErr)
+		}
+	}
+}
+
+func TestWSLImportFromURLExistingDistro(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* dev       Stopped         2\n"
+
+	err := wsl.NewClient(mock).ImportFromURL("http://127.0.0.1:1/rootfs.tar.gz", wsl.ImportOptions{Name: "dev", InstallPath: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an already-exists error before downloading, got %v", err)
+	}
+}
+
+func TestWSLExportVHD(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* Ubuntu    Stopped         2\n"
+	client := wsl.NewClient(mock)
+
+	outputPath := filepath.Join(t.TempDir(), "backups", "Ubuntu-backup.vhdx")
+	if err := client.ExportVHD("Ubuntu", outputPath); err != nil {
+		t.Fatalf("ExportVHD failed: %v", err)
+	}
+	want := "wsl.exe --export Ubuntu " + outputPath + " --vhd"
+	if got := mock.Calls[len(mock.Calls)-1]; got != want {
+		t.Errorf("Unexpected export call:\n got: %s\nwant: %s", got, want)
+	}
+
+	if err := client.ExportVHD("Debian", outputPath); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected error for a missing distribution, got %v", err)
+	}
+}
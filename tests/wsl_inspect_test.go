@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+func TestWSLMemory(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -d Ubuntu -- cat /proc/meminfo"] = `MemTotal:        8000000 kB
+MemFree:         1000000 kB
+MemAvailable:    6000000 kB
+Buffers:          100000 kB
+`
+
+	client := wsl.NewClient(mock)
+	mem, err := client.Memory("Ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mem.TotalKB != 8000000 || mem.AvailableKB != 6000000 {
+		t.Errorf("Unexpected memory info: %+v", mem)
+	}
+	if mem.UsedKB() != 2000000 {
+		t.Errorf("Expected 2000000 kB used, got %d", mem.UsedKB())
+	}
+
+	mock.Outputs["wsl.exe -d Ubuntu -- cat /proc/meminfo"] = "garbage"
+	if _, err := client.Memory("Ubuntu"); err == nil {
+		t.Error("Expected error for unparseable meminfo")
+	}
+}
+
+func TestWSLProcesses(t *testing.T) {
+	mock := NewMockRunner()
+	script := `for p in /proc/[0-9]*; do echo "${p#/proc/} $(cat $p/comm 2>/dev/null)"; done`
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c "+script] = "1 init\n42 bash\nself \n"
+
+	client := wsl.NewClient(mock)
+	procs, err := client.Processes("Ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(procs) != 2 {
+		t.Fatalf("Expected 2 processes, got %v", procs)
+	}
+	if procs[1].PID != 42 || procs[1].Name != "bash" {
+		t.Errorf("Unexpected process: %+v", procs[1])
+	}
+}
+
+func TestWSLInstallPaths(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs[`reg.exe query HKCU\Software\Microsoft\Windows\CurrentVersion\Lxss /s`] = `
+HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Lxss
+    DefaultDistribution    REG_SZ    {aaa}
+
+HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Lxss\{aaa}
+    DistributionName    REG_SZ    Ubuntu
+    BasePath    REG_SZ    \\?\C:\wsl\ubuntu
+
+HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Lxss\{bbb}
+    BasePath    REG_SZ    D:\wsl\debian
+    DistributionName    REG_SZ    Debian
+`
+
+	client := wsl.NewClient(mock)
+	paths, err := client.InstallPaths()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if paths["Ubuntu"] != `C:\wsl\ubuntu` || paths["Debian"] != `D:\wsl\debian` || len(paths) != 2 {
+		t.Errorf("Unexpected install paths: %v", paths)
+	}
+
+	if _, err := client.GetInstallPath("Missing"); err == nil {
+		t.Error("Expected error for unknown distribution")
+	}
+}
+
+func TestWSLRuntimeStatus(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c ip -4 addr show eth0 2>/dev/null"] = `2: eth0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc mq state UP group default qlen 1000
+    inet 172.20.14.7/20 brd 172.20.15.255 scope global eth0
+       valid_lft forever preferred_lft forever
+`
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c free -m"] = `               total        used        free      shared  buff/cache   available
+Mem:            7836         512        6900           3         423        7100
+Swap:           2048           0        2048
+`
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c ps aux --no-headers | wc -l"] = "12\n"
+
+	client := wsl.NewClient(mock)
+	status, err := client.RuntimeStatus("Ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status.IPv4 != "172.20.14.7" {
+		t.Errorf("Expected IP 172.20.14.7, got %q", status.IPv4)
+	}
+	if status.MemoryTotalMB != 7836 || status.MemoryUsedMB != 512 {
+		t.Errorf("Unexpected memory: %+v", status)
+	}
+	if status.ProcessCount != 12 {
+		t.Errorf("Expected 12 processes, got %d", status.ProcessCount)
+	}
+
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c free -m"] = "garbage"
+	if _, err := client.RuntimeStatus("Ubuntu"); err == nil {
+		t.Error("Expected error for unparseable free output")
+	}
+}
+
+func TestWSLListPackages(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs[`wsl.exe -d Ubuntu -- sh -c dpkg-query -W -f='${Package}\t${Version}\n'`] = "zlib1g\t1:1.2.13\nbash\t5.2-1\n"
+
+	client := wsl.NewClient(mock)
+	packages, err := client.ListPackages("Ubuntu", "apt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(packages) != 2 || packages[0].Name != "bash" || packages[0].Version != "5.2-1" {
+		t.Errorf("Unexpected packages: %+v", packages)
+	}
+
+	if _, err := client.ListPackages("Ubuntu", "brew"); err == nil {
+		t.Error("Expected error for unsupported package manager")
+	}
+}
+
+func TestParsePackagesNativeFormats(t *testing.T) {
+	apk := wsl.ParsePackages("apk", "musl-1.2.4-r2\nca-certificates-bundle-20230506-r0\n")
+	if len(apk) != 2 || apk[0].Name != "ca-certificates-bundle" || apk[0].Version != "20230506-r0" {
+		t.Errorf("Unexpected apk packages: %+v", apk)
+	}
+
+	pacman := wsl.ParsePackages("pacman", "bash 5.2.026-2\n")
+	if len(pacman) != 1 || pacman[0].Name != "bash" || pacman[0].Version != "5.2.026-2" {
+		t.Errorf("Unexpected pacman packages: %+v", pacman)
+	}
+}
+
+func TestWSLStartAndShutdown(t *testing.T) {
+	mock := NewMockRunner()
+	client := wsl.NewClient(mock)
+
+	if err := client.Start("Ubuntu"); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := client.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	want := []string{"wsl.exe -d Ubuntu -- echo started", "wsl.exe --shutdown"}
+	if len(mock.Calls) != len(want) {
+		t.Fatalf("Expected calls %v, got %v", want, mock.Calls)
+	}
+	for i := range want {
+		if mock.Calls[i] != want[i] {
+			t.Errorf("Call %d = %q, want %q", i, mock.Calls[i], want[i])
+		}
+	}
+
+	if err := client.Start(""); err == nil {
+		t.Error("Expected error for empty name")
+	}
+}
+
+func TestWSLIPv4(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c ip -4 addr show eth0 2>/dev/null"] = "    inet 172.28.160.5/20 brd 172.28.175.255 scope global eth0\n"
+
+	client := wsl.NewClient(mock)
+	ip, err := client.IPv4("Ubuntu")
+	if err != nil || ip != "172.28.160.5" {
+		t.Errorf("IPv4() = %q, %v; want 172.28.160.5", ip, err)
+	}
+
+	if _, err := client.IPv4("Legacy"); err == nil {
+		t.Error("Expected error when eth0 has no address")
+	}
+}
+
+func TestWSLLoginShell(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs[`wsl.exe -d Ubuntu -u root -- sh -c grep "^$(id -un):" /etc/passwd`] = "root:x:0:0:root:/root:/usr/bin/zsh\n"
+
+	client := wsl.NewClient(mock)
+	shell, err := client.LoginShell("Ubuntu", "root")
+	if err != nil || shell != "/usr/bin/zsh" {
+		t.Errorf("LoginShell() = %q, %v; want /usr/bin/zsh", shell, err)
+	}
+
+	if _, err := wsl.ParseLoginShell("nobody:x:65534:65534"); err == nil {
+		t.Error("Expected error for a passwd entry without a shell")
+	}
+	if err := wsl.ValidateShellPath("zsh"); err == nil {
+		t.Error("Expected error for a relative shell path")
+	}
+	if err := wsl.ValidateShellPath("/bin/zsh"); err != nil {
+		t.Errorf("ValidateShellPath(/bin/zsh) = %v", err)
+	}
+}
+
+func TestWSLWaitForState(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = "  NAME      STATE           VERSION\n* Ubuntu    Stopped         2\n"
+	client := wsl.NewClient(mock)
+
+	if err := client.WaitForState("Ubuntu", "stopped", time.Second); err != nil {
+		t.Errorf("WaitForState(Stopped) failed: %v", err)
+	}
+
+	err := client.WaitForState("Ubuntu", "Running", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "current state: Stopped") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+
+	if err := client.WaitForState("Debian", "Stopped", time.Second); err == nil {
+		t.Error("Expected error for a missing distribution")
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+func TestWSLGetWSL2IP(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -d Ubuntu -- sh -c ip -4 addr show eth0 2>/dev/null"] = "    inet 172.20.1.2/20 brd 172.20.15.255 scope global eth0\n"
+
+	ip, err := wsl.NewClient(mock).GetWSL2IP("Ubuntu")
+	if err != nil || ip != "172.20.1.2" {
+		t.Errorf("GetWSL2IP() = %q, %v; want 172.20.1.2", ip, err)
+	}
+}
+
+func TestWSLPortProxy(t *testing.T) {
+	mock := NewMockRunner()
+	client := wsl.NewClient(mock)
+
+	if err := client.AddPortProxy(8080, 80, "172.20.1.2"); err != nil {
+		t.Fatalf("AddPortProxy failed: %v", err)
+	}
+	want := "netsh.exe interface portproxy add v4tov4 listenport=8080 listenaddress=0.0.0.0 connectport=80 connectaddress=172.20.1.2"
+	if got := mock.Calls[len(mock.Calls)-1]; got != want {
+		t.Errorf("Unexpected netsh call:\n got: %s\nwant: %s", got, want)
+	}
+
+	if err := client.RemovePortProxy(8080); err != nil {
+		t.Fatalf("RemovePortProxy failed: %v", err)
+	}
+	want = "netsh.exe interface portproxy delete v4tov4 listenport=8080 listenaddress=0.0.0.0"
+	if got := mock.Calls[len(mock.Calls)-1]; got != want {
+		t.Errorf("Unexpected netsh call:\n got: %s\nwant: %s", got, want)
+	}
+
+	if err := client.AddPortProxy(8080, 80, "not-an-ip"); err == nil {
+		t.Error("Expected error for an invalid connect address")
+	}
+	if err := client.AddPortProxy(0, 80, "172.20.1.2"); err == nil {
+		t.Error("Expected error for port 0")
+	}
+}
+
+func TestWSLPortProxyNotElevated(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Errors["net.exe session"] = errors.New("exit status 2")
+
+	err := wsl.NewClient(mock).AddPortProxy(8080, 80, "172.20.1.2")
+	if !errors.Is(err, wsl.ErrNotElevated) {
+		t.Errorf("Expected ErrNotElevated, got %v", err)
+	}
+	for _, call := range mock.Calls {
+		if call != "net.exe session" {
+			t.Errorf("Expected no netsh call without elevation, got %s", call)
+		}
+	}
+}
+
+func TestWSLListPortProxies(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["netsh.exe interface portproxy show v4tov4"] = `
+Listen on ipv4:             Connect to:
+
+Address         Port        Address         Port
+--------------- ----------  --------------- ----------
+0.0.0.0         8080        172.20.1.2      80
+0.0.0.0         2222        172.20.1.2      22
+`
+
+	proxies, err := wsl.NewClient(mock).ListPortProxies()
+	if err != nil {
+		t.Fatalf("ListPortProxies failed: %v", err)
+	}
+	if len(proxies) != 2 {
+		t.Fatalf("Expected 2 rules, got %d: %+v", len(proxies), proxies)
+	}
+	want := wsl.PortProxy{ListenAddress: "0.0.0.0", ListenPort: 2222, ConnectAddress: "172.20.1.2", ConnectPort: 22}
+	if proxies[1] != want {
+		t.Errorf("proxies[1] = %+v, want %+v", proxies[1], want)
+	}
+}
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		spec         string
+		listen, dest int
+		wantErr      bool
+	}{
+		{"8080:80", 8080, 80, false},
+		{"3000", 3000, 3000, false},
+		{"8080:", 0, 0, true},
+		{"a:80", 0, 0, true},
+		{"70000:80", 0, 0, true},
+	}
+	for _, tt := range tests {
+		listen, dest, err := wsl.ParsePortMapping(tt.spec)
+		if (err != nil) != tt.wantErr || listen != tt.listen || dest != tt.dest {
+			t.Errorf("ParsePortMapping(%q) = %d, %d, %v", tt.spec, listen, dest, err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/yuanjua/autowsl/internal/wsl"
@@ -112,3 +114,156 @@ func TestListInstalledDistrosBenignFailure(t *testing.T) {
 		t.Fatalf("Expected 0 distros, got %d", len(distros))
 	}
 }
+
+func TestSetDefault(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = `  NAME                   STATE           VERSION
+* Ubuntu-22.04           Running         2
+  Debian                 Stopped         2
+`
+	client := wsl.NewClient(mock)
+
+	if err := client.SetDefault(""); err == nil {
+		t.Error("Expected error for empty name, got nil")
+	}
+
+	if err := client.SetDefault("Arch"); err == nil {
+		t.Error("Expected error for non-existing distro, got nil")
+	}
+
+	if err := client.SetDefault("Debian"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	last := mock.Calls[len(mock.Calls)-1]
+	if last != "wsl.exe --set-default Debian" {
+		t.Errorf("Expected 'wsl.exe --set-default Debian', got '%s'", last)
+	}
+}
+
+func TestGetDefaultDistro(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = `  NAME                   STATE           VERSION
+  Ubuntu-22.04           Running         2
+* Debian                 Stopped         2
+`
+	client := wsl.NewClient(mock)
+
+	name, err := client.GetDefaultDistro()
+	if err != nil || name != "Debian" {
+		t.Errorf("GetDefaultDistro() = %q, %v; want Debian", name, err)
+	}
+
+	mock.Outputs["wsl.exe -l -v"] = `  NAME                   STATE           VERSION
+  Ubuntu-22.04           Running         2
+`
+	if _, err := client.GetDefaultDistro(); err == nil {
+		t.Error("Expected error when no default is set")
+	}
+}
+
+func TestValidateDistroName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"simple", "Ubuntu", false},
+		{"dots and dashes", "Ubuntu-22.04", false},
+		{"underscore", "dev_box", false},
+		{"empty", "", true},
+		{"space", "my distro", true},
+		{"leading dash", "-ubuntu", true},
+		{"trailing dash", "ubuntu-", true},
+		{"slash", "debian-gnu/linux", true},
+		{"backslash", `dev\box`, true},
+		{"too long", strings.Repeat("a", 65), true},
+		{"max length", strings.Repeat("a", 64), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wsl.ValidateDistroName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDistroName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseWSLVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   wsl.WSLVersionInfo
+	}{
+		{
+			name: "english",
+			output: "WSL version: 2.0.14.0\r\nKernel version: 5.15.133.1-1\r\nWSLg version: 1.0.59\r\n" +
+				"MSRDC version: 1.2.4677\r\nDirect3D version: 1.611.1-81528511\r\nWindows version: 10.0.22631.2861\r\n",
+			want: wsl.WSLVersionInfo{WSL: "2.0.14.0", Kernel: "5.15.133.1-1", WSLg: "1.0.59"},
+		},
+		{
+			name:   "utf-16 output",
+			output: "W\x00S\x00L\x00 \x00v\x00e\x00r\x00s\x00i\x00o\x00n\x00:\x00 \x002\x00.\x001\x00.\x005\x00.\x000\x00\r\x00\n\x00",
+			want:   wsl.WSLVersionInfo{WSL: "2.1.5.0"},
+		},
+		{
+			name:   "german",
+			output: "WSL-Version: 2.0.9.0\nKernelversion: 5.15.133.1-microsoft-standard-WSL2\nWSLg-Version: 1.0.59\n",
+			want:   wsl.WSLVersionInfo{WSL: "2.0.9.0", Kernel: "5.15.133.1-microsoft-standard-WSL2", WSLg: "1.0.59"},
+		},
+		{
+			name:   "full-width colon",
+			output: "WSL 版本： 2.0.14.0\n内核版本： 5.15.133.1-1\nWSLg 版本： 1.0.59\n",
+			want:   wsl.WSLVersionInfo{WSL: "2.0.14.0", WSLg: "1.0.59"},
+		},
+		{
+			name:   "system distro",
+			output: "WSL version: 1.0.3.0\nKernel version: 5.15.79.1\nWSLg version: 1.0.47\nSystem distro version: 1.0.3\n",
+			want:   wsl.WSLVersionInfo{WSL: "1.0.3.0", Kernel: "5.15.79.1", WSLg: "1.0.47", SystemDistro: "1.0.3"},
+		},
+		{
+			name: "inbox status",
+			output: "Default Distribution: Ubuntu\nDefault Version: 2\n\n" +
+				"Windows Subsystem for Linux was last updated on 1/12/2023\nWSL automatic updates are on.\n\nKernel version: 5.10.102.1\n",
+			want: wsl.WSLVersionInfo{Kernel: "5.10.102.1"},
+		},
+		{
+			name:   "help text",
+			output: "Usage: wsl.exe [Argument]\n\nArguments:\n    --exec, -e <CommandLine>\n",
+			want:   wsl.WSLVersionInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wsl.ParseWSLVersion(tt.output); got != tt.want {
+				t.Errorf("ParseWSLVersion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetWSLVersionFallsBackToStatus(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Errors["wsl.exe --version"] = errors.New("exit status 4294967295")
+	mock.Outputs["wsl.exe --status"] = "Default Version: 2\nKernel version: 5.10.102.1\n"
+
+	info, err := wsl.NewClient(mock).GetWSLVersion()
+	if err != nil {
+		t.Fatalf("GetWSLVersion failed: %v", err)
+	}
+	if info.WSL != "" || info.Kernel != "5.10.102.1" {
+		t.Errorf("GetWSLVersion() = %+v, want only the kernel version", info)
+	}
+
+	mock = NewMockRunner()
+	mock.Outputs["wsl.exe --version"] = "WSL version: 2.0.14.0\nKernel version: 5.15.133.1-1\n"
+	info, err = wsl.NewClient(mock).GetWSLVersion()
+	if err != nil || info.WSL != "2.0.14.0" {
+		t.Errorf("GetWSLVersion() = %+v, %v", info, err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected no --status call when --version works, got %v", mock.Calls)
+	}
+}
@@ -13,6 +13,7 @@ type MockRunner struct {
 	Stderr  map[string]string // command -> stderr
 	Errors  map[string]error  // command -> error
 	Calls   []string          // track all commands called
+	Argv    [][]string        // name and arguments of each call, unjoined
 }
 
 func NewMockRunner() *MockRunner {
@@ -27,6 +28,7 @@ func NewMockRunner() *MockRunner {
 func (m *MockRunner) Run(name string, args ...string) (string, string, error) {
 	cmd := name + " " + strings.Join(args, " ")
 	m.Calls = append(m.Calls, cmd)
+	m.Argv = append(m.Argv, append([]string{name}, args...))
 
 	if err, ok := m.Errors[cmd]; ok {
 		return "", m.Stderr[cmd], err
@@ -97,6 +99,45 @@ func TestWSLListInstalledDistros(t *testing.T) {
 	}
 }
 
+func TestWSLFilterDistros(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -l -v"] = `  NAME                   STATE           VERSION
+* Ubuntu-22.04           Running         2
+  Debian                 Stopped         2
+  kali-linux             Stopped         1
+  Alpine                 Running         1
+`
+
+	client := wsl.NewClient(mock)
+	distros, err := client.ListInstalledDistros()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter wsl.DistroFilter
+		want   []string
+	}{
+		{"no filter", wsl.DistroFilter{}, []string{"Ubuntu-22.04", "Debian", "kali-linux", "Alpine"}},
+		{"running", wsl.DistroFilter{Running: true}, []string{"Ubuntu-22.04", "Alpine"}},
+		{"stopped", wsl.DistroFilter{Stopped: true}, []string{"Debian", "kali-linux"}},
+		{"running and stopped", wsl.DistroFilter{Running: true, Stopped: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range wsl.FilterDistros(distros, tt.filter) {
+				got = append(got, d.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterDistros() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWSLIsDistroInstalled(t *testing.T) {
 	fakeOutput := `  NAME                   STATE           VERSION
 * Ubuntu-22.04           Running         2
@@ -232,6 +273,84 @@ func TestWSLParseListEdgeCases(t *testing.T) {
 	}
 }
 
+func TestWSLParseListNamesWithSpaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    wsl.InstalledDistro
+		wantIdx int
+	}{
+		{
+			name: "aligned columns",
+			input: `  NAME                STATE           VERSION
+* Ubuntu              Running         2
+  My Custom Distro    Stopped         2
+`,
+			want:    wsl.InstalledDistro{Name: "My Custom Distro", State: "Stopped", Version: "2"},
+			wantIdx: 1,
+		},
+		{
+			name: "localized state with a space",
+			input: `  NAME                STATE              VERSION
+* My Custom Distro    Wird ausgeführt    2
+`,
+			want: wsl.InstalledDistro{Name: "My Custom Distro", State: "Wird ausgeführt", Version: "2", Default: true},
+		},
+		{
+			name: "quoted name",
+			input: `  NAME                  STATE           VERSION
+  "My Custom Distro"    Stopped         1
+`,
+			want: wsl.InstalledDistro{Name: "My Custom Distro", State: "Stopped", Version: "1"},
+		},
+		{
+			name: "columns not aligned with the header",
+			input: `  NAME STATE VERSION
+* My Custom Distro   Running   2
+`,
+			want: wsl.InstalledDistro{Name: "My Custom Distro", State: "Running", Version: "2", Default: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockRunner()
+			mock.Outputs["wsl.exe -l -v"] = tt.input
+
+			distros, err := wsl.NewClient(mock).ListInstalledDistros()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(distros) <= tt.wantIdx {
+				t.Fatalf("Expected at least %d distros, got %+v", tt.wantIdx+1, distros)
+			}
+			if distros[tt.wantIdx] != tt.want {
+				t.Errorf("distros[%d] = %+v, want %+v", tt.wantIdx, distros[tt.wantIdx], tt.want)
+			}
+		})
+	}
+}
+
+func TestWSLParseListBasicNamesWithSpaces(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Errors["wsl.exe -l -v"] = &mockError{"exit status 1"}
+	mock.Outputs["wsl.exe -l"] = "Windows Subsystem for Linux Distributions:\nMy Custom Distro (Default)\n\"Quoted Distro\"\n"
+
+	distros, err := wsl.NewClient(mock).ListInstalledDistros()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(distros) != 2 {
+		t.Fatalf("Expected 2 distros, got %+v", distros)
+	}
+	if distros[0].Name != "My Custom Distro" || !distros[0].Default {
+		t.Errorf("Unexpected default distro: %+v", distros[0])
+	}
+	if distros[1].Name != "Quoted Distro" {
+		t.Errorf("Expected quotes to be stripped, got %q", distros[1].Name)
+	}
+}
+
 // Helper type for mock errors
 type mockError struct {
 	msg string
@@ -240,3 +359,40 @@ type mockError struct {
 func (e *mockError) Error() string {
 	return e.msg
 }
+
+func TestWSLRunCommandCaptureOutput(t *testing.T) {
+	mock := NewMockRunner()
+	mock.Outputs["wsl.exe -d Ubuntu -u root -- uname -a"] = "Linux ubuntu 5.15\n"
+	client := wsl.NewClient(mock)
+
+	var out strings.Builder
+	err := client.RunCommand("Ubuntu", []string{"uname", "-a"}, wsl.RunCommandOptions{
+		CaptureOutput: true,
+		User:          "root",
+		Stdout:        &out,
+		Stderr:        &out,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.String() != "Linux ubuntu 5.15\n" {
+		t.Errorf("Expected captured output, got %q", out.String())
+	}
+
+	mock.Errors["wsl.exe -d Ubuntu -- false"] = &mockError{"exit status 1"}
+	err = client.RunCommand("Ubuntu", []string{"false"}, wsl.RunCommandOptions{CaptureOutput: true, Stdout: &out, Stderr: &out})
+	if err == nil {
+		t.Error("Expected error for failing command, got nil")
+	}
+
+	if err := client.RunCommand("Ubuntu", nil, wsl.RunCommandOptions{CaptureOutput: true}); err == nil {
+		t.Error("Expected error for empty command, got nil")
+	}
+}
+
+func TestWindowsPath(t *testing.T) {
+	got := wsl.WindowsPath("Ubuntu", "/tmp/autowsl-playbooks/playbooks/site.yml")
+	if want := `\\wsl$\Ubuntu\tmp\autowsl-playbooks\playbooks\site.yml`; got != want {
+		t.Errorf("WindowsPath() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/wsl"
+)
+
+func TestFindVHD(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := wsl.FindVHD(dir); err == nil {
+		t.Error("Expected error for a directory without a virtual disk")
+	}
+
+	named := filepath.Join(dir, "Ubuntu.vhdx")
+	if err := os.WriteFile(named, make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := wsl.FindVHD(dir); err != nil || got != named {
+		t.Errorf("FindVHD() = %q, %v; want %q", got, err, named)
+	}
+
+	ext4 := filepath.Join(dir, "ext4.vhdx")
+	if err := os.WriteFile(ext4, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := wsl.FindVHD(dir); err != nil || got != ext4 {
+		t.Errorf("Expected ext4.vhdx to be preferred, got %q, %v", got, err)
+	}
+
+	if size, err := wsl.GetVHDSize(named); err != nil || size != 2048 {
+		t.Errorf("GetVHDSize() = %d, %v; want 2048", size, err)
+	}
+	if _, err := wsl.GetVHDSize(filepath.Join(dir, "missing.vhdx")); err == nil {
+		t.Error("Expected error for a missing virtual disk")
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yuanjua/autowsl/internal/wslconfig"
+)
+
+func TestWSLConfigSetKeepsComments(t *testing.T) {
+	conf, err := wslconfig.Parse("# global settings\n[wsl2]\nmemory=4GB\n\n[experimental]\nsparseVhd=true\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if err := conf.Set("wsl2.memory", "8gb"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := conf.Set("wsl2.processors", "4"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := conf.Set("experimental.autoMemoryReclaim", "gradual"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	want := "# global settings\n[wsl2]\nmemory=8GB\nprocessors=4\n\n[experimental]\nsparseVhd=true\nautoMemoryReclaim=gradual\n"
+	if got := conf.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWSLConfigValidation(t *testing.T) {
+	conf := &wslconfig.Config{}
+	invalid := map[string]string{
+		"wsl2.memory":                    "lots",
+		"wsl2.processors":                "four",
+		"wsl2.localhostForwarding":       "maybe",
+		"experimental.autoMemoryReclaim": "sometimes",
+		"boot.systemd":                   "true",
+		"memory":                         "8GB",
+	}
+	for key, value := range invalid {
+		if err := conf.Set(key, value); err == nil {
+			t.Errorf("Expected error for %s=%s", key, value)
+		}
+	}
+
+	for _, size := range []string{"8GB", "4096MB", "0"} {
+		if err := conf.Set("wsl2.swap", size); err != nil {
+			t.Errorf("Expected swap=%s to be valid, got %v", size, err)
+		}
+	}
+}
+
+func TestWSLConfigSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".wslconfig")
+
+	conf, err := wslconfig.LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom missing file failed: %v", err)
+	}
+	if len(conf.Entries()) != 0 {
+		t.Fatalf("Expected empty config, got %v", conf.Entries())
+	}
+
+	if err := conf.Set("wsl2.memory", "8GB"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := conf.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	loaded, err := wslconfig.LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if v, ok := loaded.Get("WSL2.Memory"); !ok || v != "8GB" {
+		t.Errorf("Expected memory 8GB, got %q (found %v)", v, ok)
+	}
+	if !strings.HasPrefix(loaded.String(), "[wsl2]\n") {
+		t.Errorf("Expected file to start with [wsl2], got:\n%s", loaded.String())
+	}
+}